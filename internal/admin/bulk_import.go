@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+// BulkImportService runs bulk imports from files on the server and records each
+// run as a BulkImportJob
+type BulkImportService struct {
+	jobs    BulkImportJobRepository
+	schools SchoolImporter
+	now     func() time.Time
+}
+
+// NewBulkImportService creates a BulkImportService
+func NewBulkImportService(jobs BulkImportJobRepository, schools SchoolImporter) *BulkImportService {
+	return &BulkImportService{jobs: jobs, schools: schools, now: time.Now}
+}
+
+// GetJob returns a bulk import job by ID
+func (s *BulkImportService) GetJob(ctx context.Context, id string) (*BulkImportJob, error) {
+	return s.jobs.GetByID(ctx, id)
+}
+
+// ImportNCESSchools seeds or refreshes the school directory from an NCES CCD or
+// PSS CSV file, upserting schools by NCES ID. Invalid rows are skipped and listed
+// on the job. A file that cannot be read, or a storage error, fails the job; the
+// failed job is returned along with the error.
+func (s *BulkImportService) ImportNCESSchools(ctx context.Context, adminID, path string) (*BulkImportJob, error) {
+	job := &BulkImportJob{
+		ID:         shared.NewID(),
+		Kind:       BulkImportNCESSchools,
+		SourcePath: path,
+		Status:     BulkImportRunning,
+		StartedBy:  adminID,
+		Skipped:    []schooldirectory.NCESRowError{},
+		StartedAt:  s.now().UTC(),
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create bulk import job: %w", err)
+	}
+
+	runErr := s.runNCESImport(ctx, job)
+	job.finish(runErr, s.now().UTC())
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("save bulk import job: %w", err)
+	}
+
+	return job, runErr
+}
+
+// runNCESImport parses the job's file and upserts its schools, recording the
+// counts on the job
+func (s *BulkImportService) runNCESImport(ctx context.Context, job *BulkImportJob) error {
+	parsed, err := schooldirectory.ParseNCESFile(job.SourcePath)
+	if err != nil {
+		return err
+	}
+	job.Skipped = parsed.Skipped
+
+	result, err := s.schools.UpsertNCESSchools(ctx, parsed.Schools)
+	job.Created, job.Updated, job.Unchanged = result.Created, result.Updated, result.Unchanged
+	if err != nil {
+		return fmt.Errorf("import NCES schools: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+// memoryBulkImportJobRepository is an in-memory BulkImportJobRepository
+type memoryBulkImportJobRepository struct {
+	jobs map[string]*BulkImportJob
+}
+
+func (r *memoryBulkImportJobRepository) Create(_ context.Context, job *BulkImportJob) error {
+	clone := *job
+	r.jobs[job.ID] = &clone
+	return nil
+}
+
+func (r *memoryBulkImportJobRepository) GetByID(_ context.Context, id string) (*BulkImportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrBulkImportJobNotFound
+	}
+	clone := *job
+	return &clone, nil
+}
+
+func (r *memoryBulkImportJobRepository) Update(_ context.Context, job *BulkImportJob) error {
+	clone := *job
+	r.jobs[job.ID] = &clone
+	return nil
+}
+
+// stubSchoolImporter records the schools it is asked to upsert
+type stubSchoolImporter struct {
+	received []schooldirectory.School
+	err      error
+}
+
+func (s *stubSchoolImporter) UpsertNCESSchools(_ context.Context,
+	records []schooldirectory.School) (schooldirectory.NCESUpsertResult, error) {
+	s.received = records
+	return schooldirectory.NCESUpsertResult{Created: len(records)}, s.err
+}
+
+func newTestBulkImportService(importer SchoolImporter) (*BulkImportService, *memoryBulkImportJobRepository) {
+	jobs := &memoryBulkImportJobRepository{jobs: map[string]*BulkImportJob{}}
+	service := NewBulkImportService(jobs, importer)
+	service.now = func() time.Time { return testNow }
+	return service, jobs
+}
+
+func TestBulkImportService_ImportNCESSchools(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ccd_sch.csv")
+	file := "NCESSCH,SCH_NAME,LSTREET1,LCITY,LSTATE,LZIP,GSLO,GSHI\n" +
+		"170993000708,Lincoln Elementary School,100 Main St,Springfield,IL,62701,KG,05\n" +
+		"170993000709,Washington High,200 Oak St,Springfield,Illinois,62701,09,12\n" +
+		"170993000710,Grant Middle School,300 Elm St,Springfield,Illinoise,62701,06,08\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	importer := &stubSchoolImporter{}
+	service, _ := newTestBulkImportService(importer)
+
+	job, err := service.ImportNCESSchools(context.Background(), "admin-1", path)
+	if err != nil {
+		t.Fatalf("ImportNCESSchools() error = %v", err)
+	}
+	if job.Status != BulkImportSucceeded || job.Created != 2 || len(job.Skipped) != 1 || job.FinishedAt == nil {
+		t.Errorf("job = %+v", job)
+	}
+	if len(importer.received) != 2 || importer.received[0].NCESID != "170993000708" ||
+		importer.received[1].Address.State != "IL" {
+		t.Errorf("imported schools = %+v, want the state name stored as its code", importer.received)
+	}
+
+	saved, err := service.GetJob(context.Background(), job.ID)
+	if err != nil || saved.Status != BulkImportSucceeded || saved.StartedBy != "admin-1" {
+		t.Errorf("GetJob() = %+v, %v", saved, err)
+	}
+}
+
+func TestBulkImportService_ImportNCESSchoolsFails(t *testing.T) {
+	service, jobs := newTestBulkImportService(&stubSchoolImporter{})
+
+	job, err := service.ImportNCESSchools(context.Background(), "admin-1", filepath.Join(t.TempDir(), "missing.csv"))
+	if err == nil || job == nil || job.Status != BulkImportFailed || job.Error == "" {
+		t.Fatalf("ImportNCESSchools() = %+v, %v", job, err)
+	}
+	if jobs.jobs[job.ID].Status != BulkImportFailed {
+		t.Errorf("saved job status = %s", jobs.jobs[job.ID].Status)
+	}
+
+	path := filepath.Join(t.TempDir(), "pss.csv")
+	if err := os.WriteFile(path, []byte("PPIN,PINST,PCITY,PSTABB\nA1234567,St. Agnes,Springfield,IL\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	service, _ = newTestBulkImportService(&stubSchoolImporter{err: schooldirectory.ErrSchoolVersionConflict})
+	if job, err = service.ImportNCESSchools(context.Background(), "admin-1", path); !errors.Is(err, shared.ErrConflict) ||
+		job.Status != BulkImportFailed {
+		t.Errorf("ImportNCESSchools() = %+v, %v", job, err)
+	}
+}
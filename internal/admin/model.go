@@ -0,0 +1,100 @@
+// Package admin implements back-office operations such as teacher verification,
+// bulk imports and merging duplicate schools.
+package admin
+
+import (
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+)
+
+// BulkImportKind is the kind of data a bulk import loads
+type BulkImportKind string
+
+const (
+	// BulkImportNCESSchools seeds or refreshes the school directory from an NCES
+	// CCD public school or PSS private school file
+	BulkImportNCESSchools BulkImportKind = "nces_schools"
+)
+
+// BulkImportStatus is the progress of a bulk import job
+type BulkImportStatus string
+
+const (
+	BulkImportRunning   BulkImportStatus = "running"
+	BulkImportSucceeded BulkImportStatus = "succeeded"
+	BulkImportFailed    BulkImportStatus = "failed"
+)
+
+// BulkImportJob records one run of a bulk import from a local file
+type BulkImportJob struct {
+	ID   string         `json:"id"`
+	Kind BulkImportKind `json:"kind"`
+	// SourcePath is the file the import reads, on the server's file system
+	SourcePath string           `json:"source_path"`
+	Status     BulkImportStatus `json:"status"`
+	// StartedBy is the ID of the admin who ran the import
+	StartedBy string `json:"started_by"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+	// Skipped lists the rows of the file that were not imported
+	Skipped []schooldirectory.NCESRowError `json:"skipped"`
+	// Error is why a failed job stopped
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// finish marks the job succeeded, or failed with err
+func (j *BulkImportJob) finish(err error, now time.Time) {
+	j.Status = BulkImportSucceeded
+	if err != nil {
+		j.Status = BulkImportFailed
+		j.Error = err.Error()
+	}
+	j.FinishedAt = &now
+}
+
+// DuplicateScanStatus is the progress of a duplicate school scan
+type DuplicateScanStatus string
+
+const (
+	DuplicateScanRunning   DuplicateScanStatus = "running"
+	DuplicateScanSucceeded DuplicateScanStatus = "succeeded"
+	DuplicateScanFailed    DuplicateScanStatus = "failed"
+)
+
+// DuplicateScan records one run of the job that looks for duplicate schools in
+// the directory
+type DuplicateScan struct {
+	ID     string              `json:"id"`
+	Status DuplicateScanStatus `json:"status"`
+	// StartedBy is the ID of the admin who ran the scan
+	StartedBy string `json:"started_by"`
+	// Duplicates lists the likely duplicate pairs found, most similar first
+	Duplicates []schooldirectory.SchoolDuplicate `json:"duplicates"`
+	// Error is why a failed scan stopped
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// finish marks the scan succeeded, or failed with err
+func (s *DuplicateScan) finish(err error, now time.Time) {
+	s.Status = DuplicateScanSucceeded
+	if err != nil {
+		s.Status = DuplicateScanFailed
+		s.Error = err.Error()
+	}
+	s.FinishedAt = &now
+}
+
+// SchoolMerge is the outcome of merging a duplicate school into another
+type SchoolMerge struct {
+	Survivor    *schooldirectory.School `json:"survivor"`
+	DuplicateID string                  `json:"duplicate_id"`
+	// TeachersMoved counts the teachers moved to the survivor. Wishlists belong
+	// to teachers, so they moved along.
+	TeachersMoved int `json:"teachers_moved"`
+}
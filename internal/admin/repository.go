@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+var (
+	// ErrBulkImportJobNotFound is returned when a bulk import job does not exist
+	ErrBulkImportJobNotFound = fmt.Errorf("bulk import job %w", shared.ErrNotFound)
+	// ErrDuplicateScanNotFound is returned when a duplicate school scan does not
+	// exist
+	ErrDuplicateScanNotFound = fmt.Errorf("duplicate scan %w", shared.ErrNotFound)
+)
+
+// BulkImportJobRepository persists bulk import jobs
+type BulkImportJobRepository interface {
+	// Create stores a new job
+	Create(ctx context.Context, job *BulkImportJob) error
+	// GetByID returns the job, or an error wrapping ErrBulkImportJobNotFound
+	GetByID(ctx context.Context, id string) (*BulkImportJob, error)
+	// Update saves the job's status and results
+	Update(ctx context.Context, job *BulkImportJob) error
+}
+
+// SchoolImporter upserts schools parsed from NCES files. It is implemented by
+// schooldirectory.Service.
+type SchoolImporter interface {
+	UpsertNCESSchools(ctx context.Context, records []schooldirectory.School) (schooldirectory.NCESUpsertResult, error)
+}
+
+// DuplicateScanRepository persists duplicate school scans
+type DuplicateScanRepository interface {
+	// Create stores a new scan
+	Create(ctx context.Context, scan *DuplicateScan) error
+	// GetByID returns the scan, or an error wrapping ErrDuplicateScanNotFound
+	GetByID(ctx context.Context, id string) (*DuplicateScan, error)
+	// Update saves the scan's status and results
+	Update(ctx context.Context, scan *DuplicateScan) error
+}
+
+// SchoolMerger finds and merges duplicate schools. It is implemented by
+// schooldirectory.Service.
+type SchoolMerger interface {
+	FindDuplicateSchools(ctx context.Context) ([]schooldirectory.SchoolDuplicate, error)
+	MergeSchools(ctx context.Context, survivorID, duplicateID string) (*schooldirectory.School, error)
+}
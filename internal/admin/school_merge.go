@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// SchoolMergeService finds schools entered more than once and merges them, so a
+// school's teachers and wishlists are no longer split across records
+type SchoolMergeService struct {
+	scans    DuplicateScanRepository
+	schools  SchoolMerger
+	teachers teacherwishlist.TeacherRepository
+	now      func() time.Time
+}
+
+// NewSchoolMergeService creates a SchoolMergeService
+func NewSchoolMergeService(scans DuplicateScanRepository, schools SchoolMerger,
+	teachers teacherwishlist.TeacherRepository) *SchoolMergeService {
+	return &SchoolMergeService{scans: scans, schools: schools, teachers: teachers, now: time.Now}
+}
+
+// GetScan returns a duplicate scan by ID
+func (s *SchoolMergeService) GetScan(ctx context.Context, id string) (*DuplicateScan, error) {
+	return s.scans.GetByID(ctx, id)
+}
+
+// ScanDuplicateSchools looks for likely duplicate schools across the directory
+// and records the pairs found on a DuplicateScan for admins to review. A storage
+// error fails the scan; the failed scan is returned along with the error.
+func (s *SchoolMergeService) ScanDuplicateSchools(ctx context.Context, adminID string) (*DuplicateScan, error) {
+	scan := &DuplicateScan{
+		ID:         shared.NewID(),
+		Status:     DuplicateScanRunning,
+		StartedBy:  adminID,
+		Duplicates: []schooldirectory.SchoolDuplicate{},
+		StartedAt:  s.now().UTC(),
+	}
+	if err := s.scans.Create(ctx, scan); err != nil {
+		return nil, fmt.Errorf("create duplicate scan: %w", err)
+	}
+
+	duplicates, runErr := s.schools.FindDuplicateSchools(ctx)
+	if runErr == nil {
+		scan.Duplicates = duplicates
+	}
+	scan.finish(runErr, s.now().UTC())
+	if err := s.scans.Update(ctx, scan); err != nil {
+		return nil, fmt.Errorf("save duplicate scan: %w", err)
+	}
+
+	return scan, runErr
+}
+
+// MergeSchools merges the duplicate school into the survivor, keeping the
+// duplicate as an alias of the survivor, and moves every teacher of the
+// duplicate to the survivor. Teachers keep their verification, since it is the
+// same school. The school merge is saved first and is idempotent, so after a
+// failed teacher update the whole merge can be retried.
+func (s *SchoolMergeService) MergeSchools(ctx context.Context,
+	adminID, survivorID, duplicateID string) (*SchoolMerge, error) {
+	duplicateID = strings.TrimSpace(duplicateID)
+	survivor, err := s.schools.MergeSchools(ctx, survivorID, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &SchoolMerge{Survivor: survivor, DuplicateID: duplicateID}
+	teachers, err := s.teachers.ListBySchool(ctx, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("list teachers of merged school: %w", err)
+	}
+	now := s.now().UTC()
+	for _, teacher := range teachers {
+		teacher.MoveToMergedSchool(survivor.ID, adminID, now)
+		if err := s.teachers.Update(ctx, teacher); err != nil {
+			return nil, fmt.Errorf("move teacher %s to merged school: %w", teacher.ID, err)
+		}
+		merge.TeachersMoved++
+	}
+
+	return merge, nil
+}
@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// memoryDuplicateScanRepository is an in-memory DuplicateScanRepository
+type memoryDuplicateScanRepository struct {
+	scans map[string]*DuplicateScan
+}
+
+func (r *memoryDuplicateScanRepository) Create(_ context.Context, scan *DuplicateScan) error {
+	clone := *scan
+	r.scans[scan.ID] = &clone
+	return nil
+}
+
+func (r *memoryDuplicateScanRepository) GetByID(_ context.Context, id string) (*DuplicateScan, error) {
+	scan, ok := r.scans[id]
+	if !ok {
+		return nil, ErrDuplicateScanNotFound
+	}
+	clone := *scan
+	return &clone, nil
+}
+
+func (r *memoryDuplicateScanRepository) Update(_ context.Context, scan *DuplicateScan) error {
+	clone := *scan
+	r.scans[scan.ID] = &clone
+	return nil
+}
+
+// stubSchoolMerger returns fixed duplicates and records merges
+type stubSchoolMerger struct {
+	duplicates []schooldirectory.SchoolDuplicate
+	merged     [][2]string
+	err        error
+}
+
+func (s *stubSchoolMerger) FindDuplicateSchools(context.Context) ([]schooldirectory.SchoolDuplicate, error) {
+	return s.duplicates, s.err
+}
+
+func (s *stubSchoolMerger) MergeSchools(_ context.Context,
+	survivorID, duplicateID string) (*schooldirectory.School, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.merged = append(s.merged, [2]string{survivorID, duplicateID})
+	return &schooldirectory.School{ID: survivorID, Name: "Lincoln Elementary School"}, nil
+}
+
+func newTestSchoolMergeService(schools *stubSchoolMerger) (*SchoolMergeService, *memoryTeacherRepository) {
+	teachers := &memoryTeacherRepository{teachers: map[string]*teacherwishlist.Teacher{}}
+	for _, teacher := range []*teacherwishlist.Teacher{
+		{ID: "teacher-1", SchoolID: "duplicate", Name: "Alex Rivera", Email: "alex@example.edu", Version: 1,
+			ValidationState: teacherwishlist.ValidationState{Status: teacherwishlist.ValidationVerified}},
+		{ID: "teacher-2", SchoolID: "duplicate", Name: "Sam Lee", Email: "sam@example.edu", Version: 3,
+			ValidationState: teacherwishlist.ValidationState{Status: teacherwishlist.ValidationPendingReview}},
+		{ID: "teacher-3", SchoolID: "other", Name: "Kim Park", Email: "kim@example.edu", Version: 1},
+	} {
+		teachers.teachers[teacher.ID] = teacher
+	}
+
+	scans := &memoryDuplicateScanRepository{scans: map[string]*DuplicateScan{}}
+	service := NewSchoolMergeService(scans, schools, teachers)
+	service.now = func() time.Time { return testNow }
+	return service, teachers
+}
+
+func TestSchoolMergeService_ScanDuplicateSchools(t *testing.T) {
+	schools := &stubSchoolMerger{duplicates: []schooldirectory.SchoolDuplicate{
+		{SurvivorID: "survivor", DuplicateID: "duplicate", NameSimilarity: 1,
+			Reasons: []schooldirectory.DuplicateReason{schooldirectory.DuplicateSameAddress}},
+	}}
+	service, _ := newTestSchoolMergeService(schools)
+
+	scan, err := service.ScanDuplicateSchools(context.Background(), "admin-1")
+	if err != nil {
+		t.Fatalf("ScanDuplicateSchools() error = %v", err)
+	}
+	saved, err := service.GetScan(context.Background(), scan.ID)
+	if err != nil || saved.Status != DuplicateScanSucceeded || len(saved.Duplicates) != 1 ||
+		saved.StartedBy != "admin-1" || saved.FinishedAt == nil {
+		t.Errorf("GetScan() = %+v, %v", saved, err)
+	}
+
+	schools.err = errors.New("connection refused")
+	scan, err = service.ScanDuplicateSchools(context.Background(), "admin-1")
+	if err == nil || scan == nil || scan.Status != DuplicateScanFailed || scan.Error == "" {
+		t.Errorf("ScanDuplicateSchools() = %+v, %v, want a failed scan", scan, err)
+	}
+}
+
+func TestSchoolMergeService_MergeSchools(t *testing.T) {
+	schools := &stubSchoolMerger{}
+	service, teachers := newTestSchoolMergeService(schools)
+
+	merge, err := service.MergeSchools(context.Background(), "admin-1", "survivor", " duplicate ")
+	if err != nil {
+		t.Fatalf("MergeSchools() error = %v", err)
+	}
+	if merge.Survivor.ID != "survivor" || merge.DuplicateID != "duplicate" || merge.TeachersMoved != 2 {
+		t.Errorf("MergeSchools() = %+v", merge)
+	}
+
+	for _, id := range []string{"teacher-1", "teacher-2"} {
+		teacher := teachers.teachers[id]
+		if teacher.SchoolID != "survivor" || len(teacher.SchoolHistory) != 1 ||
+			teacher.SchoolHistory[0].FromSchoolID != "duplicate" || teacher.SchoolHistory[0].Actor != "admin-1" {
+			t.Errorf("teacher %s = %+v, want moved to the survivor with history", id, teacher)
+		}
+	}
+	if status := teachers.teachers["teacher-1"].ValidationState.Status; status != teacherwishlist.ValidationVerified {
+		t.Errorf("teacher-1 status = %s, a merge must keep verification", status)
+	}
+	if teachers.teachers["teacher-3"].SchoolID != "other" {
+		t.Errorf("teacher of another school was moved")
+	}
+
+	schools.err = schooldirectory.ErrSchoolMerged
+	_, err = service.MergeSchools(context.Background(), "admin-1", "survivor", "other")
+	if !errors.Is(err, shared.ErrConflict) {
+		t.Errorf("MergeSchools() error = %v, want conflict", err)
+	}
+	if teachers.teachers["teacher-3"].SchoolID != "other" {
+		t.Errorf("a failed merge moved teachers")
+	}
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// TeacherVerificationService lets admins review and manage teacher verification.
+// Every decision goes through the teacher's ValidationState state machine and is
+// recorded with the admin as the actor.
+type TeacherVerificationService struct {
+	teachers teacherwishlist.TeacherRepository
+	now      func() time.Time
+}
+
+// NewTeacherVerificationService creates a TeacherVerificationService
+func NewTeacherVerificationService(teachers teacherwishlist.TeacherRepository) *TeacherVerificationService {
+	return &TeacherVerificationService{
+		teachers: teachers,
+		now:      time.Now,
+	}
+}
+
+// PendingReviews returns the teachers waiting for review, longest waiting first
+func (s *TeacherVerificationService) PendingReviews(ctx context.Context) ([]*teacherwishlist.Teacher, error) {
+	return s.teachers.ListByValidationStatus(ctx, teacherwishlist.ValidationPendingReview)
+}
+
+// Verify approves a teacher who is pending review, or reinstates a suspended one
+func (s *TeacherVerificationService) Verify(ctx context.Context,
+	adminID, teacherID, note string) (*teacherwishlist.Teacher, error) {
+	return s.transition(ctx, adminID, teacherID, teacherwishlist.ValidationVerified, note)
+}
+
+// Reject declines a teacher's review; the teacher can resubmit
+func (s *TeacherVerificationService) Reject(ctx context.Context,
+	adminID, teacherID, reason string) (*teacherwishlist.Teacher, error) {
+	return s.transition(ctx, adminID, teacherID, teacherwishlist.ValidationRejected, reason)
+}
+
+// Suspend suspends a verified teacher, e.g. after a donor complaint
+func (s *TeacherVerificationService) Suspend(ctx context.Context,
+	adminID, teacherID, reason string) (*teacherwishlist.Teacher, error) {
+	return s.transition(ctx, adminID, teacherID, teacherwishlist.ValidationSuspended, reason)
+}
+
+// Expire marks a verified teacher's verification as lapsed
+func (s *TeacherVerificationService) Expire(ctx context.Context,
+	adminID, teacherID, reason string) (*teacherwishlist.Teacher, error) {
+	return s.transition(ctx, adminID, teacherID, teacherwishlist.ValidationExpired, reason)
+}
+
+// transition applies one validation status change and saves the teacher
+func (s *TeacherVerificationService) transition(ctx context.Context, adminID, teacherID string,
+	next teacherwishlist.ValidationStatus, reason string) (*teacherwishlist.Teacher, error) {
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := teacher.Transition(next, adminID, reason, s.now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.teachers.Update(ctx, teacher); err != nil {
+		return nil, fmt.Errorf("mark teacher %s: %w", next, err)
+	}
+
+	return teacher, nil
+}
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// memoryTeacherRepository is an in-memory teacherwishlist.TeacherRepository
+type memoryTeacherRepository struct {
+	teachers map[string]*teacherwishlist.Teacher
+}
+
+func (r *memoryTeacherRepository) Create(_ context.Context, teacher *teacherwishlist.Teacher) error {
+	r.teachers[teacher.ID] = teacher
+	return nil
+}
+
+func (r *memoryTeacherRepository) GetByID(_ context.Context, id string) (*teacherwishlist.Teacher, error) {
+	teacher, ok := r.teachers[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", teacherwishlist.ErrTeacherNotFound, id)
+	}
+	clone := *teacher
+	return &clone, nil
+}
+
+func (r *memoryTeacherRepository) GetByEmail(_ context.Context, email string) (*teacherwishlist.Teacher, error) {
+	for _, teacher := range r.teachers {
+		if teacher.Email == email {
+			clone := *teacher
+			return &clone, nil
+		}
+	}
+	return nil, teacherwishlist.ErrTeacherNotFound
+}
+
+func (r *memoryTeacherRepository) Update(_ context.Context, teacher *teacherwishlist.Teacher) error {
+	if r.teachers[teacher.ID].Version != teacher.Version {
+		return teacherwishlist.ErrTeacherVersionConflict
+	}
+	teacher.Version++
+	clone := *teacher
+	r.teachers[teacher.ID] = &clone
+	return nil
+}
+
+func (r *memoryTeacherRepository) ListByValidationStatus(_ context.Context,
+	status teacherwishlist.ValidationStatus) ([]*teacherwishlist.Teacher, error) {
+	teachers := []*teacherwishlist.Teacher{}
+	for _, teacher := range r.teachers {
+		if teacher.ValidationState.Status == status {
+			clone := *teacher
+			teachers = append(teachers, &clone)
+		}
+	}
+	return teachers, nil
+}
+
+func (r *memoryTeacherRepository) ListBySchool(_ context.Context,
+	schoolID string) ([]*teacherwishlist.Teacher, error) {
+	teachers := []*teacherwishlist.Teacher{}
+	for _, teacher := range r.teachers {
+		if teacher.SchoolID == schoolID {
+			clone := *teacher
+			teachers = append(teachers, &clone)
+		}
+	}
+	return teachers, nil
+}
+
+var testNow = time.Date(2025, time.August, 25, 15, 0, 0, 0, time.UTC)
+
+func newTestVerificationService(status teacherwishlist.ValidationStatus) *TeacherVerificationService {
+	repo := &memoryTeacherRepository{teachers: map[string]*teacherwishlist.Teacher{
+		"teacher-1": {
+			ID:              "teacher-1",
+			SchoolID:        "school-1",
+			Name:            "Alex Rivera",
+			Email:           "alex@example.edu",
+			ValidationState: teacherwishlist.ValidationState{Status: status},
+			Version:         1,
+		},
+	}}
+
+	service := NewTeacherVerificationService(repo)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestTeacherVerificationService_Verify(t *testing.T) {
+	ctx := context.Background()
+	service := newTestVerificationService(teacherwishlist.ValidationPendingReview)
+
+	pending, err := service.PendingReviews(ctx)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("PendingReviews() = %v, %v", pending, err)
+	}
+
+	teacher, err := service.Verify(ctx, "admin-1", "teacher-1", "confirmed with principal")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	last, _ := teacher.ValidationState.LastTransition()
+	want := teacherwishlist.ValidationTransition{
+		From:   teacherwishlist.ValidationPendingReview,
+		To:     teacherwishlist.ValidationVerified,
+		Actor:  "admin-1",
+		Reason: "confirmed with principal",
+		At:     testNow,
+	}
+	if !teacher.ValidationState.IsVerified() || last != want {
+		t.Errorf("last transition = %+v, want %+v", last, want)
+	}
+
+	if pending, _ := service.PendingReviews(ctx); len(pending) != 0 {
+		t.Errorf("PendingReviews() after verify = %d teachers", len(pending))
+	}
+}
+
+func TestTeacherVerificationService_IllegalTransitions(t *testing.T) {
+	ctx := context.Background()
+
+	service := newTestVerificationService(teacherwishlist.ValidationUnverified)
+	if _, err := service.Verify(ctx, "admin-1", "teacher-1", ""); !errors.Is(err, teacherwishlist.ErrInvalidValidationTransition) {
+		t.Errorf("Verify() of unverified teacher error = %v", err)
+	}
+
+	service = newTestVerificationService(teacherwishlist.ValidationPendingReview)
+	if _, err := service.Reject(ctx, "admin-1", "teacher-1", ""); !errors.Is(err, shared.ErrValidation) {
+		t.Errorf("Reject() without reason error = %v", err)
+	}
+	if _, err := service.Suspend(ctx, "admin-1", "teacher-1", "complaint"); !errors.Is(err, shared.ErrConflict) {
+		t.Errorf("Suspend() of pending teacher error = %v", err)
+	}
+	if _, err := service.Verify(ctx, "admin-1", "missing", ""); !errors.Is(err, shared.ErrNotFound) {
+		t.Errorf("Verify() of unknown teacher error = %v", err)
+	}
+}
+
+func TestTeacherVerificationService_SuspendAndExpire(t *testing.T) {
+	ctx := context.Background()
+	service := newTestVerificationService(teacherwishlist.ValidationVerified)
+
+	teacher, err := service.Suspend(ctx, "admin-2", "teacher-1", "donor complaint")
+	if err != nil || teacher.ValidationState.Status != teacherwishlist.ValidationSuspended {
+		t.Fatalf("Suspend() = %+v, %v", teacher, err)
+	}
+
+	if teacher, err = service.Verify(ctx, "admin-2", "teacher-1", "complaint resolved"); err != nil {
+		t.Fatalf("Verify() of suspended teacher error = %v", err)
+	}
+
+	if teacher, err = service.Expire(ctx, "system", "teacher-1", "school year ended"); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+	if teacher.ValidationState.Status != teacherwishlist.ValidationExpired || len(teacher.ValidationState.History) != 3 {
+		t.Errorf("ValidationState = %+v", teacher.ValidationState)
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// WishlistHistoryService lets admins read the revision history of any wishlist,
+// e.g. to see what a list looked like when a donor complained about it.
+// Teachers read their own history through teacherwishlist.Service.
+type WishlistHistoryService struct {
+	revisions teacherwishlist.RevisionReader
+}
+
+// NewWishlistHistoryService creates a WishlistHistoryService
+func NewWishlistHistoryService(revisions teacherwishlist.RevisionReader) *WishlistHistoryService {
+	return &WishlistHistoryService{revisions: revisions}
+}
+
+// ListRevisions returns every revision of a wishlist, oldest first
+func (s *WishlistHistoryService) ListRevisions(ctx context.Context,
+	wishlistID string) ([]*teacherwishlist.WishlistRevision, error) {
+	return s.revisions.ListRevisions(ctx, wishlistID)
+}
+
+// GetRevision returns one revision of a wishlist
+func (s *WishlistHistoryService) GetRevision(ctx context.Context, wishlistID string,
+	number int) (*teacherwishlist.WishlistRevision, error) {
+	return s.revisions.GetRevision(ctx, wishlistID, number)
+}
+
+// DiffRevisions compares two revisions of a wishlist
+func (s *WishlistHistoryService) DiffRevisions(ctx context.Context, wishlistID string,
+	from, to int) (teacherwishlist.WishlistDiff, error) {
+	return teacherwishlist.DiffStoredRevisions(ctx, s.revisions, wishlistID, from, to)
+}
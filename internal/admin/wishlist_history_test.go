@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// memoryRevisionReader is an in-memory teacherwishlist.RevisionReader
+type memoryRevisionReader struct {
+	revisions map[string][]*teacherwishlist.WishlistRevision
+}
+
+func (r *memoryRevisionReader) ListRevisions(_ context.Context,
+	wishlistID string) ([]*teacherwishlist.WishlistRevision, error) {
+	return r.revisions[wishlistID], nil
+}
+
+func (r *memoryRevisionReader) GetRevision(_ context.Context, wishlistID string,
+	number int) (*teacherwishlist.WishlistRevision, error) {
+	for _, revision := range r.revisions[wishlistID] {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s revision %d", teacherwishlist.ErrRevisionNotFound, wishlistID, number)
+}
+
+func TestWishlistHistoryService(t *testing.T) {
+	ctx := context.Background()
+	service := NewWishlistHistoryService(&memoryRevisionReader{
+		revisions: map[string][]*teacherwishlist.WishlistRevision{
+			"wishlist-1": {
+				{WishlistID: "wishlist-1", Number: 1, Source: teacherwishlist.RevisionCreated, Title: "Room 12"},
+				{WishlistID: "wishlist-1", Number: 2, Source: teacherwishlist.RevisionUpdated, Title: "Room 14"},
+			},
+		},
+	})
+
+	// Admins read any teacher's history without owning the wishlist
+	revisions, err := service.ListRevisions(ctx, "wishlist-1")
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("ListRevisions() = %d revisions, %v, want 2", len(revisions), err)
+	}
+	if revision, err := service.GetRevision(ctx, "wishlist-1", 1); err != nil || revision.Title != "Room 12" {
+		t.Errorf("GetRevision() = %+v, %v", revision, err)
+	}
+
+	diff, err := service.DiffRevisions(ctx, "wishlist-1", 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions() error = %v", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Field != "title" || diff.Changes[0].To != "Room 14" {
+		t.Errorf("diff.Changes = %+v", diff.Changes)
+	}
+
+	if _, err := service.DiffRevisions(ctx, "wishlist-1", 1, 9); !errors.Is(err, teacherwishlist.ErrRevisionNotFound) {
+		t.Errorf("DiffRevisions() with unknown revision error = %v, want ErrRevisionNotFound", err)
+	}
+}
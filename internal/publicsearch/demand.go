@@ -0,0 +1,98 @@
+package publicsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// DemandQuery narrows catalog item demand to one item and one area
+type DemandQuery struct {
+	// CatalogItemID limits the results to one catalog item; empty ranks all items
+	CatalogItemID string `json:"catalog_item_id,omitempty"`
+	// State is the two-letter code of the state the schools are in
+	State string `json:"state,omitempty"`
+	// County requires State, since county names repeat across states. The
+	// service lower-cases it and drops a trailing "county", so "Cook County"
+	// and "cook" are the same county.
+	County string `json:"county,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	// Now is the time wishlist visibility is evaluated at; the service sets it
+	Now time.Time `json:"-"`
+}
+
+// ItemDemand is how many classrooms still need a catalog item, as in "312
+// classrooms in Cook County need glue sticks"
+type ItemDemand struct {
+	CatalogItemID string                       `json:"catalog_item_id"`
+	Name          string                       `json:"name"`
+	Category      teacherwishlist.ItemCategory `json:"category"`
+	// Classrooms counts the teachers with a listed wishlist that still needs the
+	// item
+	Classrooms int `json:"classrooms"`
+	// QuantityNeeded is the number of units requested and not yet fulfilled or
+	// pledged
+	QuantityNeeded int `json:"quantity_needed"`
+}
+
+// DemandFinder is the storage used by DemandService. Implementations count only
+// wishlist items linked to the catalog with units still needed, on wishlists
+// that donors can find at query.Now: public, visible and of a verified teacher.
+// Results are ordered by Classrooms, then QuantityNeeded, highest first.
+type DemandFinder interface {
+	FindItemDemand(ctx context.Context, query DemandQuery) ([]ItemDemand, error)
+}
+
+// DemandService aggregates what classrooms need across wishlists
+type DemandService struct {
+	finder DemandFinder
+	now    func() time.Time
+}
+
+// NewDemandService creates a DemandService
+func NewDemandService(finder DemandFinder) *DemandService {
+	return &DemandService{finder: finder, now: time.Now}
+}
+
+// Demand returns the catalog items most needed by classrooms in an area
+func (s *DemandService) Demand(ctx context.Context, query DemandQuery) ([]ItemDemand, error) {
+	query.CatalogItemID = strings.TrimSpace(query.CatalogItemID)
+	query.State = strings.ToUpper(strings.TrimSpace(query.State))
+	query.County = normalizeCounty(query.County)
+
+	verr := &shared.ValidationError{}
+	if query.State != "" && !domain.IsValidStateCode(query.State) {
+		verr.Add("state", shared.CodeSearchStateInvalid, fmt.Sprintf("unknown state code %q", query.State))
+	}
+	if query.County != "" && query.State == "" {
+		verr.Add("county", shared.CodeSearchCountyRequiresState, "a county can only be searched within a state")
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = shared.DefaultSearchLimit
+	}
+	if query.Limit > shared.MaxSearchLimit {
+		query.Limit = shared.MaxSearchLimit
+	}
+	query.Now = s.now().UTC()
+
+	demand, err := s.finder.FindItemDemand(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("find item demand: %w", err)
+	}
+	return demand, nil
+}
+
+// normalizeCounty lower-cases a county name and drops a trailing "county"
+func normalizeCounty(county string) string {
+	county = strings.Join(strings.Fields(strings.ToLower(county)), " ")
+	return strings.TrimSpace(strings.TrimSuffix(county, "county"))
+}
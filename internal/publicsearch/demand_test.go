@@ -0,0 +1,70 @@
+package publicsearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/shared"
+)
+
+// stubDemandFinder records the last query and returns fixed demand
+type stubDemandFinder struct {
+	query  DemandQuery
+	demand []ItemDemand
+}
+
+func (f *stubDemandFinder) FindItemDemand(_ context.Context, query DemandQuery) ([]ItemDemand, error) {
+	f.query = query
+	return f.demand, nil
+}
+
+func newTestDemandService(finder *stubDemandFinder) *DemandService {
+	service := NewDemandService(finder)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestDemandService_Demand_NormalizesQuery(t *testing.T) {
+	finder := &stubDemandFinder{demand: []ItemDemand{{CatalogItemID: "glue", Name: "Glue Sticks", Classrooms: 312}}}
+
+	demand, err := newTestDemandService(finder).Demand(context.Background(), DemandQuery{
+		CatalogItemID: " glue ",
+		State:         " il",
+		County:        "  Cook   County ",
+		Limit:         1000,
+	})
+	if err != nil {
+		t.Fatalf("Demand() error = %v", err)
+	}
+	if len(demand) != 1 || demand[0].Classrooms != 312 {
+		t.Errorf("Demand() = %+v", demand)
+	}
+
+	want := DemandQuery{CatalogItemID: "glue", State: "IL", County: "cook", Limit: shared.MaxSearchLimit, Now: testNow}
+	if finder.query != want {
+		t.Errorf("query = %+v, want %+v", finder.query, want)
+	}
+}
+
+func TestDemandService_Demand_InvalidArea(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    DemandQuery
+		wantCode string
+	}{
+		{"unknown state", DemandQuery{State: "Illinois"}, shared.CodeSearchStateInvalid},
+		{"county without state", DemandQuery{County: "Cook"}, shared.CodeSearchCountyRequiresState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestDemandService(&stubDemandFinder{}).Demand(context.Background(), tt.query)
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) || !verr.HasCode(tt.wantCode) {
+				t.Errorf("Demand() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
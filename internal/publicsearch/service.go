@@ -0,0 +1,191 @@
+// Package publicsearch lets donors find classroom wishlists. Only public,
+// published lists of verified teachers that are within their publish window are
+// returned.
+package publicsearch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// WishlistSort orders wishlist search results
+type WishlistSort string
+
+const (
+	// SortNewest lists the most recently published wishlists first. It is the
+	// default.
+	SortNewest WishlistSort = "newest"
+	// SortClosestToFunded lists the wishlists with the highest percent funded
+	// first, so donors can help finish them. Fully funded lists come last.
+	SortClosestToFunded WishlistSort = "closest_to_funded"
+	// SortHighestNeed lists wishlists of schools with the highest free and
+	// reduced lunch rate first, then schoolwide Title I schools. Schools with no
+	// known rate come last.
+	SortHighestNeed WishlistSort = "highest_need"
+	// SortLargestEnrollment lists wishlists of the largest schools first
+	SortLargestEnrollment WishlistSort = "largest_enrollment"
+	// SortNearest lists wishlists of the schools nearest to the searched
+	// location first. It needs a radius search.
+	SortNearest WishlistSort = "nearest"
+)
+
+// IsValid returns true if s is a known sort order
+func (s WishlistSort) IsValid() bool {
+	switch s {
+	case SortNewest, SortClosestToFunded, SortHighestNeed, SortLargestEnrollment, SortNearest:
+		return true
+	}
+	return false
+}
+
+// WishlistQuery filters, orders and pages a wishlist search
+type WishlistQuery struct {
+	// Text matches the title, description and item names, case-insensitively
+	Text string `json:"text,omitempty"`
+	// DistrictID limits the results to teachers at schools of one district
+	DistrictID string `json:"district_id,omitempty"`
+	// SchoolTypes and LocaleTypes limit the results to schools of any of the
+	// listed types; empty lists match every school
+	SchoolTypes []schooldirectory.SchoolType `json:"school_types,omitempty"`
+	LocaleTypes []schooldirectory.LocaleType `json:"locale_types,omitempty"`
+	// Grade limits the results to schools whose grade span includes it
+	Grade schooldirectory.Grade `json:"grade,omitempty"`
+	// TitleIOnly limits the results to schools running a Title I program
+	TitleIOnly bool `json:"title_i_only,omitempty"`
+	// MinFRLPercent limits the results to schools with at least this free and
+	// reduced lunch rate; schools with no known rate never match
+	MinFRLPercent *float64 `json:"min_frl_percent,omitempty"`
+	// MinEnrollment and MaxEnrollment bound the number of students; 0 leaves a
+	// bound open
+	MinEnrollment int `json:"min_enrollment,omitempty"`
+	MaxEnrollment int `json:"max_enrollment,omitempty"`
+	// Near and Radius limit the results to schools within Radius of Near,
+	// expressed in Unit, which defaults to kilometers. Schools without a
+	// location never match.
+	Near   *domain.Location    `json:"near,omitempty"`
+	Radius float64             `json:"radius,omitempty"`
+	Unit   domain.DistanceUnit `json:"unit,omitempty"`
+	Sort   WishlistSort        `json:"sort,omitempty"`
+	Limit  int                 `json:"limit,omitempty"`
+	Offset int                 `json:"offset,omitempty"`
+	// Now is the time visibility is evaluated at; the service sets it
+	Now time.Time `json:"-"`
+}
+
+// WishlistFinder is the storage used by WishlistSearchService. Implementations
+// must only return public wishlists that are visible at query.Now and belong to
+// a verified teacher, within query.Radius of query.Near when it is set.
+type WishlistFinder interface {
+	FindPublishedWishlists(ctx context.Context, query WishlistQuery) ([]*teacherwishlist.Wishlist, error)
+}
+
+// WishlistSearchService searches published wishlists for donors
+type WishlistSearchService struct {
+	finder WishlistFinder
+	now    func() time.Time
+}
+
+// NewWishlistSearchService creates a WishlistSearchService
+func NewWishlistSearchService(finder WishlistFinder) *WishlistSearchService {
+	return &WishlistSearchService{finder: finder, now: time.Now}
+}
+
+// Search returns one page of wishlists donors can currently see. Each wishlist
+// carries its funding totals when encoded as JSON.
+func (s *WishlistSearchService) Search(ctx context.Context,
+	query WishlistQuery) ([]*teacherwishlist.Wishlist, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	query.DistrictID = strings.TrimSpace(query.DistrictID)
+	if query.Sort == "" {
+		query.Sort = SortNewest
+	}
+	if query.Unit == "" {
+		query.Unit = domain.Kilometers
+	}
+	if err := validateWishlistQuery(query); err != nil {
+		return nil, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = shared.DefaultSearchLimit
+	}
+	if query.Limit > shared.MaxSearchLimit {
+		query.Limit = shared.MaxSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	query.Now = s.now().UTC()
+
+	wishlists, err := s.finder.FindPublishedWishlists(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("search wishlists: %w", err)
+	}
+
+	// A list can expire between the query and the archive job, so never trust
+	// the finder alone to hide it
+	visible := make([]*teacherwishlist.Wishlist, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		if wishlist.IsListed(query.Now) {
+			visible = append(visible, wishlist)
+		}
+	}
+
+	return visible, nil
+}
+
+// validateWishlistQuery checks the sort order, the radius search and the school
+// attribute filters
+func validateWishlistQuery(query WishlistQuery) error {
+	verr := &shared.ValidationError{}
+	if !query.Sort.IsValid() {
+		verr.Add("sort", shared.CodeSearchSortInvalid, fmt.Sprintf("unknown sort order %q", query.Sort))
+	}
+	if query.Near != nil {
+		_, err := domain.NewLocation(query.Near.Latitude, query.Near.Longitude, query.Near.County, query.Near.Region)
+		verr.Merge("near", shared.CodeSearchNearInvalid, err)
+		if !query.Unit.IsValid() {
+			verr.Add("unit", shared.CodeSearchUnitInvalid, fmt.Sprintf("unknown distance unit %q", query.Unit))
+		} else if !(query.Radius > 0 && query.Unit.ToKilometers(query.Radius) <= shared.MaxSearchRadiusKm) {
+			verr.Add("radius", shared.CodeSearchRadiusRange, fmt.Sprintf("radius must be positive and at most %.0f %s",
+				math.Floor(query.Unit.FromKilometers(shared.MaxSearchRadiusKm)), query.Unit))
+		}
+	} else if query.Radius != 0 {
+		verr.Add("near", shared.CodeSearchNearInvalid, "a radius needs a location to search around")
+	}
+	if query.Sort == SortNearest && query.Near == nil {
+		verr.Add("sort", shared.CodeSearchSortRequiresNear, "sorting by distance needs a location to search around")
+	}
+	for _, schoolType := range query.SchoolTypes {
+		if !schoolType.IsValid() {
+			verr.Add("school_types", shared.CodeSearchSchoolTypeInvalid, fmt.Sprintf("unknown school type %q", schoolType))
+		}
+	}
+	for _, localeType := range query.LocaleTypes {
+		if !localeType.IsValid() {
+			verr.Add("locale_types", shared.CodeSearchLocaleTypeInvalid, fmt.Sprintf("unknown locale type %q", localeType))
+		}
+	}
+	if query.Grade != "" && !slices.Contains(schooldirectory.RankedGrades(), query.Grade) {
+		verr.Add("grade", shared.CodeSearchGradeInvalid, fmt.Sprintf("unknown grade %q", query.Grade))
+	}
+	// The negated form also rejects NaN
+	if query.MinFRLPercent != nil && !(*query.MinFRLPercent >= 0 && *query.MinFRLPercent <= 100) {
+		verr.Add("min_frl_percent", shared.CodeSearchFRLPercentRange,
+			"minimum free and reduced lunch rate must be between 0 and 100")
+	}
+	if query.MinEnrollment < 0 || query.MaxEnrollment < 0 ||
+		(query.MaxEnrollment > 0 && query.MinEnrollment > query.MaxEnrollment) {
+		verr.Add("max_enrollment", shared.CodeSearchEnrollmentRange,
+			"enrollment bounds must not be negative and the minimum must not exceed the maximum")
+	}
+	return verr.ErrOrNil()
+}
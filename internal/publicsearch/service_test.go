@@ -0,0 +1,205 @@
+package publicsearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// stubFinder records the last query and returns fixed wishlists
+type stubFinder struct {
+	query     WishlistQuery
+	wishlists []*teacherwishlist.Wishlist
+	err       error
+}
+
+func (f *stubFinder) FindPublishedWishlists(_ context.Context,
+	query WishlistQuery) ([]*teacherwishlist.Wishlist, error) {
+	f.query = query
+	return f.wishlists, f.err
+}
+
+var testNow = time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+
+func newTestService(finder *stubFinder) *WishlistSearchService {
+	service := NewWishlistSearchService(finder)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestWishlistSearchService_Search_NormalizesQuery(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              WishlistQuery
+		wantLimit, wantOff int
+	}{
+		{"defaults", WishlistQuery{}, shared.DefaultSearchLimit, 0},
+		{"capped", WishlistQuery{Limit: 1000, Offset: 40}, shared.MaxSearchLimit, 40},
+		{"negative offset", WishlistQuery{Limit: 5, Offset: -3}, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder := &stubFinder{}
+			tt.query.Text = "  crayons "
+			tt.query.DistrictID = " district-1 "
+			if _, err := newTestService(finder).Search(context.Background(), tt.query); err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if finder.query.Limit != tt.wantLimit || finder.query.Offset != tt.wantOff {
+				t.Errorf("query = %+v, want limit %d offset %d", finder.query, tt.wantLimit, tt.wantOff)
+			}
+			if finder.query.Sort != SortNewest {
+				t.Errorf("Sort = %q, want newest by default", finder.query.Sort)
+			}
+			if finder.query.Text != "crayons" || finder.query.DistrictID != "district-1" ||
+				!finder.query.Now.Equal(testNow) {
+				t.Errorf("query = %+v, want trimmed text and district at testNow", finder.query)
+			}
+		})
+	}
+}
+
+func TestWishlistSearchService_Search_OnlyVisible(t *testing.T) {
+	expired := testNow.Add(-time.Hour)
+	later := testNow.Add(time.Hour)
+	public := teacherwishlist.WishlistScope{Visibility: teacherwishlist.VisibilityPublic}
+	finder := &stubFinder{wishlists: []*teacherwishlist.Wishlist{
+		{ID: "open", Status: teacherwishlist.WishlistPublished, WishlistScope: public},
+		{
+			ID:            "unlisted",
+			Status:        teacherwishlist.WishlistPublished,
+			WishlistScope: teacherwishlist.WishlistScope{Visibility: teacherwishlist.VisibilityUnlisted},
+		},
+		{ID: "expired", Status: teacherwishlist.WishlistPublished, WishlistScope: public, ExpireAt: &expired},
+		{ID: "scheduled", Status: teacherwishlist.WishlistPublished, WishlistScope: public, PublishAt: &later},
+		{ID: "paused", Status: teacherwishlist.WishlistPaused, WishlistScope: public},
+	}}
+
+	wishlists, err := newTestService(finder).Search(context.Background(), WishlistQuery{})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(wishlists) != 1 || wishlists[0].ID != "open" {
+		t.Errorf("Search() = %+v, want only the open wishlist", wishlists)
+	}
+}
+
+func TestWishlistSearchService_Search_Sort(t *testing.T) {
+	finder := &stubFinder{}
+	service := newTestService(finder)
+
+	for _, sort := range []WishlistSort{SortClosestToFunded, SortHighestNeed, SortLargestEnrollment} {
+		if _, err := service.Search(context.Background(), WishlistQuery{Sort: sort}); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if finder.query.Sort != sort {
+			t.Errorf("Sort = %q, want %q", finder.query.Sort, sort)
+		}
+	}
+
+	_, err := service.Search(context.Background(), WishlistQuery{Sort: "cheapest"})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeSearchSortInvalid) {
+		t.Errorf("Search() error = %v, want %s", err, shared.CodeSearchSortInvalid)
+	}
+}
+
+func TestWishlistSearchService_Search_SchoolFilters(t *testing.T) {
+	minFRL, overFull := 75.0, 101.0
+	tests := []struct {
+		name      string
+		query     WishlistQuery
+		wantCodes []string
+	}{
+		{"valid", WishlistQuery{
+			SchoolTypes: []schooldirectory.SchoolType{schooldirectory.SchoolTypePublic, schooldirectory.SchoolTypeCharter},
+			LocaleTypes: []schooldirectory.LocaleType{schooldirectory.LocaleRural}, Grade: schooldirectory.GradeKindergarten,
+			TitleIOnly: true, MinFRLPercent: &minFRL, MinEnrollment: 100, MaxEnrollment: 500,
+		}, nil},
+		{"open enrollment maximum", WishlistQuery{MinEnrollment: 1000}, nil},
+		{"invalid", WishlistQuery{
+			SchoolTypes: []schooldirectory.SchoolType{"online"}, LocaleTypes: []schooldirectory.LocaleType{"remote"},
+			Grade: schooldirectory.GradeUngraded, MinFRLPercent: &overFull, MinEnrollment: 500, MaxEnrollment: 100,
+		}, []string{shared.CodeSearchSchoolTypeInvalid, shared.CodeSearchLocaleTypeInvalid, shared.CodeSearchGradeInvalid,
+			shared.CodeSearchFRLPercentRange, shared.CodeSearchEnrollmentRange}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder := &stubFinder{}
+			_, err := newTestService(finder).Search(context.Background(), tt.query)
+			if len(tt.wantCodes) == 0 {
+				if err != nil {
+					t.Errorf("Search() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Search() error = %v, want *shared.ValidationError", err)
+			}
+			for _, code := range tt.wantCodes {
+				if !verr.HasCode(code) {
+					t.Errorf("Search() error = %v, want code %s", err, code)
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistSearchService_Search_Near(t *testing.T) {
+	springfield := domain.Location{Latitude: 39.7817, Longitude: -89.6501}
+	finder := &stubFinder{}
+	service := newTestService(finder)
+
+	_, err := service.Search(context.Background(), WishlistQuery{Near: &springfield, Radius: 10, Sort: SortNearest})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if finder.query.Near == nil || *finder.query.Near != springfield || finder.query.Radius != 10 ||
+		finder.query.Unit != domain.Kilometers {
+		t.Errorf("finder query near = %v within %v %s, want springfield within 10 km",
+			finder.query.Near, finder.query.Radius, finder.query.Unit)
+	}
+
+	tests := []struct {
+		name     string
+		query    WishlistQuery
+		wantCode string
+	}{
+		{"invalid location", WishlistQuery{Near: &domain.Location{Latitude: 91}, Radius: 10},
+			shared.CodeLocationLatitudeRange},
+		{"missing radius", WishlistQuery{Near: &springfield}, shared.CodeSearchRadiusRange},
+		{"radius too large", WishlistQuery{Near: &springfield, Radius: 200, Unit: domain.Miles},
+			shared.CodeSearchRadiusRange},
+		{"invalid unit", WishlistQuery{Near: &springfield, Radius: 10, Unit: "ft"}, shared.CodeSearchUnitInvalid},
+		{"radius without location", WishlistQuery{Radius: 10}, shared.CodeSearchNearInvalid},
+		{"nearest without location", WishlistQuery{Sort: SortNearest}, shared.CodeSearchSortRequiresNear},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Search(context.Background(), tt.query)
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) || !verr.HasCode(tt.wantCode) {
+				t.Errorf("Search() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWishlistSearchService_Search_Error(t *testing.T) {
+	errStorage := errors.New("connection refused")
+	finder := &stubFinder{err: errStorage}
+
+	if _, err := newTestService(finder).Search(context.Background(), WishlistQuery{}); !errors.Is(err, errStorage) {
+		t.Errorf("Search() error = %v, want wrapped storage error", err)
+	}
+}
@@ -0,0 +1,152 @@
+package schooldirectory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// leaIDRegex matches a 7-digit NCES LEAID
+var leaIDRegex = regexp.MustCompile(`^\d{7}$`)
+
+// StateAgency is a state education agency (SEA), the parent of every district
+// in its state. There is at most one per state.
+type StateAgency struct {
+	ID string `json:"id"`
+	// State is the two-letter code of the state the agency governs
+	State   string         `json:"state"`
+	Name    string         `json:"name"`
+	Address domain.Address `json:"address"`
+	// Boundary is the outline of the state, if it is known
+	Boundary *domain.Polygon `json:"boundary,omitempty"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the agency's fields, returning a *shared.ValidationError
+// listing all problems
+func (a *StateAgency) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if !domain.IsValidStateCode(a.State) {
+		verr.Add("state", shared.CodeStateAgencyStateInvalid, fmt.Sprintf("unknown state code %q", a.State))
+	}
+
+	if strings.TrimSpace(a.Name) == "" {
+		verr.Add("name", shared.CodeStateAgencyNameRequired, "name is required")
+	}
+
+	validateParentAddress(verr, a.Address, shared.CodeStateAgencyAddressInvalid)
+	validateBoundary(verr, a.Boundary, shared.CodeStateAgencyBoundaryInvalid)
+
+	return verr.ErrOrNil()
+}
+
+// District is a local education agency (LEA), the parent of its schools
+type District struct {
+	ID string `json:"id"`
+	// StateAgencyID is the StateAgency of the district's state
+	StateAgencyID string `json:"state_agency_id"`
+	// NCESID is the district's NCES LEAID. Districts entered by hand have none.
+	NCESID  string         `json:"nces_id,omitempty"`
+	Name    string         `json:"name"`
+	Address domain.Address `json:"address"`
+	// Boundary is the outline of the district's attendance area, if it is known
+	Boundary *domain.Polygon `json:"boundary,omitempty"`
+	// AdminIDs are the users who administer the district and can see all of its
+	// schools
+	AdminIDs []string `json:"admin_ids"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the district's fields, returning a *shared.ValidationError
+// listing all problems
+func (d *District) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if strings.TrimSpace(d.StateAgencyID) == "" {
+		verr.Add("state_agency_id", shared.CodeDistrictStateAgencyRequired, "state agency is required")
+	}
+
+	if strings.TrimSpace(d.Name) == "" {
+		verr.Add("name", shared.CodeDistrictNameRequired, "name is required")
+	}
+
+	if d.NCESID != "" && !leaIDRegex.MatchString(d.NCESID) {
+		verr.Add("nces_id", shared.CodeDistrictNCESIDFormat, "NCES ID must be a 7-digit LEAID")
+	}
+
+	validateParentAddress(verr, d.Address, shared.CodeDistrictAddressInvalid)
+	validateBoundary(verr, d.Boundary, shared.CodeDistrictBoundaryInvalid)
+
+	return verr.ErrOrNil()
+}
+
+// HasAdmin returns true if the user administers the district
+func (d *District) HasAdmin(adminID string) bool {
+	for _, id := range d.AdminIDs {
+		if id == adminID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddAdmin lets a user administer the district and returns true if they did not
+// already
+func (d *District) AddAdmin(adminID string) bool {
+	if d.HasAdmin(adminID) {
+		return false
+	}
+	d.AdminIDs = append(d.AdminIDs, adminID)
+	return true
+}
+
+// Covers returns true if the location is inside the district's boundary. A
+// district without a boundary covers nothing.
+func (d *District) Covers(location domain.Location) bool {
+	return d.Boundary != nil && d.Boundary.Contains(location)
+}
+
+// parentAddress rebuilds an agency or district address with domain.NewAddress,
+// so a state given by its full name is stored as its code. An invalid address
+// is returned as given, for Validate to report.
+func parentAddress(address domain.Address) domain.Address {
+	if address.IsEmpty() {
+		return address
+	}
+	built, err := domain.NewAddress(address.Street, address.City, address.State, address.ZipCode, address.Location)
+	if err != nil {
+		return address
+	}
+	return built
+}
+
+// validateParentAddress reports an invalid agency or district address under
+// "address"
+func validateParentAddress(verr *shared.ValidationError, address domain.Address, code string) {
+	if address.IsEmpty() {
+		return
+	}
+	_, err := domain.NewAddress(address.Street, address.City, address.State, address.ZipCode, address.Location)
+	verr.Merge("address", code, err)
+}
+
+// validateBoundary reports an invalid boundary polygon under "boundary"
+func validateBoundary(verr *shared.ValidationError, boundary *domain.Polygon, code string) {
+	if boundary == nil {
+		return
+	}
+	if _, err := domain.NewPolygon(boundary.Exterior, boundary.Holes...); err != nil {
+		verr.Add("boundary", code, err.Error())
+	}
+}
@@ -0,0 +1,181 @@
+package schooldirectory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// StateAgencyInput holds the details of a state education agency
+type StateAgencyInput struct {
+	State    string          `json:"state"`
+	Name     string          `json:"name"`
+	Address  domain.Address  `json:"address"`
+	Boundary *domain.Polygon `json:"boundary,omitempty"`
+}
+
+// DistrictInput holds the details of a district
+type DistrictInput struct {
+	StateAgencyID string          `json:"state_agency_id"`
+	NCESID        string          `json:"nces_id,omitempty"`
+	Name          string          `json:"name"`
+	Address       domain.Address  `json:"address"`
+	Boundary      *domain.Polygon `json:"boundary,omitempty"`
+}
+
+// CreateStateAgency adds the education agency of a state
+func (s *Service) CreateStateAgency(ctx context.Context, input StateAgencyInput) (*StateAgency, error) {
+	now := s.now().UTC()
+	agency := &StateAgency{
+		ID:        shared.NewID(),
+		State:     stateCode(strings.TrimSpace(input.State)),
+		Name:      strings.TrimSpace(input.Name),
+		Address:   parentAddress(input.Address),
+		Boundary:  input.Boundary,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := agency.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.agencies.Create(ctx, agency); err != nil {
+		return nil, fmt.Errorf("create state agency: %w", err)
+	}
+
+	return agency, nil
+}
+
+// GetStateAgency returns a state education agency by ID
+func (s *Service) GetStateAgency(ctx context.Context, id string) (*StateAgency, error) {
+	return s.agencies.GetByID(ctx, id)
+}
+
+// CreateDistrict adds a district under a state agency. A district address must
+// be in the agency's state.
+func (s *Service) CreateDistrict(ctx context.Context, input DistrictInput) (*District, error) {
+	now := s.now().UTC()
+	district := &District{
+		ID:            shared.NewID(),
+		StateAgencyID: strings.TrimSpace(input.StateAgencyID),
+		NCESID:        strings.TrimSpace(input.NCESID),
+		Name:          strings.TrimSpace(input.Name),
+		Address:       parentAddress(input.Address),
+		Boundary:      input.Boundary,
+		AdminIDs:      []string{},
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := district.Validate(); err != nil {
+		return nil, err
+	}
+
+	agency, err := s.agencies.GetByID(ctx, district.StateAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if district.Address.State != "" && district.Address.State != agency.State {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "address.state",
+			Code:    shared.CodeDistrictStateMismatch,
+			Message: fmt.Sprintf("a district of the %s agency must be in %s", agency.State, agency.State),
+		})
+	}
+
+	if err := s.districts.Create(ctx, district); err != nil {
+		return nil, fmt.Errorf("create district: %w", err)
+	}
+
+	return district, nil
+}
+
+// GetDistrict returns a district by ID
+func (s *Service) GetDistrict(ctx context.Context, id string) (*District, error) {
+	return s.districts.GetByID(ctx, id)
+}
+
+// ListDistricts returns the districts of a state agency ordered by name
+func (s *Service) ListDistricts(ctx context.Context, stateAgencyID string) ([]*District, error) {
+	if _, err := s.agencies.GetByID(ctx, stateAgencyID); err != nil {
+		return nil, err
+	}
+	return s.districts.ListByStateAgency(ctx, stateAgencyID)
+}
+
+// AddDistrictAdmin lets a user administer a district
+func (s *Service) AddDistrictAdmin(ctx context.Context, districtID, adminID string) (*District, error) {
+	district, err := s.districts.GetByID(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	adminID = strings.TrimSpace(adminID)
+	if adminID == "" || !district.AddAdmin(adminID) {
+		return district, nil
+	}
+
+	district.UpdatedAt = s.now().UTC()
+	if err := s.districts.Update(ctx, district); err != nil {
+		return nil, fmt.Errorf("add district admin: %w", err)
+	}
+
+	return district, nil
+}
+
+// AssignSchoolToDistrict makes a school part of a district. A school merged
+// into another assigns the surviving school. A school with an address must be in
+// the district's state.
+func (s *Service) AssignSchoolToDistrict(ctx context.Context, schoolID, districtID string) (*School, error) {
+	school, err := s.GetSchool(ctx, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	district, err := s.districts.GetByID(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if school.DistrictID == district.ID {
+		return school, nil
+	}
+
+	agency, err := s.agencies.GetByID(ctx, district.StateAgencyID)
+	if err != nil {
+		return nil, err
+	}
+	if state := strings.TrimSpace(school.Address.State); state != "" && stateCode(state) != agency.State {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "address.state",
+			Code:    shared.CodeDistrictSchoolState,
+			Message: fmt.Sprintf("a school of a %s district must be in %s", agency.State, agency.State),
+		})
+	}
+
+	school.setDistrict(district)
+	school.UpdatedAt = s.now().UTC()
+	if err := s.schools.Update(ctx, school); err != nil {
+		return nil, fmt.Errorf("assign school to district: %w", err)
+	}
+
+	return school, nil
+}
+
+// ListDistrictSchools returns every school of a district to one of its admins
+func (s *Service) ListDistrictSchools(ctx context.Context, adminID, districtID string) ([]*School, error) {
+	district, err := s.districts.GetByID(ctx, districtID)
+	if err != nil {
+		return nil, err
+	}
+	if !district.HasAdmin(adminID) {
+		return nil, ErrNotDistrictAdmin
+	}
+
+	return s.schools.ListByDistrict(ctx, district.ID)
+}
@@ -0,0 +1,228 @@
+package schooldirectory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// memoryDistrictRepository is an in-memory DistrictRepository
+type memoryDistrictRepository struct {
+	districts map[string]*District
+}
+
+func newMemoryDistrictRepository(districts ...*District) *memoryDistrictRepository {
+	repo := &memoryDistrictRepository{districts: map[string]*District{}}
+	for _, district := range districts {
+		repo.districts[district.ID] = district
+	}
+	return repo
+}
+
+func (r *memoryDistrictRepository) Create(_ context.Context, district *District) error {
+	if _, err := r.GetByNCESID(context.Background(), district.NCESID); district.NCESID != "" && err == nil {
+		return ErrDistrictNCESIDTaken
+	}
+	clone := *district
+	r.districts[district.ID] = &clone
+	return nil
+}
+
+func (r *memoryDistrictRepository) GetByID(_ context.Context, id string) (*District, error) {
+	district, ok := r.districts[id]
+	if !ok {
+		return nil, ErrDistrictNotFound
+	}
+	clone := *district
+	clone.AdminIDs = append([]string{}, district.AdminIDs...)
+	return &clone, nil
+}
+
+func (r *memoryDistrictRepository) GetByNCESID(ctx context.Context, ncesID string) (*District, error) {
+	for id, district := range r.districts {
+		if district.NCESID == ncesID {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, ErrDistrictNotFound
+}
+
+func (r *memoryDistrictRepository) Update(_ context.Context, district *District) error {
+	if r.districts[district.ID].Version != district.Version {
+		return ErrDistrictVersionConflict
+	}
+	district.Version++
+	clone := *district
+	r.districts[district.ID] = &clone
+	return nil
+}
+
+func (r *memoryDistrictRepository) ListByStateAgency(_ context.Context, stateAgencyID string) ([]*District, error) {
+	districts := []*District{}
+	for _, district := range r.districts {
+		if district.StateAgencyID == stateAgencyID {
+			clone := *district
+			districts = append(districts, &clone)
+		}
+	}
+	sort.Slice(districts, func(i, j int) bool { return districts[i].Name < districts[j].Name })
+	return districts, nil
+}
+
+// memoryStateAgencyRepository is an in-memory StateAgencyRepository
+type memoryStateAgencyRepository struct {
+	agencies map[string]*StateAgency
+}
+
+func newMemoryStateAgencyRepository() *memoryStateAgencyRepository {
+	return &memoryStateAgencyRepository{agencies: map[string]*StateAgency{}}
+}
+
+func (r *memoryStateAgencyRepository) Create(ctx context.Context, agency *StateAgency) error {
+	if _, err := r.GetByState(ctx, agency.State); err == nil {
+		return ErrStateAgencyTaken
+	}
+	clone := *agency
+	r.agencies[agency.ID] = &clone
+	return nil
+}
+
+func (r *memoryStateAgencyRepository) GetByID(_ context.Context, id string) (*StateAgency, error) {
+	agency, ok := r.agencies[id]
+	if !ok {
+		return nil, ErrStateAgencyNotFound
+	}
+	clone := *agency
+	return &clone, nil
+}
+
+func (r *memoryStateAgencyRepository) GetByState(_ context.Context, state string) (*StateAgency, error) {
+	for _, agency := range r.agencies {
+		if agency.State == state {
+			clone := *agency
+			return &clone, nil
+		}
+	}
+	return nil, ErrStateAgencyNotFound
+}
+
+func newTestDistrictService() (*Service, *memorySchoolRepository) {
+	schools := &memorySchoolRepository{schools: map[string]*School{
+		"school-1": {ID: "school-1", Name: "Lincoln Elementary", Version: 1},
+		"school-2": {ID: "school-2", Name: "Adams Middle", Version: 1},
+	}}
+	return NewService(schools, newMemoryDistrictRepository(), newMemoryStateAgencyRepository()), schools
+}
+
+func TestService_DistrictHierarchy(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestDistrictService()
+
+	agency, err := service.CreateStateAgency(ctx, StateAgencyInput{State: " il ", Name: "Illinois State Board of Education",
+		Address: domain.Address{Street: "100 N 1st St", City: "Springfield", State: "Illinois", ZipCode: "62777"}})
+	if err != nil || agency.State != "IL" || agency.Address.State != "IL" {
+		t.Fatalf("CreateStateAgency() = %+v, %v", agency, err)
+	}
+	if _, err := service.CreateStateAgency(ctx, StateAgencyInput{State: "IL", Name: "ISBE"}); !errors.Is(err, shared.ErrConflict) {
+		t.Errorf("second CreateStateAgency() error = %v, want ErrConflict", err)
+	}
+
+	district, err := service.CreateDistrict(ctx, DistrictInput{
+		StateAgencyID: agency.ID, NCESID: "1709930", Name: "Springfield SD 186",
+		Address: domain.Address{Street: "1900 W Monroe St", City: "Springfield", State: "IL", ZipCode: "62704"},
+	})
+	if err != nil || district.Version != 1 {
+		t.Fatalf("CreateDistrict() = %+v, %v", district, err)
+	}
+
+	// A state given by name is stored as its code before it is compared
+	named, err := service.CreateDistrict(ctx, DistrictInput{
+		StateAgencyID: agency.ID, Name: "Chicago Public Schools",
+		Address: domain.Address{City: "Chicago", State: "Illinois"},
+	})
+	if err != nil || named.Address.State != "IL" {
+		t.Fatalf("CreateDistrict() with a state name = %+v, %v", named, err)
+	}
+
+	_, err = service.CreateDistrict(ctx, DistrictInput{
+		StateAgencyID: agency.ID, Name: "Boston Public Schools",
+		Address: domain.Address{City: "Boston", State: "MA"},
+	})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeDistrictStateMismatch) {
+		t.Errorf("CreateDistrict() in another state error = %v", err)
+	}
+	if _, err := service.CreateDistrict(ctx, DistrictInput{StateAgencyID: "missing", Name: "Nowhere"}); !errors.Is(err, shared.ErrNotFound) {
+		t.Errorf("CreateDistrict() of unknown agency error = %v", err)
+	}
+
+	districts, err := service.ListDistricts(ctx, agency.ID)
+	if err != nil || len(districts) != 2 {
+		t.Errorf("ListDistricts() = %v, %v", districts, err)
+	}
+}
+
+func TestService_ListDistrictSchools(t *testing.T) {
+	ctx := context.Background()
+	service, schools := newTestDistrictService()
+	agency, _ := service.CreateStateAgency(ctx, StateAgencyInput{State: "IL", Name: "ISBE"})
+	district, _ := service.CreateDistrict(ctx, DistrictInput{StateAgencyID: agency.ID, NCESID: "1709930",
+		Name: "Springfield SD 186"})
+
+	school, err := service.AssignSchoolToDistrict(ctx, "school-1", district.ID)
+	if err != nil || school.DistrictID != district.ID || school.DistrictNCESID != "1709930" ||
+		schools.schools["school-1"].Version != 2 {
+		t.Fatalf("AssignSchoolToDistrict() = %+v, %v", school, err)
+	}
+
+	if _, err := service.ListDistrictSchools(ctx, "admin-1", district.ID); !errors.Is(err, shared.ErrForbidden) {
+		t.Errorf("ListDistrictSchools() by non-admin error = %v, want ErrForbidden", err)
+	}
+
+	if _, err := service.AddDistrictAdmin(ctx, district.ID, "admin-1"); err != nil {
+		t.Fatalf("AddDistrictAdmin() error = %v", err)
+	}
+	listed, err := service.ListDistrictSchools(ctx, "admin-1", district.ID)
+	if err != nil || len(listed) != 1 || listed[0].ID != "school-1" {
+		t.Errorf("ListDistrictSchools() = %v, %v", listed, err)
+	}
+}
+
+func TestService_AssignSchoolToDistrict(t *testing.T) {
+	ctx := context.Background()
+	service, schools := newTestDistrictService()
+	schools.schools["school-1"].Address = domain.Address{City: "Springfield", State: "IL"}
+	schools.schools["school-3"] = &School{ID: "school-3", Name: "Lincoln Elem", MergedIntoID: "school-1", Version: 1}
+	schools.schools["school-4"] = &School{ID: "school-4", Name: "Boston Latin",
+		Address: domain.Address{City: "Boston", State: "MA"}, Version: 1}
+
+	agency, _ := service.CreateStateAgency(ctx, StateAgencyInput{State: "IL", Name: "ISBE"})
+	springfield, _ := service.CreateDistrict(ctx, DistrictInput{StateAgencyID: agency.ID, NCESID: "1709930",
+		Name: "Springfield SD 186"})
+	unlisted, _ := service.CreateDistrict(ctx, DistrictInput{StateAgencyID: agency.ID, Name: "Sangamon Co-op"})
+
+	// A merged school is resolved to the school it was merged into
+	school, err := service.AssignSchoolToDistrict(ctx, "school-3", springfield.ID)
+	if err != nil || school.ID != "school-1" || schools.schools["school-3"].DistrictID != "" {
+		t.Fatalf("AssignSchoolToDistrict() of a merged school = %+v, %v, want school-1 assigned", school, err)
+	}
+
+	// Moving to a district without an NCES ID drops the former district's LEAID
+	school, err = service.AssignSchoolToDistrict(ctx, "school-1", unlisted.ID)
+	if err != nil || school.DistrictID != unlisted.ID || school.DistrictNCESID != "" {
+		t.Errorf("AssignSchoolToDistrict() = %+v, %v, want no district NCES ID", school, err)
+	}
+
+	_, err = service.AssignSchoolToDistrict(ctx, "school-4", springfield.ID)
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeDistrictSchoolState) {
+		t.Errorf("AssignSchoolToDistrict() in another state error = %v, want %s", err, shared.CodeDistrictSchoolState)
+	}
+	if schools.schools["school-4"].DistrictID != "" {
+		t.Errorf("school in another state was assigned")
+	}
+}
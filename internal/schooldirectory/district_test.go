@@ -0,0 +1,61 @@
+package schooldirectory
+
+import (
+	"errors"
+	"testing"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+func TestStateAgency_Validate(t *testing.T) {
+	valid := StateAgency{State: "IL", Name: "Illinois State Board of Education"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := StateAgency{State: "XX", Boundary: &domain.Polygon{Exterior: []domain.Location{{Latitude: 40}}}}
+	var verr *shared.ValidationError
+	if err := invalid.Validate(); !errors.As(err, &verr) || !verr.HasCode(shared.CodeStateAgencyStateInvalid) ||
+		!verr.HasCode(shared.CodeStateAgencyNameRequired) || !verr.HasCode(shared.CodeStateAgencyBoundaryInvalid) {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestDistrict_Validate(t *testing.T) {
+	valid := District{StateAgencyID: "agency-il", NCESID: "1709930", Name: "Springfield SD 186",
+		Address: domain.Address{Street: "1900 W Monroe St", City: "Springfield", State: "IL", ZipCode: "62704"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := District{NCESID: "17-09930", Address: domain.Address{City: "Springfield", State: "ZZ"}}
+	var verr *shared.ValidationError
+	if err := invalid.Validate(); !errors.As(err, &verr) || !verr.HasCode(shared.CodeDistrictStateAgencyRequired) ||
+		!verr.HasCode(shared.CodeDistrictNameRequired) || !verr.HasCode(shared.CodeDistrictNCESIDFormat) ||
+		!verr.HasCode(shared.CodeAddressStateUnknown) {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestDistrict_AdminsAndCoverage(t *testing.T) {
+	boundary, err := domain.NewPolygon([]domain.Location{
+		{Latitude: 39.7, Longitude: -89.8}, {Latitude: 39.7, Longitude: -89.5},
+		{Latitude: 39.9, Longitude: -89.5}, {Latitude: 39.9, Longitude: -89.8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	district := District{Boundary: &boundary}
+
+	if !district.AddAdmin("admin-1") || district.AddAdmin("admin-1") || !district.HasAdmin("admin-1") {
+		t.Errorf("AdminIDs = %v", district.AdminIDs)
+	}
+	if !district.Covers(domain.Location{Latitude: 39.8, Longitude: -89.65}) ||
+		district.Covers(domain.Location{Latitude: 41.88, Longitude: -87.63}) {
+		t.Error("Covers() does not follow the boundary")
+	}
+	if (&District{}).Covers(domain.Location{Latitude: 39.8, Longitude: -89.65}) {
+		t.Error("Covers() of a district without a boundary = true")
+	}
+}
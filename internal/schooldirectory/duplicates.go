@@ -0,0 +1,260 @@
+package schooldirectory
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// DuplicateReason is a piece of evidence, besides a similar name, that two
+// schools are the same
+type DuplicateReason string
+
+const (
+	// DuplicateSameAddress means both addresses normalize to the same street
+	// address
+	DuplicateSameAddress DuplicateReason = "same_address"
+	// DuplicateNearby means both locations are within
+	// shared.SchoolDuplicateDistanceKm of each other
+	DuplicateNearby DuplicateReason = "nearby"
+	// DuplicateSameCity means both schools are in the same city and state. It is
+	// the weakest evidence: on its own, for schools entered without a street or
+	// location, it needs names at least shared.SchoolDuplicateCityNameThreshold
+	// similar.
+	DuplicateSameCity DuplicateReason = "same_city"
+)
+
+// SchoolDuplicate is a pair of schools that are likely the same school
+type SchoolDuplicate struct {
+	// SurvivorID is the school suggested to keep: the one with an NCES ID, or
+	// else the older one
+	SurvivorID    string `json:"survivor_id"`
+	SurvivorName  string `json:"survivor_name"`
+	DuplicateID   string `json:"duplicate_id"`
+	DuplicateName string `json:"duplicate_name"`
+	// NameSimilarity compares the normalized names, from 0 to 1
+	NameSimilarity float64           `json:"name_similarity"`
+	Reasons        []DuplicateReason `json:"reasons"`
+	// DistanceKm is the distance between the schools, when both are located
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+// duplicateNameStopWords carry no meaning for telling schools apart, so
+// "Lincoln Elementary" and "Lincoln Elementary School" normalize the same
+var duplicateNameStopWords = map[string]bool{"the": true, "of": true, "school": true}
+
+// duplicateNameKey normalizes a school name for comparison: lower-cased, with
+// abbreviations expanded and stop words dropped
+func duplicateNameKey(name string) string {
+	words, _ := schoolWords(name)
+	kept := words[:0]
+	for _, word := range words {
+		if !duplicateNameStopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// duplicateCityKey identifies a school's city, or is empty if the school has no
+// city and state
+func duplicateCityKey(address domain.Address) string {
+	city, state := domain.NormalizeCity(address.City), stateCode(strings.TrimSpace(address.State))
+	if city == "" || state == "" {
+		return ""
+	}
+	return state + "|" + city
+}
+
+// duplicateGeohashPrecision returns the geohash precision whose cells are at
+// least the duplicate distance across at every located school. All schools must
+// share one precision for their cells to line up, so it is sized for the most
+// poleward school.
+func duplicateGeohashPrecision(schools []*School) int {
+	maxLat := 0.0
+	for _, school := range schools {
+		if !school.IsMerged() && !school.Address.Location.IsEmpty() {
+			maxLat = math.Max(maxLat, math.Abs(school.Address.Location.Latitude))
+		}
+	}
+	return domain.GeohashPrecisionForRadius(shared.SchoolDuplicateDistanceKm, maxLat)
+}
+
+// duplicateBlocks returns the keys of the groups a school is compared within.
+// Schools that share no group cannot have a place in common: a located school
+// joins its geohash cell at precision and the cells around it, and every school
+// with a city joins its city.
+func duplicateBlocks(school *School, precision int) []string {
+	var blocks []string
+	if city := duplicateCityKey(school.Address); city != "" {
+		blocks = append(blocks, "city:"+city)
+	}
+
+	location := school.Address.Location
+	if location.IsEmpty() {
+		return blocks
+	}
+	cell, err := location.Geohash(precision)
+	if err != nil {
+		return blocks
+	}
+	blocks = append(blocks, "geo:"+cell)
+	neighbors, err := domain.GeohashNeighbors(cell)
+	if err != nil {
+		return blocks
+	}
+	for _, neighbor := range neighbors {
+		blocks = append(blocks, "geo:"+neighbor)
+	}
+	return blocks
+}
+
+// FindDuplicates returns the pairs of schools likely to be the same school, most
+// similar names first. A pair needs names at least
+// shared.SchoolDuplicateNameThreshold similar and a place in common: the same
+// street address, locations within shared.SchoolDuplicateDistanceKm, or, with
+// nearly the same name, the same city. Merged schools, pairs with different NCES
+// IDs, which NCES vouches are different schools, and pairs whose names carry
+// different numbers, such as "PS 101" and "PS 102", are never reported.
+func FindDuplicates(schools []*School) []SchoolDuplicate {
+	keys := make([]string, len(schools))
+	schoolBlocks := make([][]string, len(schools))
+	blocks := make(map[string][]int)
+	precision := duplicateGeohashPrecision(schools)
+	for i, school := range schools {
+		if school.IsMerged() {
+			continue
+		}
+		keys[i] = duplicateNameKey(school.Name)
+		schoolBlocks[i] = duplicateBlocks(school, precision)
+		for _, block := range schoolBlocks[i] {
+			blocks[block] = append(blocks[block], i)
+		}
+	}
+
+	duplicates := []SchoolDuplicate{}
+	for block, members := range blocks {
+		for x, i := range members {
+			for _, j := range members[x+1:] {
+				// Compare each pair only in the first group both schools joined
+				if firstSharedBlock(schoolBlocks[i], schoolBlocks[j]) != block {
+					continue
+				}
+				if duplicate, ok := compareSchools(schools[i], schools[j], keys[i], keys[j]); ok {
+					duplicates = append(duplicates, duplicate)
+				}
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		a, b := duplicates[i], duplicates[j]
+		if a.NameSimilarity != b.NameSimilarity {
+			return a.NameSimilarity > b.NameSimilarity
+		}
+		if len(a.Reasons) != len(b.Reasons) {
+			return len(a.Reasons) > len(b.Reasons)
+		}
+		if a.SurvivorID != b.SurvivorID {
+			return a.SurvivorID < b.SurvivorID
+		}
+		return a.DuplicateID < b.DuplicateID
+	})
+	return duplicates
+}
+
+// firstSharedBlock returns the first of a's groups that b joined too
+func firstSharedBlock(a, b []string) string {
+	for _, block := range a {
+		if slices.Contains(b, block) {
+			return block
+		}
+	}
+	return ""
+}
+
+// compareSchools checks one pair of schools, given their normalized names
+func compareSchools(a, b *School, keyA, keyB string) (SchoolDuplicate, bool) {
+	if a.NCESID != "" && b.NCESID != "" && a.NCESID != b.NCESID {
+		return SchoolDuplicate{}, false
+	}
+	if keyA == "" || keyB == "" {
+		return SchoolDuplicate{}, false
+	}
+
+	similarity := max(shared.TrigramSimilarity(keyA, keyB), shared.LevenshteinSimilarity(keyA, keyB))
+	if similarity < shared.SchoolDuplicateNameThreshold || !sameNameNumbers(keyA, keyB) {
+		return SchoolDuplicate{}, false
+	}
+
+	var reasons []DuplicateReason
+	var distance *float64
+	if a.Address.Street != "" && b.Address.Street != "" && a.Address.EquivalentTo(b.Address) {
+		reasons = append(reasons, DuplicateSameAddress)
+	}
+	if !a.Address.Location.IsEmpty() && !b.Address.Location.IsEmpty() {
+		km := a.Address.Location.DistanceTo(b.Address.Location)
+		distance = &km
+		if km <= shared.SchoolDuplicateDistanceKm {
+			reasons = append(reasons, DuplicateNearby)
+		}
+	}
+	if city := duplicateCityKey(a.Address); city != "" && city == duplicateCityKey(b.Address) {
+		reasons = append(reasons, DuplicateSameCity)
+	}
+	if len(reasons) == 0 {
+		return SchoolDuplicate{}, false
+	}
+	// Reasons are strongest first, so a leading same_city is the only evidence
+	if reasons[0] == DuplicateSameCity && similarity < shared.SchoolDuplicateCityNameThreshold {
+		return SchoolDuplicate{}, false
+	}
+
+	survivor, duplicate := a, b
+	if preferSurvivor(b, a) {
+		survivor, duplicate = b, a
+	}
+	return SchoolDuplicate{
+		SurvivorID:     survivor.ID,
+		SurvivorName:   survivor.Name,
+		DuplicateID:    duplicate.ID,
+		DuplicateName:  duplicate.Name,
+		NameSimilarity: similarity,
+		Reasons:        reasons,
+		DistanceKm:     distance,
+	}, true
+}
+
+// sameNameNumbers returns false if both normalized names carry numbers and the
+// numbers differ, as numbered schools in one city are different schools
+func sameNameNumbers(keyA, keyB string) bool {
+	numbersA, numbersB := nameNumbers(keyA), nameNumbers(keyB)
+	return len(numbersA) == 0 || len(numbersB) == 0 || slices.Equal(numbersA, numbersB)
+}
+
+// nameNumbers returns the words of a normalized name that contain a digit
+func nameNumbers(key string) []string {
+	var numbers []string
+	for _, word := range strings.Fields(key) {
+		if strings.ContainsAny(word, "0123456789") {
+			numbers = append(numbers, word)
+		}
+	}
+	return numbers
+}
+
+// preferSurvivor returns true if a is a better school to keep than b: schools
+// from NCES first, then the older school
+func preferSurvivor(a, b *School) bool {
+	if (a.NCESID != "") != (b.NCESID != "") {
+		return a.NCESID != ""
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,86 @@
+package schooldirectory
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/shared/domain"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	created := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	springfield := domain.Address{
+		Street: "100 Main Street", City: "Springfield", State: "IL", ZipCode: "62701",
+		Location: domain.Location{Latitude: 39.8017, Longitude: -89.6436},
+	}
+	typed := domain.Address{Street: "100 MAIN ST", City: "springfield", State: "il", ZipCode: "62701"}
+	nearby := domain.Address{
+		City: "Springfield", State: "IL", Location: domain.Location{Latitude: 39.8025, Longitude: -89.6440},
+	}
+
+	schools := []*School{
+		{ID: "nces", NCESID: "170993000708", Name: "Lincoln Elementary School", Address: springfield, CreatedAt: created},
+		{ID: "typed", Name: "Lincoln Elem", Address: typed, CreatedAt: created.AddDate(0, 1, 0)},
+		{ID: "pinned", Name: "Lincoln Elementary", Address: nearby, CreatedAt: created.AddDate(0, 2, 0)},
+		// Same campus, different school
+		{ID: "middle", Name: "Lincoln Middle School", Address: springfield, CreatedAt: created},
+		// Same name in another town
+		{ID: "elsewhere", Name: "Lincoln Elementary", Address: domain.Address{City: "Peoria", State: "IL"}},
+		{ID: "merged", Name: "Lincoln Elementary", Address: springfield, MergedIntoID: "nces"},
+	}
+
+	duplicates := FindDuplicates(schools)
+	if len(duplicates) != 3 {
+		t.Fatalf("FindDuplicates() = %+v, want 3 pairs", duplicates)
+	}
+
+	pairs := make(map[[2]string]SchoolDuplicate)
+	for _, duplicate := range duplicates {
+		pairs[[2]string{duplicate.SurvivorID, duplicate.DuplicateID}] = duplicate
+	}
+
+	typedPair, ok := pairs[[2]string{"nces", "typed"}]
+	if !ok || typedPair.NameSimilarity != 1 || !slices.Contains(typedPair.Reasons, DuplicateSameAddress) ||
+		typedPair.DistanceKm != nil {
+		t.Errorf("nces/typed pair = %+v", typedPair)
+	}
+	pinnedPair, ok := pairs[[2]string{"nces", "pinned"}]
+	if !ok || !slices.Contains(pinnedPair.Reasons, DuplicateNearby) || pinnedPair.DistanceKm == nil {
+		t.Errorf("nces/pinned pair = %+v", pinnedPair)
+	}
+	if _, ok := pairs[[2]string{"typed", "pinned"}]; !ok {
+		t.Errorf("FindDuplicates() = %+v, want the older hand-entered school kept", duplicates)
+	}
+}
+
+func TestFindDuplicates_NotDuplicates(t *testing.T) {
+	address := domain.Address{Street: "200 Oak St", City: "Springfield", State: "IL", ZipCode: "62701"}
+	shared := domain.Address{Street: "1700 3rd Ave", City: "New York", State: "NY", ZipCode: "10128"}
+	schools := []*School{
+		// NCES lists them as different schools
+		{ID: "nces-1", NCESID: "170993000708", Name: "Jefferson Elementary", Address: address},
+		{ID: "nces-2", NCESID: "170993000799", Name: "Jefferson Elementary", Address: address},
+		// Similar names with no place in common
+		{ID: "a", Name: "Washington High School"},
+		{ID: "b", Name: "Washington HS"},
+		{ID: "c", Name: "Washington High", Address: domain.Address{
+			Location: domain.Location{Latitude: 40.0, Longitude: -89.0},
+		}},
+		{ID: "d", Name: "Washington High", Address: domain.Address{
+			Location: domain.Location{Latitude: 40.1, Longitude: -89.0},
+		}},
+		// Numbered schools in one city, even sharing a building, are different
+		{ID: "ps-101", Name: "PS 101", Address: domain.Address{City: "New York", State: "NY"}},
+		{ID: "ps-102", Name: "PS 102", Address: domain.Address{City: "New York", State: "NY"}},
+		{ID: "is-201", Name: "IS 201 Arthur Schomburg", Address: shared},
+		{ID: "is-202", Name: "IS 202 Arthur Schomburg", Address: shared},
+		// Only the city in common, and the names are not close enough
+		{ID: "e", Name: "Grant Elementary", Address: domain.Address{City: "Peoria", State: "IL"}},
+		{ID: "f", Name: "Grant East Elementary", Address: domain.Address{City: "Peoria", State: "IL"}},
+	}
+
+	if duplicates := FindDuplicates(schools); len(duplicates) != 0 {
+		t.Errorf("FindDuplicates() = %+v, want none", duplicates)
+	}
+}
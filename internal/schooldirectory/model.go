@@ -0,0 +1,405 @@
+// Package schooldirectory holds the directory of schools that teachers work at.
+package schooldirectory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// Sector tells public schools from private ones
+type Sector string
+
+const (
+	// SectorPublic covers public, charter and magnet schools
+	SectorPublic Sector = "public"
+	// SectorPrivate covers private schools
+	SectorPrivate Sector = "private"
+)
+
+// IsValid returns true if the sector is a known value
+func (s Sector) IsValid() bool {
+	return s == SectorPublic || s == SectorPrivate
+}
+
+// Grade is a grade as coded by NCES: "PK", "KG", "01" to "12", or "UG" for
+// ungraded. Wishlists code grades differently; teacherwishlist.GradeLevel
+// converts between the two.
+type Grade string
+
+const (
+	// GradePreK is pre-kindergarten
+	GradePreK Grade = "PK"
+	// GradeKindergarten is kindergarten
+	GradeKindergarten Grade = "KG"
+	// GradeUngraded is for schools not organized by grade. It is not part of
+	// any grade span.
+	GradeUngraded Grade = "UG"
+)
+
+// gradeOrder ranks the grades a school can span. Ungraded is not ranked.
+var gradeOrder = map[Grade]int{
+	GradePreK: 0, GradeKindergarten: 1, "01": 2, "02": 3, "03": 4, "04": 5, "05": 6, "06": 7,
+	"07": 8, "08": 9, "09": 10, "10": 11, "11": 12, "12": 13,
+}
+
+// IsValid returns true if the grade is a known value
+func (g Grade) IsValid() bool {
+	_, ok := gradeOrder[g]
+	return ok || g == GradeUngraded
+}
+
+// RankedGrades returns the grades a school can span, from pre-kindergarten to
+// grade 12
+func RankedGrades() []Grade {
+	grades := make([]Grade, len(gradeOrder))
+	for grade, rank := range gradeOrder {
+		grades[rank] = grade
+	}
+	return grades
+}
+
+// Offers returns true if the school's grade span includes grade. Schools
+// without a ranked span offer no grade.
+func (s *School) Offers(grade Grade) bool {
+	rank, ok := gradeOrder[grade]
+	low, lowOK := gradeOrder[s.LowestGrade]
+	high, highOK := gradeOrder[s.HighestGrade]
+	return ok && lowOK && highOK && low <= rank && rank <= high
+}
+
+// SchoolType tells how a school is run
+type SchoolType string
+
+const (
+	// SchoolTypePublic is a regular public school
+	SchoolTypePublic SchoolType = "public"
+	// SchoolTypeCharter is a publicly funded, independently run school
+	SchoolTypeCharter SchoolType = "charter"
+	// SchoolTypeMagnet is a public school with a specialized program open to
+	// students from outside its zone
+	SchoolTypeMagnet SchoolType = "magnet"
+	// SchoolTypePrivate is a privately funded school
+	SchoolTypePrivate SchoolType = "private"
+)
+
+// IsValid returns true if the school type is a known value
+func (t SchoolType) IsValid() bool {
+	switch t {
+	case SchoolTypePublic, SchoolTypeCharter, SchoolTypeMagnet, SchoolTypePrivate:
+		return true
+	}
+	return false
+}
+
+// Sector returns the sector of schools of the type: private for private schools
+// and public for the others
+func (t SchoolType) Sector() Sector {
+	if t == SchoolTypePrivate {
+		return SectorPrivate
+	}
+	return SectorPublic
+}
+
+// TitleIStatus is a school's standing in the federal Title I program for
+// schools with many low-income students
+type TitleIStatus string
+
+const (
+	// TitleINotEligible schools do not qualify for Title I funds
+	TitleINotEligible TitleIStatus = "not_eligible"
+	// TitleIEligible schools qualify but run no Title I program
+	TitleIEligible TitleIStatus = "eligible"
+	// TitleITargeted schools serve only the students most in need
+	TitleITargeted TitleIStatus = "targeted"
+	// TitleISchoolwide schools, where at least 40% of students are low-income,
+	// use the funds for the whole school
+	TitleISchoolwide TitleIStatus = "schoolwide"
+)
+
+// IsValid returns true if the status is a known value
+func (t TitleIStatus) IsValid() bool {
+	switch t {
+	case TitleINotEligible, TitleIEligible, TitleITargeted, TitleISchoolwide:
+		return true
+	}
+	return false
+}
+
+// HasProgram returns true if the school runs a Title I program
+func (t TitleIStatus) HasProgram() bool {
+	return t == TitleITargeted || t == TitleISchoolwide
+}
+
+// LocaleType is the kind of community a school is in
+type LocaleType string
+
+const (
+	// LocaleUrban covers the NCES city locales
+	LocaleUrban LocaleType = "urban"
+	// LocaleSuburban covers the NCES suburb locales
+	LocaleSuburban LocaleType = "suburban"
+	// LocaleRural covers the NCES town and rural locales
+	LocaleRural LocaleType = "rural"
+)
+
+// IsValid returns true if the locale type is a known value
+func (l LocaleType) IsValid() bool {
+	return l == LocaleUrban || l == LocaleSuburban || l == LocaleRural
+}
+
+// LocaleTypeOf returns the locale type of an NCES locale code, or "" for an
+// unknown code: 1x cities are urban, 2x suburbs suburban, and 3x towns and 4x
+// rural areas rural
+func LocaleTypeOf(localeCode string) LocaleType {
+	if !localeCodes[localeCode] {
+		return ""
+	}
+	switch localeCode[0] {
+	case '1':
+		return LocaleUrban
+	case '2':
+		return LocaleSuburban
+	}
+	return LocaleRural
+}
+
+// localeCodes are the NCES urban-centric locale codes, from 11 (large city) to
+// 43 (remote rural)
+var localeCodes = map[string]bool{
+	"11": true, "12": true, "13": true, "21": true, "22": true, "23": true,
+	"31": true, "32": true, "33": true, "41": true, "42": true, "43": true,
+}
+
+// ncesIDRegex matches a 12-digit public school NCESSCH ID or an 8-character
+// private school PPIN
+var ncesIDRegex = regexp.MustCompile(`^(\d{12}|[A-Z0-9]{8})$`)
+
+// School is a school teachers can register with and donors can ship to
+type School struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Address domain.Address `json:"address"`
+	// NCESID is the school's ID in the NCES Common Core of Data or Private
+	// School Survey. Schools entered by hand have none.
+	NCESID string `json:"nces_id,omitempty"`
+	Sector Sector `json:"sector,omitempty"`
+	// LowestGrade and HighestGrade are the grade span the school offers
+	LowestGrade  Grade `json:"lowest_grade,omitempty"`
+	HighestGrade Grade `json:"highest_grade,omitempty"`
+	// DistrictID is the District the school belongs to, if it is known
+	DistrictID string `json:"district_id,omitempty"`
+	// DistrictNCESID is the NCES LEAID of the school's district
+	DistrictNCESID string `json:"district_nces_id,omitempty"`
+	DistrictName   string `json:"district_name,omitempty"`
+	// LocaleCode is the NCES urban-centric locale code, e.g. "11" for a large city
+	LocaleCode string     `json:"locale_code,omitempty"`
+	LocaleType LocaleType `json:"locale_type,omitempty"`
+	SchoolType SchoolType `json:"school_type,omitempty"`
+	// Enrollment is the number of students, or 0 if it is not known
+	Enrollment   int          `json:"enrollment,omitempty"`
+	TitleIStatus TitleIStatus `json:"title_i_status,omitempty"`
+	// FRLPercent is the share of students eligible for free or reduced-price
+	// lunch, from 0 to 100, or nil if it is not known
+	FRLPercent *float64 `json:"frl_percent,omitempty"`
+	// MergedIntoID is set once the school was merged into another as a
+	// duplicate. The record is kept as an alias of the surviving school.
+	MergedIntoID string     `json:"merged_into_id,omitempty"`
+	MergedAt     *time.Time `json:"merged_at,omitempty"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the school's fields, returning a *shared.ValidationError
+// listing all problems. Address errors are reported under "address".
+func (s *School) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if strings.TrimSpace(s.Name) == "" {
+		verr.Add("name", shared.CodeSchoolNameRequired, "name is required")
+	}
+
+	if !s.Address.IsEmpty() {
+		_, err := domain.NewAddress(s.Address.Street, s.Address.City, s.Address.State, s.Address.ZipCode,
+			s.Address.Location)
+		verr.Merge("address", shared.CodeSchoolAddressInvalid, err)
+	}
+
+	if s.NCESID != "" && !ncesIDRegex.MatchString(s.NCESID) {
+		verr.Add("nces_id", shared.CodeSchoolNCESIDFormat,
+			"NCES ID must be a 12-digit NCESSCH or an 8-character PPIN")
+	}
+
+	if s.Sector != "" && !s.Sector.IsValid() {
+		verr.Add("sector", shared.CodeSchoolSectorInvalid, fmt.Sprintf("unknown sector %q", s.Sector))
+	}
+
+	lowValid, highValid := s.validateGrade(verr, "lowest_grade", s.LowestGrade),
+		s.validateGrade(verr, "highest_grade", s.HighestGrade)
+	if lowValid && highValid {
+		low, lowRanked := gradeOrder[s.LowestGrade]
+		high, highRanked := gradeOrder[s.HighestGrade]
+		if lowRanked && highRanked && low > high {
+			verr.Add("highest_grade", shared.CodeSchoolGradeSpan, "highest grade must not be below the lowest grade")
+		}
+	}
+
+	if s.LocaleCode != "" && !localeCodes[s.LocaleCode] {
+		verr.Add("locale_code", shared.CodeSchoolLocaleCode, fmt.Sprintf("unknown NCES locale code %q", s.LocaleCode))
+	}
+
+	if s.LocaleType != "" && !s.LocaleType.IsValid() {
+		verr.Add("locale_type", shared.CodeSchoolLocaleType, fmt.Sprintf("unknown locale type %q", s.LocaleType))
+	} else if codeType := LocaleTypeOf(s.LocaleCode); s.LocaleType != "" && codeType != "" && s.LocaleType != codeType {
+		verr.Add("locale_type", shared.CodeSchoolLocaleTypeMismatch,
+			fmt.Sprintf("locale code %s is %s, not %s", s.LocaleCode, codeType, s.LocaleType))
+	}
+
+	if s.SchoolType != "" && !s.SchoolType.IsValid() {
+		verr.Add("school_type", shared.CodeSchoolTypeInvalid, fmt.Sprintf("unknown school type %q", s.SchoolType))
+	} else if s.SchoolType != "" && s.Sector.IsValid() && s.SchoolType.Sector() != s.Sector {
+		verr.Add("school_type", shared.CodeSchoolTypeSector,
+			fmt.Sprintf("a %s school cannot be in the %s sector", s.SchoolType, s.Sector))
+	}
+
+	if s.Enrollment < 0 || s.Enrollment > shared.MaxSchoolEnrollment {
+		verr.Add("enrollment", shared.CodeSchoolEnrollmentRange,
+			fmt.Sprintf("enrollment must be between 0 and %d", shared.MaxSchoolEnrollment))
+	}
+
+	if s.TitleIStatus != "" && !s.TitleIStatus.IsValid() {
+		verr.Add("title_i_status", shared.CodeSchoolTitleIStatus, fmt.Sprintf("unknown Title I status %q", s.TitleIStatus))
+	}
+
+	// The negated form also rejects NaN
+	if s.FRLPercent != nil && !(*s.FRLPercent >= 0 && *s.FRLPercent <= 100) {
+		verr.Add("frl_percent", shared.CodeSchoolFRLPercentRange, "free and reduced lunch rate must be between 0 and 100")
+	}
+
+	return verr.ErrOrNil()
+}
+
+// validateGrade reports an unknown grade and returns true if the grade is empty
+// or known
+func (s *School) validateGrade(verr *shared.ValidationError, field string, grade Grade) bool {
+	if grade == "" || grade.IsValid() {
+		return true
+	}
+	verr.Add(field, shared.CodeSchoolGradeInvalid, fmt.Sprintf("unknown grade %q", grade))
+	return false
+}
+
+// setDistrict links the school to a district. The district's NCES ID replaces
+// the previous one even when empty, so it never names the former district.
+func (s *School) setDistrict(district *District) {
+	s.DistrictID = district.ID
+	s.DistrictName = district.Name
+	s.DistrictNCESID = district.NCESID
+}
+
+// IsMerged returns true if the school was merged into another
+func (s *School) IsMerged() bool {
+	return s.MergedIntoID != ""
+}
+
+// mergeInto turns the school into an alias of survivor. The survivor takes the
+// NCES ID, address, location and district it is missing, so nothing known about
+// the school is lost; the NCES ID moves rather than being copied, as it is unique.
+func (s *School) mergeInto(survivor *School, at time.Time) {
+	if survivor.NCESID == "" {
+		survivor.NCESID, s.NCESID = s.NCESID, ""
+	}
+	if survivor.Address.IsEmpty() {
+		survivor.Address = s.Address
+	} else if survivor.Address.Location.IsEmpty() && survivor.Address.EquivalentTo(s.Address) {
+		survivor.Address.Location = s.Address.Location
+	}
+	if survivor.DistrictID == "" && s.DistrictID != "" {
+		survivor.DistrictID, survivor.DistrictNCESID, survivor.DistrictName = s.DistrictID, s.DistrictNCESID, s.DistrictName
+	}
+	survivor.UpdatedAt = at
+
+	s.MergedIntoID = survivor.ID
+	s.MergedAt = &at
+	s.UpdatedAt = at
+}
+
+// applyNCES copies the fields NCES publishes from record onto the school and
+// returns true if any of them changed. NCES publishes directory, enrollment and
+// lunch data in separate files, so a field the record leaves empty keeps the
+// school's value; a location is kept likewise, since the CCD directory file has
+// no coordinates. The school is linked to district when it is not nil.
+func (s *School) applyNCES(record School, district *District) bool {
+	if record.Address.Location.IsEmpty() {
+		record.Address.Location = s.Address.Location
+	}
+	if record.SchoolType == "" {
+		record.SchoolType = s.SchoolType
+	}
+	if record.Enrollment == 0 {
+		record.Enrollment = s.Enrollment
+	}
+	if record.TitleIStatus == "" {
+		record.TitleIStatus = s.TitleIStatus
+	}
+	if record.FRLPercent == nil {
+		record.FRLPercent = s.FRLPercent
+	}
+	record.DistrictID = s.DistrictID
+	if district != nil {
+		record.setDistrict(district)
+	}
+
+	changed := s.Name != record.Name ||
+		!s.Address.Equals(record.Address) ||
+		s.NCESID != record.NCESID ||
+		s.Sector != record.Sector ||
+		s.LowestGrade != record.LowestGrade ||
+		s.HighestGrade != record.HighestGrade ||
+		s.DistrictID != record.DistrictID ||
+		s.DistrictNCESID != record.DistrictNCESID ||
+		s.DistrictName != record.DistrictName ||
+		s.LocaleCode != record.LocaleCode ||
+		s.LocaleType != record.LocaleType ||
+		s.SchoolType != record.SchoolType ||
+		s.Enrollment != record.Enrollment ||
+		s.TitleIStatus != record.TitleIStatus ||
+		!equalPercent(s.FRLPercent, record.FRLPercent)
+
+	s.Name = record.Name
+	s.Address = record.Address
+	s.NCESID = record.NCESID
+	s.Sector = record.Sector
+	s.LowestGrade, s.HighestGrade = record.LowestGrade, record.HighestGrade
+	s.DistrictID, s.DistrictNCESID, s.DistrictName = record.DistrictID, record.DistrictNCESID, record.DistrictName
+	s.LocaleCode, s.LocaleType = record.LocaleCode, record.LocaleType
+	s.SchoolType = record.SchoolType
+	s.Enrollment = record.Enrollment
+	s.TitleIStatus = record.TitleIStatus
+	s.FRLPercent = record.FRLPercent
+
+	return changed
+}
+
+// equalPercent compares two optional percentages
+func equalPercent(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// stateCode returns the USPS code of a state given by code or full name, or the
+// value uppercased when it names no state, for validation to reject
+func stateCode(value string) string {
+	if code, err := domain.StateCode(value); err == nil {
+		return code
+	}
+	return strings.ToUpper(value)
+}
@@ -0,0 +1,96 @@
+package schooldirectory
+
+import (
+	"errors"
+	"testing"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+func TestSchool_Validate(t *testing.T) {
+	highNeed, overFull := 82.5, 120.0
+	tests := []struct {
+		name      string
+		school    School
+		wantCodes []string
+	}{
+		{"valid without address", School{Name: "Lincoln Elementary"}, nil},
+		{"valid with address", School{
+			Name:    "Lincoln Elementary",
+			Address: domain.Address{Street: "100 Main St", City: "Springfield", State: "IL", ZipCode: "62701"},
+		}, nil},
+		{"missing name", School{Name: "  "}, []string{shared.CodeSchoolNameRequired}},
+		{"invalid address", School{
+			Name:    "Lincoln Elementary",
+			Address: domain.Address{City: "Springfield", State: "Illinoise"},
+		}, []string{shared.CodeAddressStateFormat}},
+		{"valid NCES fields", School{
+			Name: "Lincoln Elementary", NCESID: "170993000708", Sector: SectorPublic,
+			LowestGrade: GradePreK, HighestGrade: "05", LocaleCode: "21",
+		}, nil},
+		{"ungraded span", School{Name: "Lincoln Elementary", LowestGrade: GradeUngraded, HighestGrade: "08"}, nil},
+		{"invalid NCES fields", School{
+			Name: "Lincoln Elementary", NCESID: "17-0993", Sector: "charter",
+			LowestGrade: "13", HighestGrade: "05", LocaleCode: "14",
+		}, []string{shared.CodeSchoolNCESIDFormat, shared.CodeSchoolSectorInvalid, shared.CodeSchoolGradeInvalid,
+			shared.CodeSchoolLocaleCode}},
+		{"valid attributes", School{
+			Name: "Lincoln Elementary", Sector: SectorPublic, LocaleCode: "21", LocaleType: LocaleSuburban,
+			SchoolType: SchoolTypeCharter, Enrollment: 420, TitleIStatus: TitleISchoolwide, FRLPercent: &highNeed,
+		}, nil},
+		{"invalid attributes", School{
+			Name: "Lincoln Elementary", LocaleType: "remote", SchoolType: "online", Enrollment: -1,
+			TitleIStatus: "yes", FRLPercent: &overFull,
+		}, []string{shared.CodeSchoolLocaleType, shared.CodeSchoolTypeInvalid, shared.CodeSchoolEnrollmentRange,
+			shared.CodeSchoolTitleIStatus, shared.CodeSchoolFRLPercentRange}},
+		{"inconsistent attributes", School{
+			Name: "Lincoln Elementary", Sector: SectorPrivate, LocaleCode: "11", LocaleType: LocaleRural,
+			SchoolType: SchoolTypeMagnet,
+		}, []string{shared.CodeSchoolLocaleTypeMismatch, shared.CodeSchoolTypeSector}},
+		{"inverted grade span", School{Name: "Lincoln Elementary", LowestGrade: "09", HighestGrade: GradeKindergarten},
+			[]string{shared.CodeSchoolGradeSpan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.school.Validate()
+			if len(tt.wantCodes) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *shared.ValidationError", err)
+			}
+			for _, code := range tt.wantCodes {
+				if !verr.HasCode(code) {
+					t.Errorf("Validate() error = %v, want code %s", err, code)
+				}
+			}
+		})
+	}
+}
+
+func TestSchool_Offers(t *testing.T) {
+	middle := School{LowestGrade: "06", HighestGrade: "08"}
+	if !middle.Offers("06") || !middle.Offers("08") || middle.Offers("05") || middle.Offers(GradeUngraded) {
+		t.Errorf("Offers() is wrong for a 6-8 span")
+	}
+	ungraded := School{LowestGrade: GradeUngraded, HighestGrade: "08"}
+	if ungraded.Offers("07") {
+		t.Errorf("Offers() = true for an ungraded span")
+	}
+}
+
+func TestLocaleTypeOf(t *testing.T) {
+	tests := map[string]LocaleType{"11": LocaleUrban, "23": LocaleSuburban, "32": LocaleRural, "43": LocaleRural, "14": ""}
+	for code, want := range tests {
+		if got := LocaleTypeOf(code); got != want {
+			t.Errorf("LocaleTypeOf(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
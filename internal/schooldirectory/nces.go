@@ -0,0 +1,356 @@
+package schooldirectory
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// NCESFileKind tells which NCES survey a file comes from
+type NCESFileKind string
+
+const (
+	// NCESPublic is a Common Core of Data public school directory file, keyed by
+	// the 12-digit NCESSCH
+	NCESPublic NCESFileKind = "ccd"
+	// NCESPrivate is a Private School Survey file, keyed by the 8-character PPIN
+	NCESPrivate NCESFileKind = "pss"
+)
+
+// NCESRowError lists why one row of an NCES file was skipped
+type NCESRowError struct {
+	// Line is the line of the file the row starts on, counting from 1
+	Line   int                 `json:"line"`
+	NCESID string              `json:"nces_id,omitempty"`
+	Errors []shared.FieldError `json:"errors"`
+}
+
+// NCESImport is a parsed NCES file
+type NCESImport struct {
+	Kind NCESFileKind `json:"kind"`
+	// Schools are the rows that parsed into valid schools, in file order. They
+	// have no ID, version or timestamps yet.
+	Schools []School `json:"schools"`
+	// Skipped are the rows that could not be parsed
+	Skipped []NCESRowError `json:"skipped"`
+}
+
+// ncesColumnAliases maps each school field to the column names used for it by
+// the CCD directory and geocode files and by the PSS, in lower case. The first
+// column found wins.
+var ncesColumnAliases = map[NCESFileKind]map[string][]string{
+	NCESPublic: {
+		"id":            {"ncessch"},
+		"name":          {"sch_name", "school_name"},
+		"street":        {"lstreet1", "street"},
+		"city":          {"lcity", "city"},
+		"state":         {"lstate", "state"},
+		"zip":           {"lzip", "zip"},
+		"zip4":          {"lzip4"},
+		"county":        {"nmcnty", "cnty_name"},
+		"latitude":      {"lat", "latcod", "latitude"},
+		"longitude":     {"lon", "loncod", "longitude"},
+		"lowest_grade":  {"gslo"},
+		"highest_grade": {"gshi"},
+		"district_id":   {"leaid"},
+		"district_name": {"lea_name"},
+		"locale":        {"ulocale", "locale"},
+		"enrollment":    {"member", "total_students", "enrollment"},
+		"frl_count":     {"totfrl", "frl"},
+		"title_i":       {"titlei_status", "title_i_status"},
+		"charter":       {"charter_text", "chartr"},
+		"magnet":        {"magnet_text", "magnet"},
+	},
+	NCESPrivate: {
+		"id":            {"ppin"},
+		"name":          {"pinst"},
+		"street":        {"pl_add", "paddrs"},
+		"city":          {"pl_cit", "pcity"},
+		"state":         {"pl_stabb", "pstabb"},
+		"zip":           {"pl_zip", "pzip"},
+		"zip4":          {"pl_zip4", "pzip4"},
+		"county":        {"pcntnm"},
+		"latitude":      {"latitude"},
+		"longitude":     {"longitude"},
+		"lowest_grade":  {"logr"},
+		"highest_grade": {"higr"},
+		"locale":        {"ulocale"},
+		"enrollment":    {"numstuds"},
+	},
+}
+
+// ncesRequiredColumns are the columns a file must have to be imported
+var ncesRequiredColumns = []string{"id", "name", "city", "state"}
+
+// pssGrades maps the PSS LOGR and HIGR codes to grades. Codes 4 and 5, the
+// transitional kindergarten and first grade years, are treated as kindergarten.
+var pssGrades = map[string]Grade{
+	"1": GradeUngraded, "2": GradePreK, "3": GradeKindergarten, "4": GradeKindergarten, "5": GradeKindergarten,
+	"6": "01", "7": "02", "8": "03", "9": "04", "10": "05", "11": "06", "12": "07", "13": "08",
+	"14": "09", "15": "10", "16": "11", "17": "12",
+}
+
+// ccdTitleIStatuses maps the CCD TITLEI_STATUS codes to Title I statuses
+var ccdTitleIStatuses = map[string]TitleIStatus{
+	"NOTTITLE1ELIG": TitleINotEligible,
+	"TGELGBNOPROG":  TitleIEligible,
+	"SWELIGNOPROG":  TitleIEligible,
+	"TGELGBTGPROG":  TitleITargeted,
+	"SWELIGTGPROG":  TitleITargeted,
+	"SWELIGSWPROG":  TitleISchoolwide,
+}
+
+// ParseNCESFile reads an NCES CSV file saved on the local file system
+func ParseNCESFile(path string) (*NCESImport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open NCES file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseNCES(file)
+}
+
+// ParseNCES parses a CCD public school or PSS private school CSV file into
+// schools. The survey is detected from the header row: an NCESSCH column marks a
+// CCD file and a PPIN column a PSS file. Coordinates are read when the file has
+// them, as the CCD geocode file and the PSS do. Rows that cannot be parsed or do
+// not make a valid school are reported in Skipped instead of failing the whole
+// import.
+func ParseNCES(r io.Reader) (*NCESImport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "file",
+			Code:    shared.CodeNCESFileUnreadable,
+			Message: fmt.Sprintf("the NCES file has no readable header row: %v", err),
+		})
+	}
+
+	kind, columns, err := ncesColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NCESImport{Kind: kind, Schools: []School{}, Skipped: []NCESRowError{}}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Skipped = append(result.Skipped, NCESRowError{
+				Line: parseErr.StartLine,
+				Errors: []shared.FieldError{{
+					Field:   "cells",
+					Code:    shared.CodeNCESRowMalformed,
+					Message: fmt.Sprintf("line %d is not valid CSV: %v", parseErr.StartLine, parseErr.Err),
+				}},
+			})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read NCES file: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		school, err := columns.school(kind, record)
+		var verr *shared.ValidationError
+		if errors.As(err, &verr) {
+			result.Skipped = append(result.Skipped, NCESRowError{Line: line, NCESID: school.NCESID, Errors: verr.Fields})
+			continue
+		}
+		result.Schools = append(result.Schools, school)
+	}
+
+	return result, nil
+}
+
+// ncesColumnIndex maps a school field to its index in the header row
+type ncesColumnIndex map[string]int
+
+// ncesColumns detects the survey of a header row and finds its columns
+func ncesColumns(header []string) (NCESFileKind, ncesColumnIndex, error) {
+	names := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if _, ok := names[name]; !ok {
+			names[name] = i
+		}
+	}
+
+	var kind NCESFileKind
+	switch {
+	case hasColumn(names, "ncessch"):
+		kind = NCESPublic
+	case hasColumn(names, "ppin"):
+		kind = NCESPrivate
+	default:
+		return "", nil, shared.NewValidationError(shared.FieldError{
+			Field:   "file",
+			Code:    shared.CodeNCESColumnMissing,
+			Message: "the file has no NCESSCH or PPIN column",
+		})
+	}
+
+	columns := make(ncesColumnIndex)
+	for field, aliases := range ncesColumnAliases[kind] {
+		for _, alias := range aliases {
+			if i, ok := names[alias]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	verr := &shared.ValidationError{}
+	for _, field := range ncesRequiredColumns {
+		if _, ok := columns[field]; !ok {
+			verr.Add("file", shared.CodeNCESColumnMissing,
+				fmt.Sprintf("the file has no %s column (%s)", field, strings.Join(ncesColumnAliases[kind][field], ", ")))
+		}
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return "", nil, err
+	}
+
+	return kind, columns, nil
+}
+
+func hasColumn(names map[string]int, name string) bool {
+	_, ok := names[name]
+	return ok
+}
+
+// school parses one data row into a validated school, returning a
+// *shared.ValidationError listing every problem with the row. The school's
+// NCESID is set even when the row is invalid, so it can be reported.
+func (c ncesColumnIndex) school(kind NCESFileKind, record []string) (School, error) {
+	cell := func(field string) string {
+		i, ok := c[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	school := School{
+		NCESID:         strings.ToUpper(cell("id")),
+		Name:           cell("name"),
+		DistrictNCESID: cell("district_id"),
+		DistrictName:   cell("district_name"),
+		LocaleCode:     ncesLocaleCode(cell("locale")),
+		Address: domain.Address{
+			Street:  cell("street"),
+			City:    cell("city"),
+			State:   stateCode(cell("state")),
+			ZipCode: ncesZipCode(cell("zip"), cell("zip4")),
+		},
+	}
+	school.LocaleType = LocaleTypeOf(school.LocaleCode)
+	school.Enrollment = ncesCount(cell("enrollment"))
+	school.TitleIStatus = ccdTitleIStatuses[strings.ToUpper(cell("title_i"))]
+	if frl := ncesCount(cell("frl_count")); school.Enrollment > 0 && frl > 0 && frl <= school.Enrollment {
+		percent := math.Round(float64(frl)*10000/float64(school.Enrollment)) / 100
+		school.FRLPercent = &percent
+	}
+
+	school.Sector = SectorPublic
+	school.LowestGrade, school.HighestGrade = ncesGrade(cell("lowest_grade")), ncesGrade(cell("highest_grade"))
+	_, hasCharter := c["charter"]
+	_, hasMagnet := c["magnet"]
+	switch {
+	case kind == NCESPrivate:
+		school.Sector, school.SchoolType = SectorPrivate, SchoolTypePrivate
+		school.LowestGrade, school.HighestGrade = pssGrades[cell("lowest_grade")], pssGrades[cell("highest_grade")]
+	case ncesYes(cell("charter")):
+		school.SchoolType = SchoolTypeCharter
+	case ncesYes(cell("magnet")):
+		school.SchoolType = SchoolTypeMagnet
+	case hasCharter || hasMagnet:
+		// A public school is only known to be neither when the file says so;
+		// otherwise its type is left unknown
+		school.SchoolType = SchoolTypePublic
+	}
+
+	verr := &shared.ValidationError{}
+	if lat, lon := cell("latitude"), cell("longitude"); lat != "" || lon != "" {
+		latitude, latErr := strconv.ParseFloat(lat, 64)
+		longitude, lonErr := strconv.ParseFloat(lon, 64)
+		if latErr != nil || lonErr != nil {
+			verr.Add("address.location", shared.CodeNCESCoordinateFormat,
+				fmt.Sprintf("coordinates %q, %q are not numbers", lat, lon))
+		} else {
+			school.Address.Location = domain.Location{Latitude: latitude, Longitude: longitude, County: cell("county")}
+		}
+	}
+
+	verr.Merge("", shared.CodeNCESRowMalformed, school.Validate())
+	return school, verr.ErrOrNil()
+}
+
+// ncesGrade maps a CCD GSLO or GSHI value to a grade. Adult education, grade 13
+// and "not applicable" have no grade.
+func ncesGrade(value string) Grade {
+	grade := Grade(strings.ToUpper(value))
+	if len(grade) == 1 {
+		grade = "0" + grade
+	}
+	if !grade.IsValid() {
+		return ""
+	}
+	return grade
+}
+
+// ncesCount parses a student count. NCES codes missing and suppressed values
+// as negative numbers or letters, which are read as 0.
+func ncesCount(value string) int {
+	count, err := strconv.Atoi(strings.TrimSuffix(value, ".0"))
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// ncesYes returns true for the "Yes" and "1" flags of NCES yes/no columns
+func ncesYes(value string) bool {
+	return strings.EqualFold(value, "yes") || value == "1"
+}
+
+// ncesLocaleCode takes the code from a locale such as "11-City: Large"
+func ncesLocaleCode(value string) string {
+	if len(value) >= 2 && localeCodes[value[:2]] {
+		return value[:2]
+	}
+	return ""
+}
+
+// ncesZipCode joins a ZIP code and its ZIP+4 extension. Spreadsheets often drop
+// leading zeros from New England ZIP codes, so they are put back.
+func ncesZipCode(zip, zip4 string) string {
+	if zip == "" {
+		return ""
+	}
+	if len(zip) < 5 {
+		zip = strings.Repeat("0", 5-len(zip)) + zip
+	}
+	if len(zip4) == 4 && zip4 != "0000" {
+		return zip + "-" + zip4
+	}
+	return zip
+}
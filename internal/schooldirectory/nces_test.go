@@ -0,0 +1,101 @@
+package schooldirectory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hrh-backend/internal/shared"
+)
+
+func TestParseNCES_PublicSchools(t *testing.T) {
+	file := "\uFEFFSCHOOL_YEAR,NCESSCH,SCH_NAME,LEAID,LEA_NAME,LSTREET1,LCITY,LSTATE,LZIP,LZIP4,GSLO,GSHI,ULOCALE,LAT,LON,NMCNTY\n" +
+		"2023-2024,170993000708,Lincoln Elementary School,1709930,Springfield SD 186,100 Main St,Springfield,IL,62701,1234,PK,5,21-Suburb: Large,39.8,-89.65,Sangamon County\n" +
+		"2023-2024,250327000436,Adams Middle,2503270,Boston,1 School St,Boston,MA,2108,M,06,08,11-City: Large,,,\n" +
+		"2023-2024,170993000709,,1709930,Springfield SD 186,,Springfield,IL,62701,,KG,AE,,abc,def,\n"
+
+	parsed, err := ParseNCES(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ParseNCES() error = %v", err)
+	}
+	if parsed.Kind != NCESPublic || len(parsed.Schools) != 2 || len(parsed.Skipped) != 1 {
+		t.Fatalf("ParseNCES() = %+v", parsed)
+	}
+
+	lincoln := parsed.Schools[0]
+	if lincoln.NCESID != "170993000708" || lincoln.Sector != SectorPublic || lincoln.DistrictNCESID != "1709930" ||
+		lincoln.LowestGrade != GradePreK || lincoln.HighestGrade != "05" || lincoln.LocaleCode != "21" {
+		t.Errorf("school = %+v", lincoln)
+	}
+	if lincoln.Address.ZipCode != "62701-1234" || lincoln.Address.Location.Latitude != 39.8 ||
+		lincoln.Address.Location.County != "Sangamon County" {
+		t.Errorf("address = %+v", lincoln.Address)
+	}
+
+	adams := parsed.Schools[1]
+	if adams.Address.ZipCode != "02108" || !adams.Address.Location.IsEmpty() || adams.LocaleCode != "11" {
+		t.Errorf("school = %+v", adams)
+	}
+
+	skipped := parsed.Skipped[0]
+	verr := &shared.ValidationError{Fields: skipped.Errors}
+	if skipped.Line != 4 || skipped.NCESID != "170993000709" ||
+		!verr.HasCode(shared.CodeSchoolNameRequired) || !verr.HasCode(shared.CodeNCESCoordinateFormat) {
+		t.Errorf("skipped = %+v", skipped)
+	}
+}
+
+func TestParseNCES_PrivateSchools(t *testing.T) {
+	file := "PPIN,PINST,PL_ADD,PL_CIT,PL_STABB,PL_ZIP,LOGR,HIGR,ULOCALE,LATITUDE,LONGITUDE,PCNTNM\n" +
+		"a1234567,St. Agnes School,5 Church Rd,Springfield,IL,62704,3,13,12,39.77,-89.7,Sangamon\n"
+
+	parsed, err := ParseNCES(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ParseNCES() error = %v", err)
+	}
+	if parsed.Kind != NCESPrivate || len(parsed.Schools) != 1 || len(parsed.Skipped) != 0 {
+		t.Fatalf("ParseNCES() = %+v", parsed)
+	}
+
+	school := parsed.Schools[0]
+	if school.NCESID != "A1234567" || school.Sector != SectorPrivate || school.SchoolType != SchoolTypePrivate || school.LowestGrade != GradeKindergarten ||
+		school.HighestGrade != "08" || school.LocaleCode != "12" || school.Address.Location.County != "Sangamon" {
+		t.Errorf("school = %+v", school)
+	}
+}
+
+func TestParseNCES_SchoolAttributes(t *testing.T) {
+	file := "NCESSCH,SCH_NAME,LCITY,LSTATE,ULOCALE,MEMBER,TOTFRL,TITLEI_STATUS,CHARTER_TEXT,MAGNET_TEXT\n" +
+		"170993000708,Lincoln Elementary School,Springfield,IL,41-Rural: Fringe,400,330,SWELIGSWPROG,No,No\n" +
+		"170993000710,Douglas Charter,Springfield,IL,13-City: Small,-1,M,TGELGBNOPROG,Yes,No\n"
+
+	parsed, err := ParseNCES(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("ParseNCES() error = %v", err)
+	}
+	if len(parsed.Schools) != 2 || len(parsed.Skipped) != 0 {
+		t.Fatalf("ParseNCES() = %+v", parsed)
+	}
+
+	lincoln := parsed.Schools[0]
+	if lincoln.LocaleType != LocaleRural || lincoln.Enrollment != 400 || lincoln.TitleIStatus != TitleISchoolwide ||
+		lincoln.SchoolType != SchoolTypePublic || lincoln.FRLPercent == nil || *lincoln.FRLPercent != 82.5 {
+		t.Errorf("school = %+v", lincoln)
+	}
+
+	douglas := parsed.Schools[1]
+	if douglas.LocaleType != LocaleUrban || douglas.Enrollment != 0 || douglas.FRLPercent != nil ||
+		douglas.TitleIStatus != TitleIEligible || douglas.SchoolType != SchoolTypeCharter {
+		t.Errorf("school = %+v", douglas)
+	}
+}
+
+func TestParseNCES_MissingColumns(t *testing.T) {
+	for _, header := range []string{"ID,NAME,CITY,STATE\n", "NCESSCH,LCITY,LSTATE\n"} {
+		_, err := ParseNCES(strings.NewReader(header))
+		var verr *shared.ValidationError
+		if !errors.As(err, &verr) || !verr.HasCode(shared.CodeNCESColumnMissing) {
+			t.Errorf("ParseNCES(%q) error = %v", header, err)
+		}
+	}
+}
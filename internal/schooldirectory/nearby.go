@@ -0,0 +1,97 @@
+package schooldirectory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// schoolIndex caches the locations of the directory's schools in a
+// domain.SpatialIndex for radius search. It is loaded on first use, kept up to
+// date with the schools this Service saves, and reloaded once older than
+// shared.SchoolIndexMaxAge.
+type schoolIndex struct {
+	mu       sync.Mutex
+	index    *domain.SpatialIndex
+	loadedAt time.Time
+}
+
+// load returns the index, reloading every school's location if it was never
+// loaded or has grown stale
+func (i *schoolIndex) load(ctx context.Context, schools SchoolRepository, now time.Time) (*domain.SpatialIndex, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.index != nil && now.Sub(i.loadedAt) < shared.SchoolIndexMaxAge {
+		return i.index, nil
+	}
+
+	list, err := schools.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := domain.NewSpatialIndex(shared.SchoolIndexCellKm)
+	if err != nil {
+		return nil, err
+	}
+	for _, school := range list {
+		if !school.Address.Location.IsEmpty() {
+			if err := index.Insert(school.ID, school.Address.Location); err != nil {
+				return nil, fmt.Errorf("index school %s: %w", school.ID, err)
+			}
+		}
+	}
+
+	i.index, i.loadedAt = index, now
+	return index, nil
+}
+
+// put records the location of a saved school, if the index is loaded. Merged
+// and unlocated schools are dropped from it.
+func (i *schoolIndex) put(school *School) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.index == nil {
+		return
+	}
+	if school.IsMerged() || school.Address.Location.IsEmpty() {
+		i.index.Remove(school.ID)
+		return
+	}
+	if err := i.index.Insert(school.ID, school.Address.Location); err != nil {
+		i.index.Remove(school.ID)
+	}
+}
+
+// SchoolsWithin returns the schools located within radius of center, nearest
+// first, with distances expressed in unit. Schools without a location and
+// merged schools are never returned. The radius cannot exceed
+// shared.MaxSearchRadiusKm.
+func (s *Service) SchoolsWithin(ctx context.Context, center domain.Location, radius float64,
+	unit domain.DistanceUnit) ([]domain.SpatialMatch, error) {
+	verr := &shared.ValidationError{}
+	_, err := domain.NewLocation(center.Latitude, center.Longitude, center.County, center.Region)
+	verr.Merge("center", shared.CodeSchoolNearbyCenterInvalid, err)
+	if !unit.IsValid() {
+		verr.Add("unit", shared.CodeSchoolNearbyUnitInvalid, fmt.Sprintf("unknown distance unit %q", unit))
+	} else if !(radius > 0 && unit.ToKilometers(radius) <= shared.MaxSearchRadiusKm) {
+		verr.Add("radius", shared.CodeSchoolNearbyRadiusRange, fmt.Sprintf(
+			"radius must be positive and at most %.0f %s",
+			math.Floor(unit.FromKilometers(shared.MaxSearchRadiusKm)), unit))
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return nil, err
+	}
+
+	index, err := s.locations.load(ctx, s.schools, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("load school locations: %w", err)
+	}
+	return index.WithinRadius(center, radius, unit)
+}
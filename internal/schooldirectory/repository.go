@@ -0,0 +1,98 @@
+package schooldirectory
+
+import (
+	"context"
+	"fmt"
+
+	"hrh-backend/internal/shared"
+)
+
+var (
+	// ErrSchoolNotFound is returned when a school does not exist
+	ErrSchoolNotFound = fmt.Errorf("school %w", shared.ErrNotFound)
+	// ErrSchoolNCESIDTaken is returned when another school already has the NCES ID
+	ErrSchoolNCESIDTaken = fmt.Errorf("school NCES ID is already in use: %w", shared.ErrConflict)
+	// ErrSchoolVersionConflict is returned when a school was changed by someone
+	// else since it was loaded
+	ErrSchoolVersionConflict = fmt.Errorf("school was modified concurrently: %w", shared.ErrConflict)
+	// ErrSchoolMerged is returned when merging a school that was already merged
+	// into another
+	ErrSchoolMerged = fmt.Errorf("school was merged into another school: %w", shared.ErrConflict)
+
+	// ErrDistrictNotFound is returned when a district does not exist
+	ErrDistrictNotFound = fmt.Errorf("district %w", shared.ErrNotFound)
+	// ErrDistrictNCESIDTaken is returned when another district already has the
+	// NCES ID
+	ErrDistrictNCESIDTaken = fmt.Errorf("district NCES ID is already in use: %w", shared.ErrConflict)
+	// ErrDistrictVersionConflict is returned when a district was changed by
+	// someone else since it was loaded
+	ErrDistrictVersionConflict = fmt.Errorf("district was modified concurrently: %w", shared.ErrConflict)
+	// ErrNotDistrictAdmin is returned when a user acts on a district they do not
+	// administer
+	ErrNotDistrictAdmin = fmt.Errorf("user does not administer the district: %w", shared.ErrForbidden)
+
+	// ErrStateAgencyNotFound is returned when a state education agency does not
+	// exist
+	ErrStateAgencyNotFound = fmt.Errorf("state agency %w", shared.ErrNotFound)
+	// ErrStateAgencyTaken is returned when the state already has an agency
+	ErrStateAgencyTaken = fmt.Errorf("state already has an education agency: %w", shared.ErrConflict)
+)
+
+// SchoolRepository persists schools
+type SchoolRepository interface {
+	// Create stores a new school. It returns an error wrapping
+	// ErrSchoolNCESIDTaken if another school has the NCES ID.
+	Create(ctx context.Context, school *School) error
+	// GetByID returns the school, or an error wrapping ErrSchoolNotFound
+	GetByID(ctx context.Context, id string) (*School, error)
+	// GetByNCESID returns the school with the NCES ID, or an error wrapping
+	// ErrSchoolNotFound
+	GetByNCESID(ctx context.Context, ncesID string) (*School, error)
+	// Update saves the school using optimistic locking on Version, incrementing it
+	// on success. It returns an error wrapping ErrSchoolVersionConflict if the
+	// school changed since it was loaded.
+	Update(ctx context.Context, school *School) error
+	// Merge saves a school merged into survivor together with the survivor, in
+	// one step and using optimistic locking on both Versions. Schools already
+	// merged into the merged school are re-pointed at the survivor, so an alias
+	// never leads to another alias.
+	Merge(ctx context.Context, survivor, merged *School) error
+	// List returns every school that was not merged into another, ordered by ID
+	List(ctx context.Context) ([]*School, error)
+	// ListByDistrict returns the schools of a district that were not merged into
+	// another, ordered by name
+	ListByDistrict(ctx context.Context, districtID string) ([]*School, error)
+	// FindSearchCandidates returns up to query.Limit schools whose name or city
+	// may match the query, most likely first, leaving out merged schools. It may
+	// return schools that do not match; SearchSchools ranks and filters them.
+	FindSearchCandidates(ctx context.Context, query SchoolCandidateQuery) ([]*School, error)
+}
+
+// DistrictRepository persists districts together with their admins
+type DistrictRepository interface {
+	// Create stores a new district. It returns an error wrapping
+	// ErrDistrictNCESIDTaken if another district has the NCES ID.
+	Create(ctx context.Context, district *District) error
+	// GetByID returns the district, or an error wrapping ErrDistrictNotFound
+	GetByID(ctx context.Context, id string) (*District, error)
+	// GetByNCESID returns the district with the NCES LEAID, or an error wrapping
+	// ErrDistrictNotFound
+	GetByNCESID(ctx context.Context, ncesID string) (*District, error)
+	// Update saves the district and its admins using optimistic locking on
+	// Version, incrementing it on success
+	Update(ctx context.Context, district *District) error
+	// ListByStateAgency returns the districts of a state agency ordered by name
+	ListByStateAgency(ctx context.Context, stateAgencyID string) ([]*District, error)
+}
+
+// StateAgencyRepository persists state education agencies
+type StateAgencyRepository interface {
+	// Create stores a new agency. It returns an error wrapping
+	// ErrStateAgencyTaken if the state already has one.
+	Create(ctx context.Context, agency *StateAgency) error
+	// GetByID returns the agency, or an error wrapping ErrStateAgencyNotFound
+	GetByID(ctx context.Context, id string) (*StateAgency, error)
+	// GetByState returns the agency of a two-letter state code, or an error
+	// wrapping ErrStateAgencyNotFound
+	GetByState(ctx context.Context, state string) (*StateAgency, error)
+}
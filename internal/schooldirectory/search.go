@@ -0,0 +1,176 @@
+package schooldirectory
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// SchoolQuery is a school name search, as typed by a teacher signing up
+type SchoolQuery struct {
+	// Text is matched against school names and cities. It tolerates typos and
+	// common abbreviations, and its last word may be incomplete.
+	Text string `json:"text"`
+	// Near ranks schools close to the location higher
+	Near  *domain.Location `json:"near,omitempty"`
+	Limit int              `json:"limit,omitempty"`
+}
+
+// SchoolCandidateQuery is what the repository is given to preselect schools
+// for a search
+type SchoolCandidateQuery struct {
+	// Text is the query lower-cased as typed
+	Text string
+	// Words are the query words with abbreviations expanded
+	Words []string
+	// Prefix is the last word of the query when it may be incomplete, or empty
+	Prefix string
+	Limit  int
+}
+
+// SchoolMatch is a school found by a name search
+type SchoolMatch struct {
+	School *School `json:"school"`
+	// Score ranks the match: how well the name and city match the text, plus a
+	// boost for being close to the searched location
+	Score float64 `json:"score"`
+	// DistanceKm is the distance to the searched location, when both are known
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+// schoolAbbreviations maps the abbreviations common in school names to the
+// words they stand for, so "Lincoln HS" and "Lincoln High School" compare
+// equal. Ambiguous ones such as "St" (saint or street) are left alone.
+var schoolAbbreviations = map[string][]string{
+	"hs":    {"high", "school"},
+	"jhs":   {"junior", "high", "school"},
+	"ms":    {"middle", "school"},
+	"es":    {"elementary", "school"},
+	"elem":  {"elementary"},
+	"elm":   {"elementary"},
+	"mid":   {"middle"},
+	"jr":    {"junior"},
+	"sr":    {"senior"},
+	"sch":   {"school"},
+	"schl":  {"school"},
+	"acad":  {"academy"},
+	"ctr":   {"center"},
+	"intl":  {"international"},
+	"prep":  {"preparatory"},
+	"ps":    {"public", "school"},
+	"mt":    {"mount"},
+	"hts":   {"heights"},
+	"inter": {"intermediate"},
+}
+
+var schoolWordRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// schoolWords splits a school name or city into lower-case words with
+// abbreviations expanded. It also returns the last word as typed if it was not
+// an abbreviation, since it may be the start of a longer word.
+func schoolWords(text string) ([]string, string) {
+	text = strings.ToLower(strings.NewReplacer("'", "", "’", "", ".", "").Replace(text))
+
+	words := []string{}
+	last := ""
+	for _, word := range schoolWordRegex.FindAllString(text, -1) {
+		if expanded, ok := schoolAbbreviations[word]; ok {
+			words = append(words, expanded...)
+			last = ""
+			continue
+		}
+		words = append(words, word)
+		last = word
+	}
+	return words, last
+}
+
+// minTypoWordLength is the shortest word in which a typo is forgiven; shorter
+// words such as "el" or "ps" only match closely
+const minTypoWordLength = 4
+
+// wordSimilarity compares two words by trigrams and, for words long enough to
+// hold a typo, by edit distance, keeping the higher score
+func wordSimilarity(a, b string) float64 {
+	score := shared.TrigramSimilarity(a, b)
+	if len(a) >= minTypoWordLength && len(b) >= minTypoWordLength {
+		score = max(score, shared.LevenshteinSimilarity(a, b))
+	}
+	return score
+}
+
+// RankSchools scores schools against a search, keeping those whose name or city
+// covers the query well enough, best first. Each query word is paired with the
+// most similar word of the school's name or city; the last word also matches any
+// word it starts, for autocomplete. Schools near query.Near get a boost that
+// fades with distance. Ties are broken by name.
+func RankSchools(query SchoolQuery, schools []*School) []SchoolMatch {
+	words, prefix := schoolWords(query.Text)
+	if len(words) == 0 {
+		return []SchoolMatch{}
+	}
+
+	matches := []SchoolMatch{}
+	for _, school := range schools {
+		name, _ := schoolWords(school.Name)
+		city, _ := schoolWords(school.Address.City)
+
+		coverage := queryCoverage(words, prefix, append(append([]string{}, name...), city...))
+		if coverage < shared.SchoolMatchThreshold {
+			continue
+		}
+
+		// How much of the name the query covers separates "Lincoln Elementary"
+		// from "Lincoln Elementary Annex" when both match the query fully
+		nameCoverage := queryCoverage(name, "", words)
+		match := SchoolMatch{
+			School: school,
+			Score:  (1-shared.SchoolNameCoverageWeight)*coverage + shared.SchoolNameCoverageWeight*nameCoverage,
+		}
+		if query.Near != nil && !school.Address.Location.IsEmpty() {
+			distance := query.Near.DistanceTo(school.Address.Location)
+			match.DistanceKm = &distance
+			match.Score += shared.SchoolProximityWeight / (1 + distance/shared.SchoolProximityScaleKm)
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].School.Name != matches[j].School.Name {
+			return matches[i].School.Name < matches[j].School.Name
+		}
+		return matches[i].School.ID < matches[j].School.ID
+	})
+	return matches
+}
+
+// queryCoverage returns the mean over the query words of their best similarity
+// to a candidate word. The last query word fully matches a candidate word it
+// starts when prefix is set.
+func queryCoverage(query []string, prefix string, candidates []string) float64 {
+	if len(query) == 0 || len(candidates) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for idx, word := range query {
+		best := 0.0
+		for _, candidate := range candidates {
+			score := wordSimilarity(word, candidate)
+			if prefix != "" && idx == len(query)-1 && strings.HasPrefix(candidate, prefix) {
+				score = 1
+			}
+			if score > best {
+				best = score
+			}
+		}
+		total += best
+	}
+	return total / float64(len(query))
+}
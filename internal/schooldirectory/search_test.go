@@ -0,0 +1,67 @@
+package schooldirectory
+
+import (
+	"testing"
+
+	"hrh-backend/internal/shared/domain"
+)
+
+func TestRankSchools(t *testing.T) {
+	schools := []*School{
+		{ID: "1", Name: "Abraham Lincoln High School", Address: domain.Address{City: "Denver"}},
+		{ID: "2", Name: "Lincoln Elem", Address: domain.Address{City: "Springfield"}},
+		{ID: "3", Name: "Grant Middle School", Address: domain.Address{City: "Springfield"}},
+		{ID: "4", Name: "St. Mary's Academy", Address: domain.Address{City: "Boston"}},
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"exact", "Grant Middle School", []string{"3"}},
+		{"typo", "Lincon", []string{"2", "1"}},
+		{"abbreviation in query", "lincoln hs", []string{"1"}},
+		{"abbreviation in name", "lincoln elementary", []string{"2"}},
+		{"prefix", "grant mid", []string{"3"}},
+		{"incomplete last word", "lincoln elemen", []string{"2"}},
+		{"city", "springfield grant", []string{"3"}},
+		{"apostrophe", "st marys", []string{"4"}},
+		{"no match", "jefferson", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := RankSchools(SchoolQuery{Text: tt.text}, schools)
+			got := make([]string, 0, len(matches))
+			for _, match := range matches {
+				got = append(got, match.School.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("RankSchools(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Errorf("RankSchools(%q) = %v, want %v", tt.text, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRankSchools_ProximityBoost(t *testing.T) {
+	near := domain.Location{Latitude: 39.8, Longitude: -89.65}
+	schools := []*School{
+		{ID: "far", Name: "Lincoln Elementary", Address: domain.Address{Location: domain.Location{Latitude: 41.88, Longitude: -87.63}}},
+		{ID: "near", Name: "Lincoln Elementary", Address: domain.Address{Location: domain.Location{Latitude: 39.78, Longitude: -89.64}}},
+		{ID: "unlocated", Name: "Lincoln Elementary"},
+	}
+
+	matches := RankSchools(SchoolQuery{Text: "lincoln elementary", Near: &near}, schools)
+	if len(matches) != 3 || matches[0].School.ID != "near" || matches[1].School.ID != "far" {
+		t.Fatalf("RankSchools() order = %+v", matches)
+	}
+	if matches[0].DistanceKm == nil || *matches[0].DistanceKm > 5 || matches[2].DistanceKm != nil {
+		t.Errorf("distances = %v, %v", matches[0].DistanceKm, matches[2].DistanceKm)
+	}
+}
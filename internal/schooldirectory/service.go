@@ -0,0 +1,222 @@
+package schooldirectory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// Service implements the school directory use cases
+type Service struct {
+	schools   SchoolRepository
+	districts DistrictRepository
+	agencies  StateAgencyRepository
+	locations *schoolIndex
+	now       func() time.Time
+}
+
+// NewService creates a Service backed by the given repositories
+func NewService(schools SchoolRepository, districts DistrictRepository, agencies StateAgencyRepository) *Service {
+	return &Service{
+		schools:   schools,
+		districts: districts,
+		agencies:  agencies,
+		locations: &schoolIndex{},
+		now:       time.Now,
+	}
+}
+
+// GetSchool returns a school by ID. The ID of a school merged into another
+// returns the surviving school.
+func (s *Service) GetSchool(ctx context.Context, id string) (*School, error) {
+	school, err := s.schools.GetByID(ctx, id)
+	if err != nil || !school.IsMerged() {
+		return school, err
+	}
+	return s.schools.GetByID(ctx, school.MergedIntoID)
+}
+
+// FindDuplicateSchools returns the pairs of schools in the directory that are
+// likely the same school, most similar first
+func (s *Service) FindDuplicateSchools(ctx context.Context) ([]SchoolDuplicate, error) {
+	schools, err := s.schools.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find duplicate schools: %w", err)
+	}
+	return FindDuplicates(schools), nil
+}
+
+// MergeSchools merges the duplicate school into the survivor and returns the
+// survivor. The duplicate is kept as an alias that GetSchool resolves to the
+// survivor. Merging again a school already merged into the same survivor returns
+// the survivor, so a merge whose follow-up steps failed can be retried. Schools
+// with different NCES IDs cannot be merged, since NCES vouches that they differ.
+func (s *Service) MergeSchools(ctx context.Context, survivorID, duplicateID string) (*School, error) {
+	survivorID, duplicateID = strings.TrimSpace(survivorID), strings.TrimSpace(duplicateID)
+	if survivorID == duplicateID {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "duplicate_id",
+			Code:    shared.CodeSchoolMergeSame,
+			Message: "a school cannot be merged into itself",
+		})
+	}
+
+	survivor, err := s.schools.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.schools.GetByID(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate.MergedIntoID == survivor.ID {
+		return survivor, nil
+	}
+	if survivor.IsMerged() || duplicate.IsMerged() {
+		return nil, ErrSchoolMerged
+	}
+	if survivor.NCESID != "" && duplicate.NCESID != "" && survivor.NCESID != duplicate.NCESID {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "duplicate_id",
+			Code:    shared.CodeSchoolMergeNCESConflict,
+			Message: fmt.Sprintf("NCES lists %s and %s as different schools", survivor.NCESID, duplicate.NCESID),
+		})
+	}
+
+	duplicate.mergeInto(survivor, s.now().UTC())
+	if err := s.schools.Merge(ctx, survivor, duplicate); err != nil {
+		return nil, fmt.Errorf("merge schools: %w", err)
+	}
+	s.locations.put(survivor)
+	s.locations.put(duplicate)
+
+	return survivor, nil
+}
+
+// SearchSchools finds schools by name or city as a teacher types, tolerating
+// typos and abbreviations such as "HS" or "Elem", best match first. Schools
+// near query.Near rank higher.
+func (s *Service) SearchSchools(ctx context.Context, query SchoolQuery) ([]SchoolMatch, error) {
+	query.Text = strings.TrimSpace(query.Text)
+
+	verr := &shared.ValidationError{}
+	words, prefix := schoolWords(query.Text)
+	if len(words) == 0 {
+		verr.Add("text", shared.CodeSchoolSearchTextRequired, "search text is required")
+	}
+	if query.Near != nil {
+		_, err := domain.NewLocation(query.Near.Latitude, query.Near.Longitude, query.Near.County, query.Near.Region)
+		verr.Merge("near", shared.CodeSchoolSearchNearInvalid, err)
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = shared.DefaultSearchLimit
+	}
+	if query.Limit > shared.MaxSearchLimit {
+		query.Limit = shared.MaxSearchLimit
+	}
+
+	candidates, err := s.schools.FindSearchCandidates(ctx, SchoolCandidateQuery{
+		Text:   strings.ToLower(query.Text),
+		Words:  words,
+		Prefix: prefix,
+		Limit:  shared.MaxSchoolSearchCandidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search schools: %w", err)
+	}
+
+	matches := RankSchools(query, candidates)
+	if len(matches) > query.Limit {
+		matches = matches[:query.Limit]
+	}
+	return matches, nil
+}
+
+// NCESUpsertResult counts what an NCES import did to the directory
+type NCESUpsertResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// UpsertNCESSchools creates or refreshes schools from parsed NCES records,
+// matching existing schools by NCES ID. Records without an NCES ID are ignored.
+// A school whose NCES LEAID matches a known district is linked to it.
+// The import stops at the first storage error; schools saved before it stay
+// saved and are counted in the result.
+func (s *Service) UpsertNCESSchools(ctx context.Context, records []School) (NCESUpsertResult, error) {
+	var result NCESUpsertResult
+	districts := make(map[string]*District)
+	for _, record := range records {
+		if record.NCESID == "" {
+			continue
+		}
+
+		district, err := s.districtByNCESID(ctx, record.DistrictNCESID, districts)
+		if err != nil {
+			return result, err
+		}
+
+		now := s.now().UTC()
+		school, err := s.schools.GetByNCESID(ctx, record.NCESID)
+		if errors.Is(err, ErrSchoolNotFound) {
+			school = &School{ID: shared.NewID(), Version: 1, CreatedAt: now}
+			school.applyNCES(record, district)
+			school.UpdatedAt = now
+			if err := s.schools.Create(ctx, school); err != nil {
+				return result, fmt.Errorf("create school %s: %w", record.NCESID, err)
+			}
+			s.locations.put(school)
+			result.Created++
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("get school %s: %w", record.NCESID, err)
+		}
+
+		if !school.applyNCES(record, district) {
+			result.Unchanged++
+			continue
+		}
+		school.UpdatedAt = now
+		if err := s.schools.Update(ctx, school); err != nil {
+			return result, fmt.Errorf("update school %s: %w", record.NCESID, err)
+		}
+		s.locations.put(school)
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// districtByNCESID returns the district with an NCES LEAID, or nil if there is
+// none. Lookups are cached, including misses, for the length of one import.
+func (s *Service) districtByNCESID(ctx context.Context, ncesID string,
+	cache map[string]*District) (*District, error) {
+	if ncesID == "" {
+		return nil, nil
+	}
+	if district, ok := cache[ncesID]; ok {
+		return district, nil
+	}
+
+	district, err := s.districts.GetByNCESID(ctx, ncesID)
+	if errors.Is(err, ErrDistrictNotFound) {
+		district, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get district %s: %w", ncesID, err)
+	}
+
+	cache[ncesID] = district
+	return district, nil
+}
@@ -0,0 +1,361 @@
+package schooldirectory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/shared"
+	"hrh-backend/internal/shared/domain"
+)
+
+// memorySchoolRepository is an in-memory SchoolRepository for service tests
+type memorySchoolRepository struct {
+	schools map[string]*School
+}
+
+func (r *memorySchoolRepository) GetByID(_ context.Context, id string) (*School, error) {
+	school, ok := r.schools[id]
+	if !ok {
+		return nil, ErrSchoolNotFound
+	}
+	clone := *school
+	return &clone, nil
+}
+
+func (r *memorySchoolRepository) Create(_ context.Context, school *School) error {
+	if _, err := r.GetByNCESID(context.Background(), school.NCESID); school.NCESID != "" && err == nil {
+		return ErrSchoolNCESIDTaken
+	}
+	clone := *school
+	r.schools[school.ID] = &clone
+	return nil
+}
+
+func (r *memorySchoolRepository) GetByNCESID(_ context.Context, ncesID string) (*School, error) {
+	for _, school := range r.schools {
+		if school.NCESID == ncesID {
+			clone := *school
+			return &clone, nil
+		}
+	}
+	return nil, ErrSchoolNotFound
+}
+
+func (r *memorySchoolRepository) ListByDistrict(_ context.Context, districtID string) ([]*School, error) {
+	schools := []*School{}
+	for _, school := range r.schools {
+		if school.DistrictID == districtID && !school.IsMerged() {
+			clone := *school
+			schools = append(schools, &clone)
+		}
+	}
+	sort.Slice(schools, func(i, j int) bool { return schools[i].Name < schools[j].Name })
+	return schools, nil
+}
+
+func (r *memorySchoolRepository) FindSearchCandidates(_ context.Context,
+	query SchoolCandidateQuery) ([]*School, error) {
+	return r.List(context.Background())
+}
+
+func (r *memorySchoolRepository) List(_ context.Context) ([]*School, error) {
+	schools := []*School{}
+	for _, school := range r.schools {
+		if !school.IsMerged() {
+			clone := *school
+			schools = append(schools, &clone)
+		}
+	}
+	sort.Slice(schools, func(i, j int) bool { return schools[i].ID < schools[j].ID })
+	return schools, nil
+}
+
+func (r *memorySchoolRepository) Merge(_ context.Context, survivor, merged *School) error {
+	if r.schools[survivor.ID].Version != survivor.Version || r.schools[merged.ID].Version != merged.Version {
+		return ErrSchoolVersionConflict
+	}
+	for _, school := range r.schools {
+		if school.MergedIntoID == merged.ID {
+			school.MergedIntoID = survivor.ID
+		}
+	}
+	for _, school := range []*School{survivor, merged} {
+		school.Version++
+		clone := *school
+		r.schools[school.ID] = &clone
+	}
+	return nil
+}
+
+func (r *memorySchoolRepository) Update(_ context.Context, school *School) error {
+	if r.schools[school.ID].Version != school.Version {
+		return ErrSchoolVersionConflict
+	}
+	school.Version++
+	clone := *school
+	r.schools[school.ID] = &clone
+	return nil
+}
+
+func TestService_GetSchool(t *testing.T) {
+	repo := &memorySchoolRepository{schools: map[string]*School{
+		"school-1": {ID: "school-1", Name: "Lincoln Elementary"},
+	}}
+	service := NewService(repo, newMemoryDistrictRepository(), newMemoryStateAgencyRepository())
+
+	school, err := service.GetSchool(context.Background(), "school-1")
+	if err != nil || school.Name != "Lincoln Elementary" {
+		t.Errorf("GetSchool() = %+v, %v", school, err)
+	}
+
+	if _, err := service.GetSchool(context.Background(), "school-9"); !errors.Is(err, shared.ErrNotFound) {
+		t.Errorf("GetSchool() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestService_UpsertNCESSchools(t *testing.T) {
+	located := domain.Address{
+		Street: "100 Main St", City: "Springfield", State: "IL", ZipCode: "62701",
+		Location: domain.Location{Latitude: 39.8, Longitude: -89.65, County: "Sangamon County"},
+	}
+	frlPercent := 64.2
+	repo := &memorySchoolRepository{schools: map[string]*School{
+		"school-1": {ID: "school-1", NCESID: "170000100001", Name: "Lincoln Elem", Address: located,
+			Enrollment: 350, FRLPercent: &frlPercent, TitleIStatus: TitleITargeted, Version: 1},
+		"school-2": {ID: "school-2", NCESID: "170000100002", Name: "Grant Middle School", Sector: SectorPublic, Version: 3},
+	}}
+	districts := newMemoryDistrictRepository(&District{
+		ID: "district-1", StateAgencyID: "agency-il", NCESID: "1709930", Name: "Springfield SD 186", Version: 1,
+	})
+	service := NewService(repo, districts, newMemoryStateAgencyRepository())
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	unlocated := located
+	unlocated.Location = domain.Location{}
+	result, err := service.UpsertNCESSchools(context.Background(), []School{
+		{NCESID: "170000100001", Name: "Lincoln Elementary School", Sector: SectorPublic, Address: unlocated,
+			DistrictNCESID: "1709930", DistrictName: "SPRINGFIELD SD 186"},
+		{NCESID: "170000100002", Name: "Grant Middle School", Sector: SectorPublic},
+		{NCESID: "A1234567", Name: "St. Agnes School", Sector: SectorPrivate},
+		{Name: "No ID Academy"},
+	})
+	if err != nil {
+		t.Fatalf("UpsertNCESSchools() error = %v", err)
+	}
+	if want := (NCESUpsertResult{Created: 1, Updated: 1, Unchanged: 1}); result != want {
+		t.Errorf("UpsertNCESSchools() = %+v, want %+v", result, want)
+	}
+
+	updated := repo.schools["school-1"]
+	if updated.Name != "Lincoln Elementary School" || updated.Version != 2 || !updated.UpdatedAt.Equal(now) {
+		t.Errorf("updated school = %+v", updated)
+	}
+	if updated.DistrictID != "district-1" || updated.DistrictName != "Springfield SD 186" {
+		t.Errorf("district = %s %q, want linked to district-1", updated.DistrictID, updated.DistrictName)
+	}
+	if !updated.Address.Location.Equals(located.Location) {
+		t.Errorf("location = %v, want the existing location kept", updated.Address.Location)
+	}
+	if updated.Enrollment != 350 || updated.FRLPercent == nil || *updated.FRLPercent != frlPercent ||
+		updated.TitleIStatus != TitleITargeted {
+		t.Errorf("school = %+v, want the attributes missing from the record kept", updated)
+	}
+	if repo.schools["school-2"].Version != 3 {
+		t.Errorf("unchanged school was saved")
+	}
+
+	created, err := repo.GetByNCESID(context.Background(), "A1234567")
+	if err != nil || created.ID == "" || created.Version != 1 || created.Sector != SectorPrivate {
+		t.Errorf("created school = %+v, %v", created, err)
+	}
+}
+
+func TestService_SearchSchools(t *testing.T) {
+	springfield := domain.Location{Latitude: 39.8, Longitude: -89.65}
+	chicago := domain.Location{Latitude: 41.88, Longitude: -87.63}
+	repo := &memorySchoolRepository{schools: map[string]*School{
+		"school-1": {ID: "school-1", Name: "Lincoln Elementary School",
+			Address: domain.Address{City: "Chicago", State: "IL", Location: chicago}},
+		"school-2": {ID: "school-2", Name: "Lincoln Elementary School",
+			Address: domain.Address{City: "Springfield", State: "IL", Location: springfield}},
+		"school-3": {ID: "school-3", Name: "Washington HS", Address: domain.Address{City: "Springfield", State: "IL"}},
+	}}
+	service := NewService(repo, newMemoryDistrictRepository(), newMemoryStateAgencyRepository())
+
+	matches, err := service.SearchSchools(context.Background(), SchoolQuery{Text: "lincon elem", Near: &springfield})
+	if err != nil {
+		t.Fatalf("SearchSchools() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].School.ID != "school-2" || matches[0].DistanceKm == nil {
+		t.Fatalf("SearchSchools() = %+v, want the Springfield school first", matches)
+	}
+
+	matches, err = service.SearchSchools(context.Background(), SchoolQuery{Text: "washington high", Limit: 1})
+	if err != nil || len(matches) != 1 || matches[0].School.ID != "school-3" {
+		t.Errorf("SearchSchools() = %+v, %v", matches, err)
+	}
+
+	_, err = service.SearchSchools(context.Background(), SchoolQuery{Text: " .. ", Near: &domain.Location{Latitude: 91}})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeSchoolSearchTextRequired) ||
+		!verr.HasCode(shared.CodeLocationLatitudeRange) {
+		t.Errorf("SearchSchools() error = %v", err)
+	}
+}
+
+func TestService_SchoolsWithin(t *testing.T) {
+	springfield := domain.Location{Latitude: 39.7817, Longitude: -89.6501}
+	repo := &memorySchoolRepository{schools: map[string]*School{
+		"chatham": {ID: "chatham", Name: "Chatham Elementary",
+			Address: domain.Address{Location: domain.Location{Latitude: 39.6762, Longitude: -89.7043}}, Version: 1},
+		"decatur": {ID: "decatur", NCESID: "171128001418", Name: "Decatur High School",
+			Address: domain.Address{Location: domain.Location{Latitude: 39.8403, Longitude: -88.9548}}, Version: 1},
+		"chicago": {ID: "chicago", Name: "Lincoln Park High School",
+			Address: domain.Address{City: "Chicago", State: "IL",
+				Location: domain.Location{Latitude: 41.8781, Longitude: -87.6298}}, Version: 1},
+		"unlocated": {ID: "unlocated", Name: "Springfield Academy", Version: 1},
+	}}
+	service := NewService(repo, newMemoryDistrictRepository(), newMemoryStateAgencyRepository())
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	matchIDs := func(radius float64, unit domain.DistanceUnit) []string {
+		t.Helper()
+		matches, err := service.SchoolsWithin(ctx, springfield, radius, unit)
+		if err != nil {
+			t.Fatalf("SchoolsWithin() error = %v", err)
+		}
+		ids := []string{}
+		for _, match := range matches {
+			ids = append(ids, match.ID)
+		}
+		return ids
+	}
+
+	if ids := matchIDs(80, domain.Kilometers); !slices.Equal(ids, []string{"chatham", "decatur"}) {
+		t.Errorf("SchoolsWithin(80 km) = %v, want [chatham decatur]", ids)
+	}
+
+	// Schools this service saves are reflected without a reload
+	if _, err := service.UpsertNCESSchools(ctx, []School{{NCESID: "171128001418", Name: "Decatur High School",
+		Address: domain.Address{Location: domain.Location{Latitude: 41.0, Longitude: -88.0}}}}); err != nil {
+		t.Fatalf("UpsertNCESSchools() error = %v", err)
+	}
+	if _, err := service.MergeSchools(ctx, "chicago", "chatham"); err != nil {
+		t.Fatalf("MergeSchools() error = %v", err)
+	}
+	if ids := matchIDs(80, domain.Kilometers); len(ids) != 0 {
+		t.Errorf("SchoolsWithin(80 km) after changes = %v, want none", ids)
+	}
+
+	// Schools saved elsewhere show up once the index is reloaded
+	repo.schools["new"] = &School{ID: "new", Name: "Springfield Southeast High School",
+		Address: domain.Address{Location: domain.Location{Latitude: 39.75, Longitude: -89.6}}}
+	if ids := matchIDs(10, domain.Miles); len(ids) != 0 {
+		t.Errorf("SchoolsWithin(10 mi) before reload = %v, want none", ids)
+	}
+	now = now.Add(shared.SchoolIndexMaxAge)
+	if ids := matchIDs(10, domain.Miles); !slices.Equal(ids, []string{"new"}) {
+		t.Errorf("SchoolsWithin(10 mi) after reload = %v, want [new]", ids)
+	}
+
+	_, err := service.SchoolsWithin(ctx, domain.Location{Latitude: 91}, 300, domain.Kilometers)
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeLocationLatitudeRange) ||
+		!verr.HasCode(shared.CodeSchoolNearbyRadiusRange) {
+		t.Errorf("SchoolsWithin() error = %v", err)
+	}
+	_, err = service.SchoolsWithin(ctx, springfield, 10, "ft")
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeSchoolNearbyUnitInvalid) {
+		t.Errorf("SchoolsWithin() error = %v, want %s", err, shared.CodeSchoolNearbyUnitInvalid)
+	}
+}
+
+func TestService_MergeSchools(t *testing.T) {
+	located := domain.Address{
+		Street: "100 Main St", City: "Springfield", State: "IL", ZipCode: "62701",
+		Location: domain.Location{Latitude: 39.8, Longitude: -89.65},
+	}
+	repo := &memorySchoolRepository{schools: map[string]*School{
+		"survivor": {ID: "survivor", Name: "Lincoln Elementary School",
+			Address: domain.Address{Street: "100 MAIN STREET", City: "Springfield", State: "IL", ZipCode: "62701"},
+			Version: 1},
+		"duplicate": {ID: "duplicate", NCESID: "170993000708", Name: "Lincoln Elem", Address: located,
+			DistrictID: "district-1", DistrictName: "Springfield SD 186", Version: 2},
+		"alias": {ID: "alias", Name: "Lincoln Elementary", MergedIntoID: "duplicate", Version: 1},
+	}}
+	service := NewService(repo, newMemoryDistrictRepository(), newMemoryStateAgencyRepository())
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	survivor, err := service.MergeSchools(ctx, " survivor ", "duplicate")
+	if err != nil {
+		t.Fatalf("MergeSchools() error = %v", err)
+	}
+	if survivor.NCESID != "170993000708" || survivor.DistrictID != "district-1" ||
+		!survivor.Address.Location.Equals(located.Location) || survivor.Version != 2 {
+		t.Errorf("survivor = %+v, want the duplicate's NCES ID, district and location", survivor)
+	}
+
+	merged := repo.schools["duplicate"]
+	if merged.MergedIntoID != "survivor" || merged.NCESID != "" || merged.MergedAt == nil || !merged.MergedAt.Equal(now) {
+		t.Errorf("merged school = %+v", merged)
+	}
+	if repo.schools["alias"].MergedIntoID != "survivor" {
+		t.Errorf("alias MergedIntoID = %s, want it re-pointed at the survivor", repo.schools["alias"].MergedIntoID)
+	}
+
+	for _, id := range []string{"duplicate", "alias"} {
+		if school, err := service.GetSchool(ctx, id); err != nil || school.ID != "survivor" {
+			t.Errorf("GetSchool(%s) = %+v, %v, want the survivor", id, school, err)
+		}
+	}
+
+	// A retry of the same merge is a no-op
+	if again, err := service.MergeSchools(ctx, "survivor", "duplicate"); err != nil || again.Version != 2 {
+		t.Errorf("MergeSchools() again = %+v, %v", again, err)
+	}
+}
+
+func TestService_MergeSchools_Errors(t *testing.T) {
+	tests := []struct {
+		name                    string
+		survivorID, duplicateID string
+		wantErr                 error
+		code                    string
+	}{
+		{"same school", "school-1", " school-1 ", shared.ErrValidation, shared.CodeSchoolMergeSame},
+		{"different NCES IDs", "school-1", "school-2", shared.ErrValidation, shared.CodeSchoolMergeNCESConflict},
+		{"merged survivor", "merged", "school-3", ErrSchoolMerged, ""},
+		{"merged duplicate", "school-3", "merged", ErrSchoolMerged, ""},
+		{"unknown school", "school-1", "school-9", ErrSchoolNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memorySchoolRepository{schools: map[string]*School{
+				"school-1": {ID: "school-1", NCESID: "170000100001", Name: "Lincoln Elementary", Version: 1},
+				"school-2": {ID: "school-2", NCESID: "170000100002", Name: "Lincoln Elementary", Version: 1},
+				"school-3": {ID: "school-3", Name: "Lincoln Elementary", Version: 1},
+				"merged":   {ID: "merged", Name: "Lincoln Elem", MergedIntoID: "school-1", Version: 2},
+			}}
+			service := NewService(repo, newMemoryDistrictRepository(), newMemoryStateAgencyRepository())
+
+			_, err := service.MergeSchools(context.Background(), tt.survivorID, tt.duplicateID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MergeSchools() error = %v, want %v", err, tt.wantErr)
+			}
+			var verr *shared.ValidationError
+			if tt.code != "" && (!errors.As(err, &verr) || !verr.HasCode(tt.code)) {
+				t.Errorf("MergeSchools() error = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package shared
+
+import "time"
+
+// Validation error codes reported in FieldError.Code. Codes are part of the API
+// contract: the front end localizes messages by code, so existing codes must not
+// be renamed.
+const (
+	CodeAddressCityRequired      = "address.city.required"
+	CodeAddressStateRequired     = "address.state.required"
+	CodeAddressStateFormat       = "address.state.format"
+	CodeAddressStateUnknown      = "address.state.unknown"
+	CodeAddressZipCodeFormat     = "address.zip_code.format"
+	CodeAddressZipCodeStateMatch = "address.zip_code.state_mismatch"
+	CodeAddressLocationInvalid   = "address.location.invalid"
+
+	CodeLocationLatitudeRange  = "location.latitude.range"
+	CodeLocationLongitudeRange = "location.longitude.range"
+)
+
+// Limits applied to teacher wishlists
+const (
+	// MaxWishlistItems is the maximum number of line items on one wishlist
+	MaxWishlistItems = 200
+	// MaxItemQuantity is the maximum quantity that can be requested for one item
+	MaxItemQuantity = 10000
+	// MaxWishlistTitleLength is the maximum length of a wishlist title, in characters
+	MaxWishlistTitleLength = 120
+	// MaxItemNameLength is the maximum length of an item name, in characters
+	MaxItemNameLength = 200
+	// MaxItemUnitPriceCents is the highest unit price of an item ($1,000,000). It
+	// keeps wishlist totals, and the basis-point math on them, well within int64.
+	MaxItemUnitPriceCents = 100_000_000
+	// MaxWishlistSubjectLength is the maximum length of a wishlist subject, in characters
+	MaxWishlistSubjectLength = 80
+	// MaxWishlistStudentCount is the largest student count a wishlist can give
+	MaxWishlistStudentCount = 1000
+	// DefaultMaxWishlistsPerTeacher is how many wishlists that are not archived a
+	// teacher can have unless the service is configured otherwise
+	DefaultMaxWishlistsPerTeacher = 10
+)
+
+// Validation error codes for wishlists and their items
+const (
+	CodeWishlistTeacherRequired = "wishlist.teacher_id.required"
+	CodeWishlistTitleRequired   = "wishlist.title.required"
+	CodeWishlistTitleLength     = "wishlist.title.length"
+	CodeWishlistItemsLimit      = "wishlist.items.limit"
+	CodeWishlistItemsDuplicate  = "wishlist.items.duplicate"
+	CodeWishlistItemInvalid     = "wishlist.items.invalid"
+	CodeWishlistExpireAtOrder   = "wishlist.expire_at.order"
+	CodeWishlistGradeLevel      = "wishlist.grade_level.invalid"
+	CodeWishlistSubjectLength   = "wishlist.subject.length"
+	CodeWishlistStudentCount    = "wishlist.student_count.range"
+	CodeWishlistVisibility      = "wishlist.visibility.invalid"
+
+	CodeItemIDUnknown           = "wishlist_item.id.unknown"
+	CodeItemNameRequired        = "wishlist_item.name.required"
+	CodeItemNameLength          = "wishlist_item.name.length"
+	CodeItemQuantityRange       = "wishlist_item.quantity_requested.range"
+	CodeItemQuantityBelowFilled = "wishlist_item.quantity_requested.below_fulfilled"
+	CodeItemFulfilledRange      = "wishlist_item.quantity_fulfilled.range"
+	CodeItemPledgedRange        = "wishlist_item.quantity_pledged.range"
+	CodeItemUnitPriceNegative   = "wishlist_item.unit_price_cents.negative"
+	CodeItemUnitPriceMax        = "wishlist_item.unit_price_cents.max"
+	CodeItemPriorityInvalid     = "wishlist_item.priority.invalid"
+	CodeItemCategoryInvalid     = "wishlist_item.category.invalid"
+	CodeItemProductURLFormat    = "wishlist_item.product_url.format"
+	CodeItemFulfilledRemoval    = "wishlist_item.fulfilled.removed"
+	CodeItemFulfillmentQuantity = "wishlist_item.fulfillment.quantity"
+	CodeItemCatalogUnknown      = "wishlist_item.catalog_item_id.unknown"
+)
+
+// Limits and validation error codes for importing retailer list exports
+const (
+	// MaxImportFileBytes is the largest retailer export file that can be imported
+	MaxImportFileBytes = 2 << 20
+
+	CodeImportFormatUnsupported = "wishlist_import.format.unsupported"
+	CodeImportFileSize          = "wishlist_import.file.size"
+	CodeImportFileUnreadable    = "wishlist_import.file.unreadable"
+	CodeImportNameColumnMissing = "wishlist_import.file.name_column_missing"
+	CodeImportNoItems           = "wishlist_import.file.no_items"
+	CodeImportRowMalformed      = "wishlist_import.row.malformed"
+	CodeImportQuantityFormat    = "wishlist_import.row.quantity_format"
+	CodeImportPriceFormat       = "wishlist_import.row.price_format"
+)
+
+// Wishlist lifecycle timing
+const (
+	// SchoolYearEndMonth is the month whose first day ends a school year; published
+	// wishlists expire then unless the teacher picks another date
+	SchoolYearEndMonth = time.July
+	// WishlistArchiveInterval is how often expired wishlists are archived
+	WishlistArchiveInterval = time.Hour
+)
+
+// Limits applied to public search
+const (
+	// DefaultSearchLimit is the page size used when a search does not set one
+	DefaultSearchLimit = 20
+	// MaxSearchLimit is the largest page size a search can request
+	MaxSearchLimit = 100
+
+	CodeSearchSortInvalid         = "search.sort.invalid"
+	CodeSearchStateInvalid        = "search.state.invalid"
+	CodeSearchCountyRequiresState = "search.county.state_required"
+	CodeSearchSchoolTypeInvalid   = "search.school_type.invalid"
+	CodeSearchLocaleTypeInvalid   = "search.locale_type.invalid"
+	CodeSearchGradeInvalid        = "search.grade.invalid"
+	CodeSearchFRLPercentRange     = "search.min_frl_percent.range"
+	CodeSearchEnrollmentRange     = "search.enrollment.range"
+	CodeSearchNearInvalid         = "search.near.invalid"
+	CodeSearchRadiusRange         = "search.radius.range"
+	CodeSearchUnitInvalid         = "search.unit.invalid"
+	CodeSearchSortRequiresNear    = "search.sort.near_required"
+)
+
+// Pledge hold timing
+const (
+	// DefaultPledgeHoldDuration is how long a donor's claim reserves units before
+	// it expires unless the teacher confirms delivery
+	DefaultPledgeHoldDuration = 7 * 24 * time.Hour
+	// PledgeExpiryInterval is how often lapsed pledge holds are expired
+	PledgeExpiryInterval = 5 * time.Minute
+)
+
+// Validation error codes for donor pledges
+const (
+	CodePledgeDonorNameRequired = "pledge.donor_name.required"
+	CodePledgeDonorEmailFormat  = "pledge.donor_email.format"
+	CodePledgeQuantityRange     = "pledge.quantity.range"
+)
+
+// Validation error codes for teachers and their verification
+const (
+	CodeTeacherSchoolRequired   = "teacher.school_id.required"
+	CodeTeacherSchoolUnchanged  = "teacher.school_id.unchanged"
+	CodeTeacherSchoolUnknown    = "teacher.school_id.unknown"
+	CodeTeacherNameRequired     = "teacher.name.required"
+	CodeTeacherEmailFormat      = "teacher.email.format"
+	CodeTeacherValidationStatus = "teacher.validation_state.invalid"
+
+	CodeTransferWishlistsInvalid = "transfer.wishlists.invalid"
+
+	CodeValidationActorRequired  = "validation_transition.actor.required"
+	CodeValidationReasonRequired = "validation_transition.reason.required"
+)
+
+// Validation error codes for schools
+const (
+	CodeSchoolNameRequired   = "school.name.required"
+	CodeSchoolAddressInvalid = "school.address.invalid"
+	CodeSchoolNCESIDFormat   = "school.nces_id.format"
+	CodeSchoolSectorInvalid  = "school.sector.invalid"
+	CodeSchoolGradeInvalid   = "school.grade.invalid"
+	CodeSchoolGradeSpan      = "school.grade.span"
+	CodeSchoolLocaleCode     = "school.locale_code.invalid"
+
+	CodeSchoolLocaleType         = "school.locale_type.invalid"
+	CodeSchoolLocaleTypeMismatch = "school.locale_type.code_mismatch"
+	CodeSchoolTypeInvalid        = "school.school_type.invalid"
+	CodeSchoolTypeSector         = "school.school_type.sector_mismatch"
+	CodeSchoolEnrollmentRange    = "school.enrollment.range"
+	CodeSchoolTitleIStatus       = "school.title_i_status.invalid"
+	CodeSchoolFRLPercentRange    = "school.frl_percent.range"
+)
+
+// MaxSchoolEnrollment is the largest enrollment a school can report; the
+// biggest US schools, mostly online, have a few tens of thousands of students
+const MaxSchoolEnrollment = 100_000
+
+// Tuning of the school name search
+const (
+	// SchoolMatchThreshold is the lowest share, from 0 to 1, of the query a
+	// school's name and city must match for the school to be returned
+	SchoolMatchThreshold = 0.7
+	// SchoolNameCoverageWeight is the part of a school's text score given to how
+	// much of its name the query covers, which ranks shorter exact names first
+	SchoolNameCoverageWeight = 0.15
+	// SchoolProximityWeight is the score boost of a school at the searched
+	// location. It halves at SchoolProximityScaleKm and keeps fading with distance.
+	SchoolProximityWeight = 0.3
+	// SchoolProximityScaleKm is the distance at which the proximity boost halves
+	SchoolProximityScaleKm = 25.0
+	// MaxSchoolSearchCandidates is how many schools the repository preselects
+	// for ranking
+	MaxSchoolSearchCandidates = 500
+
+	CodeSchoolSearchTextRequired = "school_search.text.required"
+	CodeSchoolSearchNearInvalid  = "school_search.near.invalid"
+)
+
+// Tuning of school duplicate detection
+const (
+	// SchoolDuplicateNameThreshold is the lowest similarity, from 0 to 1, of two
+	// normalized school names for the schools to be reported as duplicates
+	SchoolDuplicateNameThreshold = 0.8
+	// SchoolDuplicateCityNameThreshold is the stricter name similarity needed
+	// when being in the same city is the only evidence two schools are the same
+	SchoolDuplicateCityNameThreshold = 0.95
+	// SchoolDuplicateDistanceKm is how close two schools' locations must be for
+	// them to count as the same site
+	SchoolDuplicateDistanceKm = 0.25
+
+	CodeSchoolMergeSame         = "school_merge.duplicate_id.same"
+	CodeSchoolMergeNCESConflict = "school_merge.nces_id.conflict"
+)
+
+// Tuning of radius search over schools
+const (
+	// SchoolIndexCellKm is the size of the grid cells school locations are
+	// indexed in
+	SchoolIndexCellKm = 10.0
+	// SchoolIndexMaxAge is how long the school location index is trusted before
+	// it is reloaded, so schools saved by other instances show up
+	SchoolIndexMaxAge = 15 * time.Minute
+	// MaxSearchRadiusKm is the largest radius a radius search can request
+	MaxSearchRadiusKm = 250.0
+
+	CodeSchoolNearbyCenterInvalid = "school_nearby.center.invalid"
+	CodeSchoolNearbyRadiusRange   = "school_nearby.radius.range"
+	CodeSchoolNearbyUnitInvalid   = "school_nearby.unit.invalid"
+)
+
+// Validation error codes for districts and state education agencies
+const (
+	CodeDistrictStateAgencyRequired = "district.state_agency_id.required"
+	CodeDistrictStateMismatch       = "district.address.state_mismatch"
+	CodeDistrictNameRequired        = "district.name.required"
+	CodeDistrictNCESIDFormat        = "district.nces_id.format"
+	CodeDistrictAddressInvalid      = "district.address.invalid"
+	CodeDistrictBoundaryInvalid     = "district.boundary.invalid"
+	CodeDistrictSchoolState         = "district_school.address.state_mismatch"
+
+	CodeStateAgencyStateInvalid    = "state_agency.state.invalid"
+	CodeStateAgencyNameRequired    = "state_agency.name.required"
+	CodeStateAgencyAddressInvalid  = "state_agency.address.invalid"
+	CodeStateAgencyBoundaryInvalid = "state_agency.boundary.invalid"
+)
+
+// Validation error codes for importing NCES school files
+const (
+	CodeNCESFileUnreadable   = "nces_import.file.unreadable"
+	CodeNCESColumnMissing    = "nces_import.file.column_missing"
+	CodeNCESRowMalformed     = "nces_import.row.malformed"
+	CodeNCESCoordinateFormat = "nces_import.row.coordinate_format"
+)
+
+// Limits applied to the item catalog
+const (
+	// MaxCatalogAliases is the maximum number of aliases of one catalog item
+	MaxCatalogAliases = 25
+	// CatalogMatchThreshold is the lowest similarity, from 0 to 1, at which a
+	// free-text item name is linked to a catalog item
+	CatalogMatchThreshold = 0.6
+	// CatalogSuggestThreshold is the lowest similarity at which a catalog item is
+	// suggested while a teacher types an item name
+	CatalogSuggestThreshold = 0.3
+)
+
+// Validation error codes for catalog items
+const (
+	CodeCatalogNameRequired    = "catalog_item.name.required"
+	CodeCatalogNameLength      = "catalog_item.name.length"
+	CodeCatalogCategoryInvalid = "catalog_item.category.invalid"
+	CodeCatalogPriceRange      = "catalog_item.typical_price_cents.range"
+	CodeCatalogAliasesLimit    = "catalog_item.aliases.limit"
+	CodeCatalogAliasInvalid    = "catalog_item.aliases.invalid"
+)
@@ -1,9 +1,9 @@
 package domain
 
 import (
-	"errors"
-	"regexp"
 	"strings"
+
+	"hrh-backend/internal/shared"
 )
 
 // Address represents a physical address with standardized components
@@ -16,12 +16,13 @@ type Address struct {
 	Location Location `json:"location"`
 }
 
-// NewAddress creates a new Address with validation
+// NewAddress creates a new Address with validation. The state may be given as
+// a USPS code or a full name; it is stored as the code, so "Texas" becomes "TX".
 func NewAddress(street, city, state, zipCode string, location Location) (Address, error) {
 	addr := Address{
 		Street:   strings.TrimSpace(street),
 		City:     strings.TrimSpace(city),
-		State:    strings.TrimSpace(state),
+		State:    normalizeState(state),
 		ZipCode:  strings.TrimSpace(zipCode),
 		Location: location,
 	}
@@ -33,6 +34,15 @@ func NewAddress(street, city, state, zipCode string, location Location) (Address
 	return addr, nil
 }
 
+// normalizeState returns the USPS code of a state given by code or full name, or
+// the value trimmed and uppercased when it names no state
+func normalizeState(state string) string {
+	if code, err := StateCode(state); err == nil {
+		return code
+	}
+	return strings.ToUpper(strings.TrimSpace(state))
+}
+
 // WithStreet returns a new Address with updated street
 func (a Address) WithStreet(street string) (Address, error) {
 	return NewAddress(street, a.City, a.State, a.ZipCode, a.Location)
@@ -58,30 +68,45 @@ func (a Address) WithLocation(location Location) (Address, error) {
 	return NewAddress(a.Street, a.City, a.State, a.ZipCode, location)
 }
 
-// validate performs validation on address components
+// validate performs validation on address components, reporting every invalid
+// field as a *shared.ValidationError
 func (a Address) validate() error {
-	if a.City == "" {
-		return errors.New("city is required")
-	}
+	verr := &shared.ValidationError{}
 
-	if a.State == "" {
-		return errors.New("state is required")
+	if a.City == "" {
+		verr.Add("city", shared.CodeAddressCityRequired, "city is required")
 	}
 
 	// Validate state format (2-letter state code)
-	if len(a.State) != 2 {
-		return errors.New("state must be a 2-letter code")
+	stateValid := false
+	switch {
+	case a.State == "":
+		verr.Add("state", shared.CodeAddressStateRequired, "state is required")
+	case len(a.State) != 2:
+		verr.Add("state", shared.CodeAddressStateFormat, "state must be a 2-letter code")
+	case !IsValidStateCode(a.State):
+		verr.Add("state", shared.CodeAddressStateUnknown, "state must be a valid US state or territory code")
+	default:
+		stateValid = true
 	}
 
 	// Validate zip code format (basic US zip code validation)
 	if a.ZipCode != "" {
-		zipRegex := regexp.MustCompile(`^\d{5}(-\d{4})?$`)
-		if !zipRegex.MatchString(a.ZipCode) {
-			return errors.New("zip code must be in format 12345 or 12345-6789")
+		if !zipCodeRegex.MatchString(a.ZipCode) {
+			verr.Add("zip_code", shared.CodeAddressZipCodeFormat, "zip code must be in format 12345 or 12345-6789")
+		} else if stateValid {
+			// The first three digits must belong to the state
+			if err := ValidateZipForState(a.ZipCode, a.State); err != nil {
+				verr.Add("zip_code", shared.CodeAddressZipCodeStateMatch, err.Error())
+			}
 		}
 	}
 
-	return nil
+	if !a.Location.IsEmpty() {
+		verr.Merge("location", shared.CodeAddressLocationInvalid, a.Location.validate())
+	}
+
+	return verr.ErrOrNil()
 }
 
 // IsEmpty returns true if the address has no meaningful data
@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"strings"
+)
+
+// streetSuffixes maps common street suffixes and their variants to the USPS
+// Publication 28 (Appendix C1) standard abbreviation
+var streetSuffixes = map[string]string{
+	"ALLEY": "ALY", "ALLEE": "ALY", "ALLY": "ALY", "ALY": "ALY",
+	"AVENUE": "AVE", "AV": "AVE", "AVE": "AVE", "AVEN": "AVE", "AVENU": "AVE", "AVN": "AVE", "AVNUE": "AVE",
+	"BEND": "BND", "BND": "BND",
+	"BOULEVARD": "BLVD", "BLVD": "BLVD", "BOUL": "BLVD", "BOULV": "BLVD",
+	"BYPASS": "BYP", "BYP": "BYP", "BYPA": "BYP", "BYPAS": "BYP", "BYPS": "BYP",
+	"CENTER": "CTR", "CENTRE": "CTR", "CEN": "CTR", "CENT": "CTR",
+	"CENTR": "CTR", "CNTER": "CTR", "CNTR": "CTR", "CTR": "CTR",
+	"CIRCLE": "CIR", "CIR": "CIR", "CIRC": "CIR", "CIRCL": "CIR", "CRCL": "CIR", "CRCLE": "CIR",
+	"COURT": "CT", "CT": "CT",
+	"COVE": "CV", "CV": "CV",
+	"CREEK": "CRK", "CRK": "CRK",
+	"CROSSING": "XING", "CRSSNG": "XING", "XING": "XING",
+	"DRIVE": "DR", "DR": "DR", "DRIV": "DR", "DRV": "DR",
+	"EXPRESSWAY": "EXPY", "EXP": "EXPY", "EXPR": "EXPY", "EXPRESS": "EXPY", "EXPW": "EXPY", "EXPY": "EXPY",
+	"EXTENSION": "EXT", "EXT": "EXT", "EXTN": "EXT", "EXTNSN": "EXT",
+	"FREEWAY": "FWY", "FREEWY": "FWY", "FRWAY": "FWY", "FRWY": "FWY", "FWY": "FWY",
+	"GARDENS": "GDNS", "GDNS": "GDNS", "GRDNS": "GDNS",
+	"GROVE": "GRV", "GROV": "GRV", "GRV": "GRV",
+	"HEIGHTS": "HTS", "HT": "HTS", "HTS": "HTS",
+	"HIGHWAY": "HWY", "HIGHWY": "HWY", "HIWAY": "HWY", "HIWY": "HWY", "HWAY": "HWY", "HWY": "HWY",
+	"HILL": "HL", "HL": "HL",
+	"HOLLOW": "HOLW", "HLLW": "HOLW", "HOLLOWS": "HOLW", "HOLW": "HOLW", "HOLWS": "HOLW",
+	"JUNCTION": "JCT", "JCT": "JCT", "JCTION": "JCT", "JCTN": "JCT", "JUNCTN": "JCT", "JUNCTON": "JCT",
+	"LANE": "LN", "LN": "LN",
+	"LOOP": "LOOP", "LOOPS": "LOOP",
+	"MANOR": "MNR", "MNR": "MNR",
+	"MEADOWS": "MDWS", "MDW": "MDWS", "MDWS": "MDWS", "MEDOWS": "MDWS",
+	"PARK": "PARK", "PRK": "PARK",
+	"PARKWAY": "PKWY", "PARKWY": "PKWY", "PKWAY": "PKWY", "PKWY": "PKWY", "PKY": "PKWY",
+	"PIKE": "PIKE", "PIKES": "PIKE",
+	"PLACE": "PL", "PL": "PL",
+	"PLAZA": "PLZ", "PLZ": "PLZ", "PLZA": "PLZ",
+	"POINT": "PT", "PT": "PT",
+	"RIDGE": "RDG", "RDG": "RDG", "RDGE": "RDG",
+	"ROAD": "RD", "RD": "RD",
+	"ROUTE": "RTE", "RTE": "RTE",
+	"RUN": "RUN", "RUNS": "RUN",
+	"SQUARE": "SQ", "SQ": "SQ", "SQR": "SQ", "SQRE": "SQ", "SQU": "SQ",
+	"STREET": "ST", "ST": "ST", "STR": "ST", "STRT": "ST",
+	"TERRACE": "TER", "TER": "TER", "TERR": "TER",
+	"TRACE": "TRCE", "TRACES": "TRCE", "TRCE": "TRCE",
+	"TRAIL": "TRL", "TRAILS": "TRL", "TRL": "TRL", "TRLS": "TRL",
+	"TURNPIKE": "TPKE", "TPKE": "TPKE", "TRNPK": "TPKE", "TURNPK": "TPKE",
+	"VIEW": "VW", "VW": "VW",
+	"VILLAGE": "VLG", "VILL": "VLG", "VILLAG": "VLG", "VILLG": "VLG", "VILLIAGE": "VLG", "VLG": "VLG",
+	"VISTA": "VIS", "VIS": "VIS", "VIST": "VIS", "VST": "VIS", "VSTA": "VIS",
+	"WALK": "WALK", "WALKS": "WALK",
+	"WAY": "WAY", "WY": "WAY",
+}
+
+// directionals maps directional words to the USPS standard abbreviation
+var directionals = map[string]string{
+	"NORTH": "N", "N": "N",
+	"SOUTH": "S", "S": "S",
+	"EAST": "E", "E": "E",
+	"WEST": "W", "W": "W",
+	"NORTHEAST": "NE", "NE": "NE",
+	"NORTHWEST": "NW", "NW": "NW",
+	"SOUTHEAST": "SE", "SE": "SE",
+	"SOUTHWEST": "SW", "SW": "SW",
+}
+
+// directionalNames maps each directional abbreviation back to its word, for a
+// directional that is the street name itself
+var directionalNames = map[string]string{
+	"N": "NORTH", "S": "SOUTH", "E": "EAST", "W": "WEST",
+	"NE": "NORTHEAST", "NW": "NORTHWEST", "SE": "SOUTHEAST", "SW": "SOUTHWEST",
+}
+
+// unitDesignators maps secondary unit designators (Appendix C2) that are followed
+// by a unit number to their standard abbreviation
+var unitDesignators = map[string]string{
+	"APARTMENT": "APT", "APT": "APT",
+	"BUILDING": "BLDG", "BLDG": "BLDG",
+	"DEPARTMENT": "DEPT", "DEPT": "DEPT",
+	"FLOOR": "FL", "FL": "FL",
+	"HANGAR": "HNGR", "HNGR": "HNGR",
+	"KEY":  "KEY",
+	"LOT":  "LOT",
+	"PIER": "PIER",
+	"ROOM": "RM", "RM": "RM",
+	"SLIP":  "SLIP",
+	"SPACE": "SPC", "SPC": "SPC",
+	"STOP":  "STOP",
+	"SUITE": "STE", "STE": "STE",
+	"TRAILER": "TRLR", "TRLR": "TRLR",
+	"UNIT": "UNIT",
+}
+
+// standaloneUnitDesignators maps secondary unit designators that are used without
+// a unit number to their standard abbreviation
+var standaloneUnitDesignators = map[string]string{
+	"BASEMENT": "BSMT", "BSMT": "BSMT",
+	"FRONT": "FRNT", "FRNT": "FRNT",
+	"LOBBY": "LBBY", "LBBY": "LBBY",
+	"LOWER": "LOWR", "LOWR": "LOWR",
+	"OFFICE": "OFC", "OFC": "OFC",
+	"PENTHOUSE": "PH", "PH": "PH",
+	"REAR":  "REAR",
+	"SIDE":  "SIDE",
+	"UPPER": "UPPR", "UPPR": "UPPR",
+}
+
+// NormalizeStreet standardizes a street line following USPS Publication 28:
+// text is uppercased, punctuation other than '#', '-' and '/' is removed,
+// whitespace is collapsed, and street suffixes, directionals and secondary unit
+// designators are abbreviated. "123 North Main Street, Apt. 4b" becomes
+// "123 N MAIN ST APT 4B".
+func NormalizeStreet(street string) string {
+	tokens := addressTokens(street)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	primary, secondary := splitSecondaryUnit(tokens)
+
+	return strings.Join(append(normalizePrimary(primary), normalizeSecondary(secondary)...), " ")
+}
+
+// NormalizeCity standardizes a city name: uppercased, without punctuation and
+// with whitespace collapsed
+func NormalizeCity(city string) string {
+	return strings.Join(addressTokens(strings.ReplaceAll(city, "#", "")), " ")
+}
+
+// Normalize returns a new Address with every component standardized following
+// USPS Publication 28. The Location is kept as is.
+func (a Address) Normalize() (Address, error) {
+	return NewAddress(
+		NormalizeStreet(a.Street),
+		NormalizeCity(a.City),
+		strings.ToUpper(a.State),
+		a.ZipCode,
+		a.Location,
+	)
+}
+
+// CanonicalKey returns a key identifying the physical address regardless of how
+// it was typed, for duplicate detection: the normalized street, city and state
+// and the 5-digit ZIP code. The Location is not part of the key.
+func (a Address) CanonicalKey() string {
+	zip := a.ZipCode
+	if len(zip) > 5 {
+		zip = zip[:5]
+	}
+
+	return strings.Join([]string{
+		NormalizeStreet(a.Street),
+		NormalizeCity(a.City),
+		normalizeState(a.State),
+		strings.TrimSpace(zip),
+	}, "|")
+}
+
+// EquivalentTo returns true if both addresses normalize to the same physical
+// address, e.g. "123 Main Street" and "123 MAIN ST". Unlike Equals, the Location
+// is ignored.
+func (a Address) EquivalentTo(other Address) bool {
+	return a.CanonicalKey() == other.CanonicalKey()
+}
+
+// addressTokens uppercases text, strips punctuation and splits it into words.
+// A '#' is always split into its own token.
+func addressTokens(text string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		switch {
+		case r == '#':
+			b.WriteString(" # ")
+		case r == '-' || r == '/' || r == '&':
+			b.WriteRune(r)
+		case r == '.' || r == '\'':
+			// dropped without splitting, so "ST." becomes "ST" and "O'HARE" "OHARE"
+		case r == ',' || r == ';' || r == ':' || r == '(' || r == ')' || r == '"':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.Fields(b.String())
+}
+
+// streetNameStart returns the index of the first street name token, skipping a
+// leading house number
+func streetNameStart(tokens []string) int {
+	if len(tokens) > 0 && tokens[0][0] >= '0' && tokens[0][0] <= '9' {
+		return 1
+	}
+	return 0
+}
+
+// splitSecondaryUnit separates the primary street tokens from a trailing
+// secondary unit such as "APT 4B" or "# 12"
+func splitSecondaryUnit(tokens []string) ([]string, []string) {
+	// The house number and first street name word are never a unit
+	for i := streetNameStart(tokens) + 1; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if token == "#" {
+			return tokens[:i], tokens[i:]
+		}
+
+		if _, ok := unitDesignators[token]; ok && i+1 < len(tokens) {
+			return tokens[:i], tokens[i:]
+		}
+
+		if _, ok := standaloneUnitDesignators[token]; ok && followsStreetType(tokens[i-1]) {
+			return tokens[:i], tokens[i:]
+		}
+	}
+
+	return tokens, nil
+}
+
+// followsStreetType returns true if the token ends a street name
+func followsStreetType(token string) bool {
+	_, isSuffix := streetSuffixes[token]
+	_, isDirectional := directionals[token]
+	return isSuffix || isDirectional
+}
+
+// normalizePrimary abbreviates the pre-directional, street suffix and
+// post-directional of the primary address line
+func normalizePrimary(tokens []string) []string {
+	out := make([]string, len(tokens))
+	copy(out, tokens)
+
+	start := streetNameStart(out)
+	last := len(out) - 1
+
+	// Post-directional, e.g. "MAIN ST NORTH"
+	if last >= start+2 {
+		if abbr, ok := directionals[out[last]]; ok {
+			if _, isSuffix := streetSuffixes[out[last-1]]; isSuffix {
+				out[last] = abbr
+				last--
+			}
+		}
+	}
+
+	// Street suffix: only the last word of the street name is abbreviated, so
+	// "AVENUE OF THE AMERICAS" and "PARK AVENUE" are handled correctly. A suffix
+	// word that is the whole street name ("123 PARK") is left alone.
+	hasSuffix := false
+	if last > start {
+		if abbr, ok := streetSuffixes[out[last]]; ok {
+			out[last] = abbr
+			hasSuffix = true
+		}
+	}
+
+	// Pre-directional, e.g. "123 NORTH MAIN ST". A directional that is the street
+	// name itself is spelled out, so "123 N ST" and "123 NORTH ST" agree.
+	if start <= last {
+		if abbr, ok := directionals[out[start]]; ok {
+			if last-start >= 2 || (start < last && !hasSuffix) {
+				out[start] = abbr
+			} else {
+				out[start] = directionalNames[abbr]
+			}
+		}
+	}
+
+	return out
+}
+
+// normalizeSecondary abbreviates the secondary unit designator. A '#' directly
+// after a designator is redundant and dropped.
+func normalizeSecondary(tokens []string) []string {
+	out := []string{}
+	for i, token := range tokens {
+		if i == 0 {
+			if abbr, ok := unitDesignators[token]; ok {
+				token = abbr
+			} else if abbr, ok := standaloneUnitDesignators[token]; ok {
+				token = abbr
+			}
+		} else if token == "#" && i == 1 && tokens[0] != "#" {
+			continue
+		}
+		out = append(out, token)
+	}
+
+	return out
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestNormalizeStreet(t *testing.T) {
+	tests := []struct {
+		name     string
+		street   string
+		expected string
+	}{
+		{name: "suffix and case", street: "123 Main Street", expected: "123 MAIN ST"},
+		{name: "already normalized", street: "123 MAIN ST", expected: "123 MAIN ST"},
+		{name: "whitespace and periods", street: "  123   Main   St. ", expected: "123 MAIN ST"},
+		{name: "suffix variant", street: "500 Lakeshore Boul", expected: "500 LAKESHORE BLVD"},
+		{name: "pre-directional", street: "742 North Evergreen Terrace", expected: "742 N EVERGREEN TER"},
+		{name: "post-directional", street: "1600 Pennsylvania Avenue Northwest", expected: "1600 PENNSYLVANIA AVE NW"},
+		{name: "directional as street name", street: "10 North Street", expected: "10 NORTH ST"},
+		{name: "abbreviated directional as street name", street: "12 N St", expected: "12 NORTH ST"},
+		{name: "directional street name with post-directional", street: "12 N St NE", expected: "12 NORTH ST NE"},
+		{name: "directional as whole name", street: "40 W", expected: "40 WEST"},
+		{name: "pre-directional without suffix", street: "10 West Broadway", expected: "10 W BROADWAY"},
+		{name: "suffix word inside name", street: "350 Park Avenue", expected: "350 PARK AVE"},
+		{name: "suffix word leading name", street: "1211 Avenue of the Americas", expected: "1211 AVENUE OF THE AMERICAS"},
+		{name: "suffix word as whole name", street: "15 Park", expected: "15 PARK"},
+		{name: "apartment", street: "123 Main Street, Apartment 4b", expected: "123 MAIN ST APT 4B"},
+		{name: "suite abbreviation", street: "1 Infinite Loop Suite 200", expected: "1 INFINITE LOOP STE 200"},
+		{name: "hash unit", street: "123 Main St #5", expected: "123 MAIN ST # 5"},
+		{name: "designator with hash", street: "123 Main St Apt #5", expected: "123 MAIN ST APT 5"},
+		{name: "standalone designator", street: "77 Elm Rd Rear", expected: "77 ELM RD REAR"},
+		{name: "standalone designator word in name", street: "77 Front Street", expected: "77 FRONT ST"},
+		{name: "unit with hyphen", street: "12-14 Oak Lane, Bldg. A-2", expected: "12-14 OAK LN BLDG A-2"},
+		{name: "no house number", street: "Main Street", expected: "MAIN ST"},
+		{name: "house number only", street: "123", expected: "123"},
+		{name: "empty", street: "  ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NormalizeStreet(tt.street); result != tt.expected {
+				t.Errorf("NormalizeStreet(%q) = %q, want %q", tt.street, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeCity(t *testing.T) {
+	if result := NormalizeCity("  St.  Louis "); result != "ST LOUIS" {
+		t.Errorf("NormalizeCity() = %q, want %q", result, "ST LOUIS")
+	}
+}
+
+func TestAddress_Normalize(t *testing.T) {
+	location, _ := NewLocation(39.7817, -89.6501, "Sangamon", "IL")
+	addr, _ := NewAddress("123 Main Street, Apt. 2", "springfield", "il", "62701", location)
+
+	normalized, err := addr.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize() unexpected error = %v", err)
+	}
+
+	expected, _ := NewAddress("123 MAIN ST APT 2", "SPRINGFIELD", "IL", "62701", location)
+	if !normalized.Equals(expected) {
+		t.Errorf("Normalize() = %v, want %v", normalized, expected)
+	}
+	if addr.Street != "123 Main Street, Apt. 2" {
+		t.Errorf("Normalize() modified original address")
+	}
+}
+
+func TestAddress_CanonicalKey(t *testing.T) {
+	location1, _ := NewLocation(39.7817, -89.6501, "Sangamon", "IL")
+	location2, _ := NewLocation(39.7800, -89.6500, "Sangamon", "IL")
+
+	addr1, _ := NewAddress("123 Main Street", "Springfield", "IL", "62701", location1)
+	addr2, _ := NewAddress("123 MAIN ST", "SPRINGFIELD", "il", "62701-1234", location2)
+	// Addresses stored before state names were converted keep the name
+	legacy := Address{Street: "123 Main St.", City: "Springfield", State: "Illinois", ZipCode: "62701"}
+	addr3, _ := NewAddress("125 Main Street", "Springfield", "IL", "62701", location1)
+
+	if addr1.CanonicalKey() != "123 MAIN ST|SPRINGFIELD|IL|62701" {
+		t.Errorf("CanonicalKey() = %q, want %q", addr1.CanonicalKey(), "123 MAIN ST|SPRINGFIELD|IL|62701")
+	}
+
+	if !addr1.EquivalentTo(addr2) {
+		t.Errorf("EquivalentTo() = false for differently typed copies of the same address")
+	}
+	if !addr1.EquivalentTo(legacy) {
+		t.Errorf("EquivalentTo() = false for an address with the state spelled out")
+	}
+	if addr1.Equals(addr2) {
+		t.Errorf("Equals() = true, want strict comparison to be unchanged")
+	}
+	if addr1.EquivalentTo(addr3) {
+		t.Errorf("EquivalentTo() = true for different house numbers")
+	}
+
+	short, _ := NewAddress("12 N St", "Springfield", "IL", "62701", location1)
+	long, _ := NewAddress("12 North Street", "Springfield", "IL", "62701", location1)
+	if short.CanonicalKey() != long.CanonicalKey() {
+		t.Errorf("CanonicalKey() = %q and %q for the same street named North", short.CanonicalKey(), long.CanonicalKey())
+	}
+}
@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"errors"
 	"testing"
+
+	"hrh-backend/internal/shared"
 )
 
 func TestNewAddress(t *testing.T) {
@@ -12,8 +15,10 @@ func TestNewAddress(t *testing.T) {
 		state    string
 		zipCode  string
 		location Location
-		wantErr  bool
-		errMsg   string
+		// wantState is the stored state when it differs from the given one
+		wantState string
+		wantErr   bool
+		errMsg    string
 	}{
 		{
 			name:     "valid address",
@@ -53,16 +58,74 @@ func TestNewAddress(t *testing.T) {
 			wantErr:  true,
 			errMsg:   "state is required",
 		},
+		{
+			name:      "full state name",
+			street:    "1100 Congress Ave",
+			city:      "Austin",
+			state:     " texas ",
+			zipCode:   "78701",
+			location:  Location{},
+			wantState: "TX",
+			wantErr:   false,
+		},
 		{
 			name:     "invalid state format",
 			street:   "123 Main St",
 			city:     "Springfield",
-			state:    "Illinois",
+			state:    "Illinoise",
 			zipCode:  "62701",
 			location: Location{},
 			wantErr:  true,
 			errMsg:   "state must be a 2-letter code",
 		},
+		{
+			name:     "unknown state code",
+			street:   "123 Main St",
+			city:     "Springfield",
+			state:    "ZZ",
+			zipCode:  "62701",
+			location: Location{},
+			wantErr:  true,
+			errMsg:   "state must be a valid US state or territory code",
+		},
+		{
+			name:     "numeric state code",
+			street:   "123 Main St",
+			city:     "Springfield",
+			state:    "12",
+			zipCode:  "",
+			location: Location{},
+			wantErr:  true,
+			errMsg:   "state must be a valid US state or territory code",
+		},
+		{
+			name:     "zip code outside state",
+			street:   "123 Main St",
+			city:     "Austin",
+			state:    "TX",
+			zipCode:  "62701",
+			location: Location{},
+			wantErr:  true,
+			errMsg:   "zip code 62701 is not in TX",
+		},
+		{
+			name:     "territory",
+			street:   "1 Calle Fortaleza",
+			city:     "San Juan",
+			state:    "PR",
+			zipCode:  "00901",
+			location: Location{},
+			wantErr:  false,
+		},
+		{
+			name:     "military",
+			street:   "Unit 2050 Box 4190",
+			city:     "APO",
+			state:    "AP",
+			zipCode:  "96278-2050",
+			location: Location{},
+			wantErr:  false,
+		},
 		{
 			name:     "invalid zip code format",
 			street:   "123 Main St",
@@ -101,8 +164,12 @@ func TestNewAddress(t *testing.T) {
 			if addr.City != tt.city {
 				t.Errorf("NewAddress() city = %v, want %v", addr.City, tt.city)
 			}
-			if addr.State != tt.state {
-				t.Errorf("NewAddress() state = %v, want %v", addr.State, tt.state)
+			wantState := tt.state
+			if tt.wantState != "" {
+				wantState = tt.wantState
+			}
+			if addr.State != wantState {
+				t.Errorf("NewAddress() state = %v, want %v", addr.State, wantState)
 			}
 			if addr.ZipCode != tt.zipCode {
 				t.Errorf("NewAddress() zipCode = %v, want %v", addr.ZipCode, tt.zipCode)
@@ -140,7 +207,8 @@ func TestAddress_ImmutabilityMethods(t *testing.T) {
 	}
 
 	// Test WithState
-	newAddr, err = original.WithState("NY")
+	withoutZip, _ := original.WithZipCode("")
+	newAddr, err = withoutZip.WithState("NY")
 	if err != nil {
 		t.Errorf("WithState() unexpected error = %v", err)
 	}
@@ -151,6 +219,11 @@ func TestAddress_ImmutabilityMethods(t *testing.T) {
 		t.Errorf("WithState() modified original address")
 	}
 
+	// The zip code must stay consistent with the new state
+	if _, err := original.WithState("NY"); err == nil {
+		t.Errorf("WithState() expected error for zip code outside the new state")
+	}
+
 	// Test WithZipCode
 	newAddr, err = original.WithZipCode("60601")
 	if err != nil {
@@ -288,3 +361,51 @@ func TestAddress_Equals(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAddress_ValidationErrors(t *testing.T) {
+	badLocation := Location{Latitude: 95, Longitude: -200}
+
+	_, err := NewAddress("123 Main St", "", "ZZ", "6270", badLocation)
+	if err == nil {
+		t.Fatal("NewAddress() expected error")
+	}
+
+	if !errors.Is(err, shared.ErrValidation) {
+		t.Errorf("errors.Is(err, ErrValidation) = false for %v", err)
+	}
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("NewAddress() error %T is not a *shared.ValidationError", err)
+	}
+
+	want := []shared.FieldError{
+		{Field: "city", Code: shared.CodeAddressCityRequired, Message: "city is required"},
+		{Field: "state", Code: shared.CodeAddressStateUnknown, Message: "state must be a valid US state or territory code"},
+		{Field: "zip_code", Code: shared.CodeAddressZipCodeFormat, Message: "zip code must be in format 12345 or 12345-6789"},
+		{Field: "location.latitude", Code: shared.CodeLocationLatitudeRange, Message: "latitude must be between -90 and 90 degrees"},
+		{Field: "location.longitude", Code: shared.CodeLocationLongitudeRange, Message: "longitude must be between -180 and 180 degrees"},
+	}
+
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("got %d field errors %v, want %d", len(verr.Fields), verr.Fields, len(want))
+	}
+	for i := range want {
+		if verr.Fields[i] != want[i] {
+			t.Errorf("Fields[%d] = %+v, want %+v", i, verr.Fields[i], want[i])
+		}
+	}
+}
+
+func TestNewAddress_ZipStateMismatchCode(t *testing.T) {
+	_, err := NewAddress("123 Main St", "Springfield", "TX", "62701", Location{})
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("NewAddress() error = %v, want *shared.ValidationError", err)
+	}
+
+	if !verr.HasCode(shared.CodeAddressZipCodeStateMatch) {
+		t.Errorf("expected code %s in %+v", shared.CodeAddressZipCodeStateMatch, verr.Fields)
+	}
+}
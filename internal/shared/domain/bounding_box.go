@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Area is a geographic region that can answer containment queries
+type Area interface {
+	Contains(location Location) bool
+	Bounds() BoundingBox
+}
+
+// BoundingBox represents a latitude/longitude rectangle
+// This is a Value Object - immutable and defined by its attributes
+//
+// A box whose West edge is greater than its East edge crosses the antimeridian,
+// e.g. West 170, East -170 covers the 20 degrees around longitude 180.
+type BoundingBox struct {
+	South float64 `json:"south"`
+	West  float64 `json:"west"`
+	North float64 `json:"north"`
+	East  float64 `json:"east"`
+}
+
+// NewBoundingBox creates a new BoundingBox with validation
+func NewBoundingBox(south, west, north, east float64) (BoundingBox, error) {
+	box := BoundingBox{
+		South: south,
+		West:  west,
+		North: north,
+		East:  east,
+	}
+
+	if err := box.validate(); err != nil {
+		return BoundingBox{}, err
+	}
+
+	return box, nil
+}
+
+// BoundingBoxAround creates the smallest BoundingBox containing every point within
+// radius of center
+func BoundingBoxAround(center Location, radius float64, unit DistanceUnit) (BoundingBox, error) {
+	if err := center.validate(); err != nil {
+		return BoundingBox{}, err
+	}
+
+	point := BoundingBox{
+		South: center.Latitude,
+		West:  center.Longitude,
+		North: center.Latitude,
+		East:  center.Longitude,
+	}
+
+	return point.ExpandBy(radius, unit)
+}
+
+// validate performs validation on bounding box components
+func (b BoundingBox) validate() error {
+	if b.South < -90 || b.South > 90 || b.North < -90 || b.North > 90 {
+		return errors.New("latitude must be between -90 and 90 degrees")
+	}
+
+	if b.West < -180 || b.West > 180 || b.East < -180 || b.East > 180 {
+		return errors.New("longitude must be between -180 and 180 degrees")
+	}
+
+	if b.South > b.North {
+		return errors.New("south edge must not be north of the north edge")
+	}
+
+	return nil
+}
+
+// CrossesAntimeridian returns true if the box spans longitude 180
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+// Bounds returns the box itself, so a BoundingBox can be used as an Area
+func (b BoundingBox) Bounds() BoundingBox {
+	return b
+}
+
+// LongitudeSpan returns the width of the box in degrees of longitude
+func (b BoundingBox) LongitudeSpan() float64 {
+	return b.lngInterval().width
+}
+
+// Center returns the Location at the center of the box
+func (b BoundingBox) Center() Location {
+	return Location{
+		Latitude:  (b.South + b.North) / 2,
+		Longitude: normalizeLongitude(b.West + b.LongitudeSpan()/2),
+	}
+}
+
+// Contains returns true if the location lies inside the box (edges included)
+func (b BoundingBox) Contains(location Location) bool {
+	if location.Latitude < b.South || location.Latitude > b.North {
+		return false
+	}
+
+	return b.lngInterval().contains(location.Longitude)
+}
+
+// ContainsBox returns true if other lies entirely inside the box
+func (b BoundingBox) ContainsBox(other BoundingBox) bool {
+	if other.South < b.South || other.North > b.North {
+		return false
+	}
+
+	outer, inner := b.lngInterval(), other.lngInterval()
+	if outer.isFull() {
+		return true
+	}
+	if inner.width > outer.width {
+		return false
+	}
+
+	offset := math.Mod(inner.west-outer.west+720, 360)
+	return offset+inner.width <= outer.width
+}
+
+// Intersects returns true if the two boxes share at least one point
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	return len(b.Intersection(other)) > 0
+}
+
+// Intersection returns the area shared by both boxes. Two boxes that together wrap
+// around the whole globe can overlap in two disjoint pieces, so a slice is returned;
+// it is empty when the boxes do not intersect.
+func (b BoundingBox) Intersection(other BoundingBox) []BoundingBox {
+	south := math.Max(b.South, other.South)
+	north := math.Min(b.North, other.North)
+	if south > north {
+		return []BoundingBox{}
+	}
+
+	pieces := b.lngInterval().intersect(other.lngInterval())
+
+	boxes := make([]BoundingBox, 0, len(pieces))
+	for _, piece := range pieces {
+		boxes = append(boxes, piece.toBox(south, north))
+	}
+
+	return boxes
+}
+
+// ExpandBy returns a new BoundingBox grown by distance on every side. Boxes that
+// reach a pole span every meridian, and longitudes wrap across the antimeridian.
+func (b BoundingBox) ExpandBy(distance float64, unit DistanceUnit) (BoundingBox, error) {
+	if !unit.IsValid() {
+		return BoundingBox{}, errors.New("distance unit must be km or mi")
+	}
+
+	if distance < 0 || math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return BoundingBox{}, errors.New("distance must be a non-negative number")
+	}
+
+	latDelta := unit.ToKilometers(distance) / kmPerDegreeLatitude
+	south := math.Max(b.South-latDelta, -90)
+	north := math.Min(b.North+latDelta, 90)
+
+	lng := b.lngInterval()
+	if south <= -90 || north >= 90 {
+		return fullLngInterval.toBox(south, north), nil
+	}
+
+	// Degrees of longitude shrink towards the poles, so widen using the most
+	// poleward latitude the expanded box reaches
+	maxAbsLat := math.Max(math.Abs(south), math.Abs(north))
+	lngDelta := latDelta / math.Cos(maxAbsLat*math.Pi/180)
+
+	if lng.width+2*lngDelta >= 360 {
+		return fullLngInterval.toBox(south, north), nil
+	}
+
+	expanded := lngInterval{west: lng.west - lngDelta, width: lng.width + 2*lngDelta}
+	return expanded.toBox(south, north), nil
+}
+
+// String returns a formatted string representation of the box
+func (b BoundingBox) String() string {
+	return fmt.Sprintf("[%.6f, %.6f] - [%.6f, %.6f]", b.South, b.West, b.North, b.East)
+}
+
+// Equals compares two boxes for equality (with small tolerance for floating point comparison)
+func (b BoundingBox) Equals(other BoundingBox) bool {
+	const tolerance = 1e-6
+
+	return math.Abs(b.South-other.South) < tolerance &&
+		math.Abs(b.West-other.West) < tolerance &&
+		math.Abs(b.North-other.North) < tolerance &&
+		math.Abs(b.East-other.East) < tolerance
+}
+
+// lngInterval returns the longitude range of the box as a circular interval
+func (b BoundingBox) lngInterval() lngInterval {
+	width := b.East - b.West
+	if width < 0 {
+		width += 360
+	}
+
+	return lngInterval{west: b.West, width: width}
+}
+
+// lngInterval is a range of longitudes starting at west and extending width
+// degrees eastwards, possibly past the antimeridian
+type lngInterval struct {
+	west  float64
+	width float64
+}
+
+// fullLngInterval covers every meridian
+var fullLngInterval = lngInterval{west: -180, width: 360}
+
+// isFull returns true if the interval covers every meridian
+func (i lngInterval) isFull() bool {
+	return i.width >= 360
+}
+
+// contains returns true if the longitude lies inside the interval
+func (i lngInterval) contains(longitude float64) bool {
+	if i.isFull() {
+		return true
+	}
+
+	offset := math.Mod(longitude-i.west+720, 360)
+	return offset <= i.width
+}
+
+// intersect returns the pieces of longitude shared by both intervals
+func (i lngInterval) intersect(other lngInterval) []lngInterval {
+	if i.isFull() {
+		return []lngInterval{other}
+	}
+	if other.isFull() {
+		return []lngInterval{i}
+	}
+
+	// Place other's west edge within [i.west, i.west+360) and compare it against
+	// both itself and its copy one revolution to the west
+	start := i.west + math.Mod(other.west-i.west+720, 360)
+	end := i.west + i.width
+
+	pieces := []lngInterval{}
+	for _, west := range []float64{start - 360, start} {
+		lo := math.Max(i.west, west)
+		hi := math.Min(end, west+other.width)
+		if lo <= hi {
+			pieces = append(pieces, lngInterval{west: lo, width: hi - lo})
+		}
+	}
+
+	return pieces
+}
+
+// toBox builds a BoundingBox from the interval and a latitude range
+func (i lngInterval) toBox(south, north float64) BoundingBox {
+	if i.isFull() {
+		return BoundingBox{South: south, West: -180, North: north, East: 180}
+	}
+
+	return BoundingBox{
+		South: south,
+		West:  normalizeLongitude(i.west),
+		North: north,
+		East:  normalizeLongitude(i.west + i.width),
+	}
+}
+
+// normalizeLongitude maps any longitude into [-180, 180]
+func normalizeLongitude(longitude float64) float64 {
+	if longitude >= -180 && longitude <= 180 {
+		return longitude
+	}
+
+	longitude = math.Mod(longitude+180, 360)
+	if longitude < 0 {
+		longitude += 360
+	}
+	return longitude - 180
+}
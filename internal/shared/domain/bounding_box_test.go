@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewBoundingBox(t *testing.T) {
+	tests := []struct {
+		name    string
+		south   float64
+		west    float64
+		north   float64
+		east    float64
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:  "valid box",
+			south: 41.4, west: -88.3, north: 42.2, east: -87.5,
+		},
+		{
+			name:  "crossing antimeridian",
+			south: -20, west: 170, north: -10, east: -170,
+		},
+		{
+			name:  "south above north",
+			south: 42, west: -88, north: 41, east: -87,
+			wantErr: true,
+			errMsg:  "south edge must not be north of the north edge",
+		},
+		{
+			name:  "latitude out of range",
+			south: -91, west: -88, north: 41, east: -87,
+			wantErr: true,
+			errMsg:  "latitude must be between -90 and 90 degrees",
+		},
+		{
+			name:  "longitude out of range",
+			south: 41, west: -181, north: 42, east: -87,
+			wantErr: true,
+			errMsg:  "longitude must be between -180 and 180 degrees",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBoundingBox(tt.south, tt.west, tt.north, tt.east)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewBoundingBox() expected error but got none")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("NewBoundingBox() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("NewBoundingBox() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBoundingBox_Contains(t *testing.T) {
+	cook, _ := NewBoundingBox(41.4, -88.3, 42.2, -87.5)
+	fiji, _ := NewBoundingBox(-20, 170, -10, -170)
+
+	tests := []struct {
+		name     string
+		box      BoundingBox
+		location Location
+		expected bool
+	}{
+		{name: "inside", box: cook, location: Location{Latitude: 41.8781, Longitude: -87.6298}, expected: true},
+		{name: "on edge", box: cook, location: Location{Latitude: 41.4, Longitude: -88.3}, expected: true},
+		{name: "outside", box: cook, location: Location{Latitude: 40.7128, Longitude: -74.0060}, expected: false},
+		{name: "east of antimeridian", box: fiji, location: Location{Latitude: -16.5, Longitude: 178.4}, expected: true},
+		{name: "west of antimeridian", box: fiji, location: Location{Latitude: -16.5, Longitude: -179.9}, expected: true},
+		{name: "on antimeridian", box: fiji, location: Location{Latitude: -16.5, Longitude: 180}, expected: true},
+		{name: "outside wrapped box", box: fiji, location: Location{Latitude: -16.5, Longitude: 0}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.box.Contains(tt.location); result != tt.expected {
+				t.Errorf("Contains() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBoundingBox_Intersection(t *testing.T) {
+	a, _ := NewBoundingBox(0, 0, 10, 10)
+	b, _ := NewBoundingBox(5, 5, 15, 15)
+	c, _ := NewBoundingBox(20, 20, 30, 30)
+	wrapped, _ := NewBoundingBox(-10, 170, 10, -170)
+	eastOfLine, _ := NewBoundingBox(-5, -175, 5, -160)
+	// Together these two wrap the whole globe and overlap on both sides
+	wide, _ := NewBoundingBox(0, -100, 10, 100)
+	wideWrapped, _ := NewBoundingBox(0, 90, 10, -90)
+
+	tests := []struct {
+		name     string
+		box1     BoundingBox
+		box2     BoundingBox
+		expected []BoundingBox
+	}{
+		{name: "overlapping", box1: a, box2: b, expected: []BoundingBox{{South: 5, West: 5, North: 10, East: 10}}},
+		{name: "disjoint", box1: a, box2: c, expected: []BoundingBox{}},
+		{
+			name:     "across antimeridian",
+			box1:     wrapped,
+			box2:     eastOfLine,
+			expected: []BoundingBox{{South: -5, West: -175, North: 5, East: -170}},
+		},
+		{
+			name: "two pieces",
+			box1: wide,
+			box2: wideWrapped,
+			expected: []BoundingBox{
+				{South: 0, West: -100, North: 10, East: -90},
+				{South: 0, West: 90, North: 10, East: 100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.box1.Intersection(tt.box2)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Intersection() = %v, want %v", result, tt.expected)
+			}
+			for i := range result {
+				if !result[i].Equals(tt.expected[i]) {
+					t.Errorf("Intersection()[%d] = %v, want %v", i, result[i], tt.expected[i])
+				}
+			}
+
+			if tt.box1.Intersects(tt.box2) != (len(tt.expected) > 0) {
+				t.Errorf("Intersects() = %v, want %v", !(len(tt.expected) > 0), len(tt.expected) > 0)
+			}
+			if tt.box2.Intersects(tt.box1) != (len(tt.expected) > 0) {
+				t.Errorf("Intersects() is not symmetric")
+			}
+		})
+	}
+}
+
+func TestBoundingBox_ContainsBox(t *testing.T) {
+	outer, _ := NewBoundingBox(-20, 170, 20, -170)
+	inner, _ := NewBoundingBox(-5, 175, 5, -175)
+	straddling, _ := NewBoundingBox(-5, 160, 5, 175)
+
+	if !outer.ContainsBox(inner) {
+		t.Errorf("ContainsBox() = false, want true")
+	}
+	if outer.ContainsBox(straddling) {
+		t.Errorf("ContainsBox() = true for box extending past the west edge")
+	}
+	if inner.ContainsBox(outer) {
+		t.Errorf("ContainsBox() = true for larger box")
+	}
+}
+
+func TestBoundingBox_ExpandBy(t *testing.T) {
+	box, _ := NewBoundingBox(0, 0, 0, 0)
+
+	expanded, err := box.ExpandBy(kmPerDegreeLatitude, Kilometers)
+	if err != nil {
+		t.Fatalf("ExpandBy() unexpected error = %v", err)
+	}
+
+	// One degree of latitude at the equator, and slightly more than one degree of
+	// longitude because the widening uses the most poleward latitude reached
+	if math.Abs(expanded.South+1) > 1e-9 || math.Abs(expanded.North-1) > 1e-9 {
+		t.Errorf("ExpandBy() latitude = [%v, %v], want [-1, 1]", expanded.South, expanded.North)
+	}
+	wantLng := 1 / math.Cos(math.Pi/180)
+	if math.Abs(expanded.West+wantLng) > 1e-9 || math.Abs(expanded.East-wantLng) > 1e-9 {
+		t.Errorf("ExpandBy() longitude = [%v, %v], want [%v, %v]", expanded.West, expanded.East, -wantLng, wantLng)
+	}
+
+	// Expanding past the antimeridian wraps the east edge
+	nearLine, _ := NewBoundingBox(-1, 179, 1, 179.5)
+	wrapped, _ := nearLine.ExpandBy(200, Kilometers)
+	if !wrapped.CrossesAntimeridian() {
+		t.Errorf("ExpandBy() = %v, want box crossing the antimeridian", wrapped)
+	}
+	if !wrapped.Contains(Location{Latitude: 0, Longitude: -179.5}) {
+		t.Errorf("ExpandBy() = %v, want box containing -179.5", wrapped)
+	}
+
+	// Reaching a pole covers every meridian
+	polar, _ := NewBoundingBox(89, 10, 89.5, 20)
+	full, _ := polar.ExpandBy(100, Miles)
+	if full.West != -180 || full.East != 180 || full.North != 90 {
+		t.Errorf("ExpandBy() near pole = %v, want full longitude range up to 90", full)
+	}
+
+	if _, err := box.ExpandBy(-1, Kilometers); err == nil {
+		t.Errorf("ExpandBy() expected error for negative distance")
+	}
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	chicago, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	box, err := BoundingBoxAround(chicago, 25, Miles)
+	if err != nil {
+		t.Fatalf("BoundingBoxAround() unexpected error = %v", err)
+	}
+
+	// Every point on the circle must be inside the box
+	for _, bearing := range []float64{0, 45, 90, 135, 180, 225, 270, 315} {
+		rad := bearing * math.Pi / 180
+		d := Miles.ToKilometers(25) / kmPerDegreeLatitude
+		edge := Location{
+			Latitude:  chicago.Latitude + d*math.Cos(rad)*0.999,
+			Longitude: chicago.Longitude + d*math.Sin(rad)/math.Cos(chicago.Latitude*math.Pi/180)*0.999,
+		}
+		if !box.Contains(edge) {
+			t.Errorf("BoundingBoxAround() = %v does not contain %v", box, edge)
+		}
+	}
+
+	if center := box.Center(); center.DistanceTo(chicago) > 1 {
+		t.Errorf("Center() = %v, want approximately %v", center, chicago)
+	}
+}
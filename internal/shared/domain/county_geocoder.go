@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+)
+
+// ErrCountyNotFound is returned when no known county boundary contains a location
+var ErrCountyNotFound = errors.New("no county contains the location")
+
+// ReverseGeocoder fills in the County and Region of a Location from its coordinates
+type ReverseGeocoder interface {
+	ReverseGeocode(location Location) (Location, error)
+}
+
+// countyPropertyAliases lists the Feature property names read for each attribute,
+// in order of preference. The defaults cover TIGER/Line county files converted to
+// GeoJSON as well as hand-made files.
+var countyPropertyAliases = map[string][]string{
+	"county": {"name", "county", "county_name", "namelsad"},
+	"region": {"stusps", "state_abbr", "state", "region"},
+	"fips":   {"statefp", "state_fips"},
+}
+
+// countyBoundary is one imported county outline
+type countyBoundary struct {
+	county   string
+	region   string
+	polygons []Polygon
+}
+
+// contains returns true if any polygon of the county contains the location
+func (c countyBoundary) contains(location Location) bool {
+	for _, polygon := range c.polygons {
+		if polygon.Contains(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// countyCell is a one-degree latitude/longitude cell used to narrow boundary lookups
+type countyCell struct {
+	lat int
+	lng int
+}
+
+// CountyBoundaries is an offline ReverseGeocoder backed by county outlines
+// imported from GeoJSON. It is safe for concurrent use.
+type CountyBoundaries struct {
+	mu       sync.RWMutex
+	counties []countyBoundary
+	cells    map[countyCell][]int
+}
+
+// NewCountyBoundaries creates an empty CountyBoundaries
+func NewCountyBoundaries() *CountyBoundaries {
+	return &CountyBoundaries{cells: make(map[countyCell][]int)}
+}
+
+// LoadCountyBoundaries creates a CountyBoundaries from a GeoJSON FeatureCollection
+func LoadCountyBoundaries(r io.Reader) (*CountyBoundaries, error) {
+	b := NewCountyBoundaries()
+	if _, err := b.Import(r); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Import adds the counties of a GeoJSON FeatureCollection whose features have
+// Polygon or MultiPolygon geometries, such as a Census TIGER/Line county file
+// converted with ogr2ogr. The county name is read from the "NAME" (or "county")
+// property and the region from "STUSPS" (or "state"/"region"), falling back to
+// the "STATEFP" FIPS code. The import is all-or-nothing and returns the number of
+// counties added.
+func (b *CountyBoundaries) Import(r io.Reader) (int, error) {
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry   *GeoJSONGeometry `json:"geometry"`
+			Properties map[string]any   `json:"properties"`
+		} `json:"features"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return 0, fmt.Errorf("invalid county boundary GeoJSON: %w", err)
+	}
+
+	if collection.Type != GeoJSONTypeFeatureCollection {
+		return 0, fmt.Errorf("county boundaries must be a FeatureCollection, got %q", collection.Type)
+	}
+
+	imported := make([]countyBoundary, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		if feature.Geometry == nil {
+			return 0, fmt.Errorf("county feature %d has no geometry", i)
+		}
+
+		polygons, err := PolygonsFromGeoJSON(*feature.Geometry)
+		if err != nil {
+			return 0, fmt.Errorf("county feature %d: %w", i, err)
+		}
+
+		county := countyProperty(feature.Properties, "county")
+		if county == "" {
+			return 0, fmt.Errorf("county feature %d has no name", i)
+		}
+
+		region := countyProperty(feature.Properties, "region")
+		if fips := countyProperty(feature.Properties, "fips"); region == "" && fips != "" {
+			if region, err = StateCodeFromFIPS(fips); err != nil {
+				return 0, fmt.Errorf("county feature %d: %w", i, err)
+			}
+		}
+
+		imported = append(imported, countyBoundary{
+			county:   county,
+			region:   region,
+			polygons: polygons,
+		})
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, county := range imported {
+		b.counties = append(b.counties, county)
+		index := len(b.counties) - 1
+		for _, polygon := range county.polygons {
+			for _, cell := range countyCellsInBox(polygon.Bounds()) {
+				b.cells[cell] = appendUnique(b.cells[cell], index)
+			}
+		}
+	}
+
+	return len(imported), nil
+}
+
+// Len returns the number of known counties
+func (b *CountyBoundaries) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.counties)
+}
+
+// ReverseGeocode returns the location with County and Region set from the county
+// boundary containing its coordinates. When boundaries overlap (e.g. along shared
+// edges) the county imported first wins.
+func (b *CountyBoundaries) ReverseGeocode(location Location) (Location, error) {
+	if err := location.validate(); err != nil {
+		return Location{}, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, index := range b.cells[countyCellOf(location.Latitude, location.Longitude)] {
+		county := b.counties[index]
+		if county.contains(location) {
+			return NewLocation(location.Latitude, location.Longitude, county.county, county.region)
+		}
+	}
+
+	return Location{}, fmt.Errorf("%w: %s", ErrCountyNotFound, location)
+}
+
+// countyProperty reads the first non-empty string property matching one of the
+// attribute's aliases, ignoring case
+func countyProperty(properties map[string]any, attribute string) string {
+	lowered := make(map[string]any, len(properties))
+	for key, value := range properties {
+		lowered[strings.ToLower(key)] = value
+	}
+
+	for _, alias := range countyPropertyAliases[attribute] {
+		if value, ok := lowered[alias].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
+
+// countyCellOf returns the one-degree cell containing the coordinates
+func countyCellOf(latitude, longitude float64) countyCell {
+	return countyCell{
+		lat: int(math.Floor(latitude)),
+		lng: wrapDegree(int(math.Floor(longitude))),
+	}
+}
+
+// countyCellsInBox returns every one-degree cell overlapping the box
+func countyCellsInBox(box BoundingBox) []countyCell {
+	lng := box.lngInterval()
+	firstLng := int(math.Floor(lng.west))
+	lastLng := int(math.Floor(lng.west + lng.width))
+	if lastLng-firstLng >= 360 {
+		firstLng, lastLng = -180, 179
+	}
+
+	cells := []countyCell{}
+	for lat := int(math.Floor(box.South)); lat <= int(math.Floor(box.North)); lat++ {
+		for l := firstLng; l <= lastLng; l++ {
+			cells = append(cells, countyCell{lat: lat, lng: wrapDegree(l)})
+		}
+	}
+
+	return cells
+}
+
+// wrapDegree maps a whole longitude degree into [-180, 180)
+func wrapDegree(degree int) int {
+	degree = (degree + 180) % 360
+	if degree < 0 {
+		degree += 360
+	}
+	return degree - 180
+}
+
+// appendUnique appends value unless the slice already ends with it
+func appendUnique(values []int, value int) []int {
+	if len(values) > 0 && values[len(values)-1] == value {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Simplified outlines: Sangamon as a rectangle, Cook with a hole standing in for
+// an enclave, and a county split across the antimeridian
+const countyBoundariesGeoJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"NAME": "Sangamon", "STUSPS": "IL", "STATEFP": "17"},
+      "geometry": {"type": "Polygon", "coordinates": [[[-89.99,39.52],[-89.22,39.52],[-89.22,40.00],[-89.99,40.00],[-89.99,39.52]]]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "Cook", "STATEFP": "17"},
+      "geometry": {"type": "Polygon", "coordinates": [
+        [[-88.26,41.47],[-87.52,41.47],[-87.52,42.15],[-88.26,42.15],[-88.26,41.47]],
+        [[-88.00,41.90],[-87.90,41.90],[-87.90,42.00],[-88.00,42.00],[-88.00,41.90]]
+      ]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"NAME": "Aleutians West", "STUSPS": "AK"},
+      "geometry": {"type": "MultiPolygon", "coordinates": [
+        [[[172.0,52.0],[180.0,52.0],[180.0,53.5],[172.0,53.5],[172.0,52.0]]],
+        [[[-180.0,51.0],[-176.0,51.0],[-176.0,52.5],[-180.0,52.5],[-180.0,51.0]]]
+      ]}
+    }
+  ]
+}`
+
+func TestCountyBoundaries_ReverseGeocode(t *testing.T) {
+	b, err := LoadCountyBoundaries(strings.NewReader(countyBoundariesGeoJSON))
+	if err != nil {
+		t.Fatalf("LoadCountyBoundaries() unexpected error = %v", err)
+	}
+
+	if b.Len() != 3 {
+		t.Errorf("Len() = %v, want 3", b.Len())
+	}
+
+	tests := []struct {
+		name       string
+		latitude   float64
+		longitude  float64
+		wantCounty string
+		wantRegion string
+		wantErr    error
+	}{
+		{name: "springfield", latitude: 39.7817, longitude: -89.6501, wantCounty: "Sangamon", wantRegion: "IL"},
+		{name: "chicago", latitude: 41.8781, longitude: -87.6298, wantCounty: "Cook", wantRegion: "IL"},
+		{name: "enclave", latitude: 41.95, longitude: -87.95, wantErr: ErrCountyNotFound},
+		{name: "east of antimeridian", latitude: 52.8, longitude: 173.2, wantCounty: "Aleutians West", wantRegion: "AK"},
+		{name: "west of antimeridian", latitude: 51.8, longitude: -177.6, wantCounty: "Aleutians West", wantRegion: "AK"},
+		{name: "ocean", latitude: 30, longitude: -40, wantErr: ErrCountyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Any county or region already on the location is replaced
+			input := Location{Latitude: tt.latitude, Longitude: tt.longitude, County: "stale"}
+			loc, err := b.ReverseGeocode(input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReverseGeocode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ReverseGeocode() unexpected error = %v", err)
+			}
+			if loc.County != tt.wantCounty || loc.Region != tt.wantRegion {
+				t.Errorf("ReverseGeocode() = %v, want %s County - %s", loc, tt.wantCounty, tt.wantRegion)
+			}
+			if loc.Latitude != tt.latitude || loc.Longitude != tt.longitude {
+				t.Errorf("ReverseGeocode() changed coordinates to %v", loc)
+			}
+		})
+	}
+
+	if _, err := b.ReverseGeocode(Location{Latitude: 100}); err == nil {
+		t.Errorf("ReverseGeocode() expected error for invalid location")
+	}
+}
+
+func TestCountyBoundaries_Import_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "malformed", input: `{"type":`},
+		{name: "not a collection", input: `{"type":"Feature"}`},
+		{name: "missing geometry", input: `{"type":"FeatureCollection","features":[{"properties":{"NAME":"X"}}]}`},
+		{
+			name: "point geometry",
+			input: `{"type":"FeatureCollection","features":[` +
+				`{"properties":{"NAME":"X"},"geometry":{"type":"Point","coordinates":[0,0]}}]}`,
+		},
+		{
+			name: "missing name",
+			input: `{"type":"FeatureCollection","features":[` +
+				`{"properties":{},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCountyBoundaries()
+			if _, err := b.Import(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Import() expected error but got none")
+			}
+			if b.Len() != 0 {
+				t.Errorf("Import() kept %d counties from a failed import", b.Len())
+			}
+		})
+	}
+}
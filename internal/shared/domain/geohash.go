@@ -0,0 +1,294 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// geohashAlphabet is the base32 alphabet used by the geohash encoding
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+	// MinGeohashPrecision is the shortest supported geohash (~5000km cells)
+	MinGeohashPrecision = 1
+	// MaxGeohashPrecision is the longest supported geohash (~4cm cells)
+	MaxGeohashPrecision = 12
+	// DefaultGeohashPrecision is the precision stored alongside coordinates (~150m cells)
+	DefaultGeohashPrecision = 7
+)
+
+// GeohashDirection identifies one of the eight neighbors of a geohash cell
+type GeohashDirection string
+
+const (
+	// North is the neighboring cell with higher latitude
+	North GeohashDirection = "n"
+	// NorthEast is the diagonal neighbor towards higher latitude and longitude
+	NorthEast GeohashDirection = "ne"
+	// East is the neighboring cell with higher longitude
+	East GeohashDirection = "e"
+	// SouthEast is the diagonal neighbor towards lower latitude and higher longitude
+	SouthEast GeohashDirection = "se"
+	// South is the neighboring cell with lower latitude
+	South GeohashDirection = "s"
+	// SouthWest is the diagonal neighbor towards lower latitude and longitude
+	SouthWest GeohashDirection = "sw"
+	// West is the neighboring cell with lower longitude
+	West GeohashDirection = "w"
+	// NorthWest is the diagonal neighbor towards higher latitude and lower longitude
+	NorthWest GeohashDirection = "nw"
+)
+
+// GeohashDirections lists every neighbor direction in clockwise order starting at North
+var GeohashDirections = []GeohashDirection{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+
+// GeohashCell is the rectangular area covered by a geohash
+type GeohashCell struct {
+	Hash   string  `json:"hash"`
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// Center returns the Location at the center of the cell
+func (c GeohashCell) Center() Location {
+	return Location{
+		Latitude:  (c.MinLat + c.MaxLat) / 2,
+		Longitude: (c.MinLng + c.MaxLng) / 2,
+	}
+}
+
+// Contains returns true if the location lies inside the cell. Cells are half-open
+// intervals, except along the north and east edges of the world.
+func (c GeohashCell) Contains(location Location) bool {
+	return location.Latitude >= c.MinLat &&
+		(location.Latitude < c.MaxLat || (c.MaxLat == 90 && location.Latitude == 90)) &&
+		location.Longitude >= c.MinLng &&
+		(location.Longitude < c.MaxLng || (c.MaxLng == 180 && location.Longitude == 180))
+}
+
+// Geohash encodes the location's coordinates as a geohash of the given precision
+// (number of characters, 1-12)
+func (l Location) Geohash(precision int) (string, error) {
+	if err := validateGeohashPrecision(precision); err != nil {
+		return "", err
+	}
+
+	if err := l.validate(); err != nil {
+		return "", err
+	}
+
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	hash.Grow(precision)
+
+	bits, value := 0, 0
+	evenBit := true
+	for hash.Len() < precision {
+		if evenBit {
+			mid := (minLng + maxLng) / 2
+			if l.Longitude >= mid {
+				value = value<<1 | 1
+				minLng = mid
+			} else {
+				value <<= 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if l.Latitude >= mid {
+				value = value<<1 | 1
+				minLat = mid
+			} else {
+				value <<= 1
+				maxLat = mid
+			}
+		}
+		evenBit = !evenBit
+
+		bits++
+		if bits == 5 {
+			hash.WriteByte(geohashAlphabet[value])
+			bits, value = 0, 0
+		}
+	}
+
+	return hash.String(), nil
+}
+
+// DecodeGeohash returns the Location at the center of the geohash cell.
+// County and Region are left empty.
+func DecodeGeohash(hash string) (Location, error) {
+	cell, err := GeohashBounds(hash)
+	if err != nil {
+		return Location{}, err
+	}
+
+	return cell.Center(), nil
+}
+
+// GeohashBounds returns the cell covered by a geohash. Every Location whose
+// geohash starts with this hash lies within the returned bounds, which makes it
+// usable for prefix-based bounding of search buckets.
+func GeohashBounds(hash string) (GeohashCell, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if err := validateGeohashPrecision(len(hash)); err != nil {
+		return GeohashCell{}, err
+	}
+
+	cell := GeohashCell{Hash: hash, MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+
+	evenBit := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(geohashAlphabet, hash[i])
+		if idx < 0 {
+			return GeohashCell{}, fmt.Errorf("invalid geohash character %q", hash[i])
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := idx>>uint(bit)&1 == 1
+			if evenBit {
+				mid := (cell.MinLng + cell.MaxLng) / 2
+				if set {
+					cell.MinLng = mid
+				} else {
+					cell.MaxLng = mid
+				}
+			} else {
+				mid := (cell.MinLat + cell.MaxLat) / 2
+				if set {
+					cell.MinLat = mid
+				} else {
+					cell.MaxLat = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+	}
+
+	return cell, nil
+}
+
+// GeohashNeighbor returns the adjacent geohash of the same precision in the given
+// direction. Neighbors wrap across the antimeridian; an empty string is returned
+// for cells beyond the poles.
+func GeohashNeighbor(hash string, direction GeohashDirection) (string, error) {
+	cell, err := GeohashBounds(hash)
+	if err != nil {
+		return "", err
+	}
+
+	latStep := cell.MaxLat - cell.MinLat
+	lngStep := cell.MaxLng - cell.MinLng
+	center := cell.Center()
+
+	var dLat, dLng float64
+	switch direction {
+	case North:
+		dLat = 1
+	case NorthEast:
+		dLat, dLng = 1, 1
+	case East:
+		dLng = 1
+	case SouthEast:
+		dLat, dLng = -1, 1
+	case South:
+		dLat = -1
+	case SouthWest:
+		dLat, dLng = -1, -1
+	case West:
+		dLng = -1
+	case NorthWest:
+		dLat, dLng = 1, -1
+	default:
+		return "", fmt.Errorf("unknown geohash direction %q", direction)
+	}
+
+	lat := center.Latitude + dLat*latStep
+	if lat > 90 || lat < -90 {
+		return "", nil
+	}
+
+	lng := center.Longitude + dLng*lngStep
+	if lng > 180 {
+		lng -= 360
+	} else if lng < -180 {
+		lng += 360
+	}
+
+	return Location{Latitude: lat, Longitude: lng}.Geohash(len(cell.Hash))
+}
+
+// GeohashNeighbors returns all eight neighbors of a geohash keyed by direction.
+// Directions that fall beyond a pole are omitted.
+func GeohashNeighbors(hash string) (map[GeohashDirection]string, error) {
+	neighbors := make(map[GeohashDirection]string, len(GeohashDirections))
+	for _, direction := range GeohashDirections {
+		neighbor, err := GeohashNeighbor(hash, direction)
+		if err != nil {
+			return nil, err
+		}
+		if neighbor != "" {
+			neighbors[direction] = neighbor
+		}
+	}
+
+	return neighbors, nil
+}
+
+// GeohashPrecisionForRadius returns the longest geohash precision whose cells are
+// at least radiusKm tall and, everywhere within radiusKm of latitude, at least
+// radiusKm wide, so a radius search around a point at that latitude only needs
+// the point's cell and its neighbors. Cells narrow towards the poles, so the
+// width is measured at the most poleward latitude the radius reaches. When the
+// radius reaches a pole no cell is wide enough and MinGeohashPrecision is
+// returned.
+func GeohashPrecisionForRadius(radiusKm, latitude float64) int {
+	maxLat := math.Min(math.Abs(latitude)+radiusKm/kmPerDegreeLatitude, 90)
+	widthScale := math.Cos(maxLat * math.Pi / 180)
+
+	precision := MinGeohashPrecision
+	for p := MinGeohashPrecision; p <= MaxGeohashPrecision; p++ {
+		latBits, lngBits := (5*p)/2, (5*p+1)/2
+		cellHeightKm := 180 / float64(uint64(1)<<uint(latBits)) * kmPerDegreeLatitude
+		cellWidthKm := 360 / float64(uint64(1)<<uint(lngBits)) * kmPerDegreeLatitude * widthScale
+		if math.Min(cellHeightKm, cellWidthKm) < radiusKm {
+			break
+		}
+		precision = p
+	}
+
+	return precision
+}
+
+// BucketByGeohash groups SpatialMatches by the geohash prefix of their Location,
+// preserving the order of matches within each bucket
+func BucketByGeohash(matches []SpatialMatch, precision int) (map[string][]SpatialMatch, error) {
+	if err := validateGeohashPrecision(precision); err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[string][]SpatialMatch)
+	for _, match := range matches {
+		hash, err := match.Location.Geohash(precision)
+		if err != nil {
+			return nil, err
+		}
+		buckets[hash] = append(buckets[hash], match)
+	}
+
+	return buckets, nil
+}
+
+// validateGeohashPrecision checks that precision is within the supported range
+func validateGeohashPrecision(precision int) error {
+	if precision < MinGeohashPrecision || precision > MaxGeohashPrecision {
+		return errors.New("geohash precision must be between 1 and 12")
+	}
+	return nil
+}
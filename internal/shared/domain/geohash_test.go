@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLocation_Geohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		precision int
+		expected  string
+		wantErr   bool
+	}{
+		{
+			name:      "reference point",
+			latitude:  57.64911,
+			longitude: 10.40744,
+			precision: 11,
+			expected:  "u4pruydqqvj",
+		},
+		{
+			name:      "new york",
+			latitude:  40.7128,
+			longitude: -74.0060,
+			precision: 5,
+			expected:  "dr5re",
+		},
+		{
+			name:      "single character",
+			latitude:  40.7128,
+			longitude: -74.0060,
+			precision: 1,
+			expected:  "d",
+		},
+		{
+			name:      "precision too low",
+			latitude:  40.7128,
+			longitude: -74.0060,
+			precision: 0,
+			wantErr:   true,
+		},
+		{
+			name:      "precision too high",
+			latitude:  40.7128,
+			longitude: -74.0060,
+			precision: 13,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid latitude",
+			latitude:  91,
+			longitude: 0,
+			precision: 5,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := Location{Latitude: tt.latitude, Longitude: tt.longitude}.Geohash(tt.precision)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Geohash() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Geohash() unexpected error = %v", err)
+			}
+			if hash != tt.expected {
+				t.Errorf("Geohash() = %v, want %v", hash, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	loc, err := DecodeGeohash("u4pruydqqvj")
+	if err != nil {
+		t.Fatalf("DecodeGeohash() unexpected error = %v", err)
+	}
+
+	if math.Abs(loc.Latitude-57.64911) > 1e-5 || math.Abs(loc.Longitude-10.40744) > 1e-5 {
+		t.Errorf("DecodeGeohash() = %v, want approximately 57.64911, 10.40744", loc)
+	}
+
+	// Decoding is case-insensitive
+	upper, err := DecodeGeohash("U4PRUYDQQVJ")
+	if err != nil || !upper.Equals(loc) {
+		t.Errorf("DecodeGeohash() upper case = %v, %v, want %v", upper, err, loc)
+	}
+
+	for _, hash := range []string{"", "u4pa", "u4pruydqqvjab"} {
+		if _, err := DecodeGeohash(hash); err == nil {
+			t.Errorf("DecodeGeohash(%q) expected error but got none", hash)
+		}
+	}
+}
+
+func TestGeohashBounds_ContainsEncodedLocations(t *testing.T) {
+	chicago, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+	hash, _ := chicago.Geohash(9)
+
+	for precision := 1; precision <= len(hash); precision++ {
+		cell, err := GeohashBounds(hash[:precision])
+		if err != nil {
+			t.Fatalf("GeohashBounds() unexpected error = %v", err)
+		}
+		if !cell.Contains(chicago) {
+			t.Errorf("GeohashBounds(%q) does not contain %v", hash[:precision], chicago)
+		}
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	neighbors, err := GeohashNeighbors("gbsuv")
+	if err != nil {
+		t.Fatalf("GeohashNeighbors() unexpected error = %v", err)
+	}
+
+	expected := map[GeohashDirection]string{
+		North:     "gbsvj",
+		NorthEast: "gbsvn",
+		East:      "gbsuy",
+		SouthEast: "gbsuw",
+		South:     "gbsut",
+		SouthWest: "gbsus",
+		West:      "gbsuu",
+		NorthWest: "gbsvh",
+	}
+
+	for direction, want := range expected {
+		if neighbors[direction] != want {
+			t.Errorf("GeohashNeighbors()[%v] = %v, want %v", direction, neighbors[direction], want)
+		}
+	}
+}
+
+func TestGeohashNeighbor_Edges(t *testing.T) {
+	// East of the easternmost cell wraps around the antimeridian
+	east, err := GeohashNeighbor("z", East)
+	if err != nil {
+		t.Fatalf("GeohashNeighbor() unexpected error = %v", err)
+	}
+	if east != "b" {
+		t.Errorf("GeohashNeighbor(z, East) = %v, want b", east)
+	}
+
+	// There is nothing north of the northernmost cell
+	north, err := GeohashNeighbor("z", North)
+	if err != nil {
+		t.Fatalf("GeohashNeighbor() unexpected error = %v", err)
+	}
+	if north != "" {
+		t.Errorf("GeohashNeighbor(z, North) = %v, want empty", north)
+	}
+
+	if _, err := GeohashNeighbor("z", GeohashDirection("up")); err == nil {
+		t.Errorf("GeohashNeighbor() expected error for unknown direction")
+	}
+}
+
+func TestGeohashPrecisionForRadius(t *testing.T) {
+	tests := []struct {
+		radiusKm float64
+		latitude float64
+		expected int
+	}{
+		{radiusKm: 10000, latitude: 0, expected: 1},
+		{radiusKm: 100, latitude: 0, expected: 3},
+		{radiusKm: 4, latitude: 0, expected: 5},
+		{radiusKm: 0, latitude: 0, expected: 12},
+		// Cells narrow away from the equator, so coarser cells are needed
+		{radiusKm: 100, latitude: 60, expected: 2},
+		{radiusKm: 4, latitude: -45, expected: 4},
+		{radiusKm: 100, latitude: 89.5, expected: 1},
+	}
+
+	for _, tt := range tests {
+		if got := GeohashPrecisionForRadius(tt.radiusKm, tt.latitude); got != tt.expected {
+			t.Errorf("GeohashPrecisionForRadius(%v, %v) = %v, want %v", tt.radiusKm, tt.latitude, got, tt.expected)
+		}
+	}
+}
+
+func TestBucketByGeohash(t *testing.T) {
+	nyc, _ := NewLocation(40.7128, -74.0060, "New York", "NY")
+	brooklyn, _ := NewLocation(40.6782, -73.9442, "Kings", "NY")
+	chicago, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	matches := []SpatialMatch{
+		{ID: "a", Location: nyc},
+		{ID: "b", Location: chicago},
+		{ID: "c", Location: brooklyn},
+	}
+
+	buckets, err := BucketByGeohash(matches, 3)
+	if err != nil {
+		t.Fatalf("BucketByGeohash() unexpected error = %v", err)
+	}
+
+	if len(buckets) != 2 {
+		t.Fatalf("BucketByGeohash() returned %d buckets, want 2", len(buckets))
+	}
+	if got := buckets["dr5"]; len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Errorf("BucketByGeohash()[dr5] = %v, want [a c]", got)
+	}
+	if got := buckets["dp3"]; len(got) != 1 || got[0].ID != "b" {
+		t.Errorf("BucketByGeohash()[dp3] = %v, want [b]", got)
+	}
+
+	if _, err := BucketByGeohash(matches, 0); err == nil {
+		t.Errorf("BucketByGeohash() expected error for invalid precision")
+	}
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// GeoJSON object types used by the RFC 7946 encoding
+const (
+	GeoJSONTypePoint             = "Point"
+	GeoJSONTypePolygon           = "Polygon"
+	GeoJSONTypeMultiPolygon      = "MultiPolygon"
+	GeoJSONTypeFeature           = "Feature"
+	GeoJSONTypeFeatureCollection = "FeatureCollection"
+)
+
+// GeoJSONGeometry is an RFC 7946 geometry object. Coordinates are kept raw so the
+// shape can be decoded according to Type.
+type GeoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// GeoJSONFeature is an RFC 7946 Feature. Geometry is nil for unlocated features,
+// which encodes as "geometry": null.
+type GeoJSONFeature struct {
+	Type       string           `json:"type"`
+	ID         string           `json:"id,omitempty"`
+	Geometry   *GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any   `json:"properties"`
+}
+
+// GeoJSONFeatureCollection is an RFC 7946 FeatureCollection
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// NewGeoJSONFeatureCollection creates a FeatureCollection holding the given features
+func NewGeoJSONFeatureCollection(features ...GeoJSONFeature) GeoJSONFeatureCollection {
+	collection := GeoJSONFeatureCollection{
+		Type:     GeoJSONTypeFeatureCollection,
+		Features: make([]GeoJSONFeature, 0, len(features)),
+	}
+
+	return collection.With(features...)
+}
+
+// With returns a new FeatureCollection with the given features appended
+func (c GeoJSONFeatureCollection) With(features ...GeoJSONFeature) GeoJSONFeatureCollection {
+	out := make([]GeoJSONFeature, 0, len(c.Features)+len(features))
+	out = append(out, c.Features...)
+	out = append(out, features...)
+
+	return GeoJSONFeatureCollection{Type: GeoJSONTypeFeatureCollection, Features: out}
+}
+
+// GeoJSONPoint returns the location as an RFC 7946 Point geometry ([longitude, latitude])
+func (l Location) GeoJSONPoint() *GeoJSONGeometry {
+	coordinates := "[" + strconv.FormatFloat(l.Longitude, 'f', -1, 64) + "," +
+		strconv.FormatFloat(l.Latitude, 'f', -1, 64) + "]"
+
+	return &GeoJSONGeometry{Type: GeoJSONTypePoint, Coordinates: json.RawMessage(coordinates)}
+}
+
+// GeoJSONFeature returns the location as a Point Feature with county and region
+// as properties
+func (l Location) GeoJSONFeature() GeoJSONFeature {
+	return GeoJSONFeature{
+		Type:     GeoJSONTypeFeature,
+		Geometry: l.GeoJSONPoint(),
+		Properties: map[string]any{
+			"county": l.County,
+			"region": l.Region,
+		},
+	}
+}
+
+// GeoJSONFeature returns the address as a Point Feature at its Location, with the
+// address components as properties. Addresses without a Location have a null geometry.
+func (a Address) GeoJSONFeature() GeoJSONFeature {
+	var geometry *GeoJSONGeometry
+	if !a.Location.IsEmpty() {
+		geometry = a.Location.GeoJSONPoint()
+	}
+
+	return GeoJSONFeature{
+		Type:     GeoJSONTypeFeature,
+		Geometry: geometry,
+		Properties: map[string]any{
+			"street":   a.Street,
+			"city":     a.City,
+			"state":    a.State,
+			"zip_code": a.ZipCode,
+			"county":   a.Location.County,
+			"region":   a.Location.Region,
+		},
+	}
+}
+
+// GeoJSONFeature returns the match as a Point Feature identified by the match ID,
+// with its distance from the query center as a property
+func (m SpatialMatch) GeoJSONFeature() GeoJSONFeature {
+	feature := m.Location.GeoJSONFeature()
+	feature.ID = m.ID
+	feature.Properties["distance"] = m.Distance
+
+	return feature
+}
+
+// LocationFromGeoJSON decodes a Point geometry, or a Feature with a Point geometry,
+// into a validated Location. County and region are read from Feature properties
+// when present. An optional third (altitude) coordinate is ignored.
+func LocationFromGeoJSON(data []byte) (Location, error) {
+	var object struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+		Geometry    json.RawMessage `json:"geometry"`
+		Properties  map[string]any  `json:"properties"`
+	}
+
+	if err := json.Unmarshal(data, &object); err != nil {
+		return Location{}, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	switch object.Type {
+	case GeoJSONTypePoint:
+		return pointFromGeoJSON(object.Coordinates, "", "")
+	case GeoJSONTypeFeature:
+		var geometry *GeoJSONGeometry
+		if err := json.Unmarshal(object.Geometry, &geometry); err != nil {
+			return Location{}, fmt.Errorf("invalid GeoJSON geometry: %w", err)
+		}
+		if geometry == nil {
+			return Location{}, errors.New("GeoJSON feature has no geometry")
+		}
+		if geometry.Type != GeoJSONTypePoint {
+			return Location{}, fmt.Errorf("GeoJSON geometry must be a Point, got %q", geometry.Type)
+		}
+
+		county, err := stringProperty(object.Properties, "county")
+		if err != nil {
+			return Location{}, err
+		}
+		region, err := stringProperty(object.Properties, "region")
+		if err != nil {
+			return Location{}, err
+		}
+
+		return pointFromGeoJSON(geometry.Coordinates, county, region)
+	default:
+		return Location{}, fmt.Errorf("GeoJSON type must be Point or Feature, got %q", object.Type)
+	}
+}
+
+// pointFromGeoJSON decodes Point coordinates into a validated Location
+func pointFromGeoJSON(raw json.RawMessage, county, region string) (Location, error) {
+	var coordinates []float64
+	if err := json.Unmarshal(raw, &coordinates); err != nil {
+		return Location{}, fmt.Errorf("invalid GeoJSON coordinates: %w", err)
+	}
+
+	if len(coordinates) < 2 || len(coordinates) > 3 {
+		return Location{}, errors.New("GeoJSON Point must have 2 or 3 coordinates")
+	}
+
+	return NewLocation(coordinates[1], coordinates[0], county, region)
+}
+
+// PolygonsFromGeoJSON decodes a Polygon or MultiPolygon geometry into validated
+// Polygons. The first ring of each polygon is its exterior and any further rings
+// are holes.
+func PolygonsFromGeoJSON(geometry GeoJSONGeometry) ([]Polygon, error) {
+	var rawPolygons [][][][]float64
+	switch geometry.Type {
+	case GeoJSONTypePolygon:
+		var rings [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON coordinates: %w", err)
+		}
+		rawPolygons = [][][][]float64{rings}
+	case GeoJSONTypeMultiPolygon:
+		if err := json.Unmarshal(geometry.Coordinates, &rawPolygons); err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON coordinates: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("GeoJSON geometry must be a Polygon or MultiPolygon, got %q", geometry.Type)
+	}
+
+	polygons := make([]Polygon, 0, len(rawPolygons))
+	for _, rawRings := range rawPolygons {
+		if len(rawRings) == 0 {
+			return nil, errors.New("GeoJSON polygon has no rings")
+		}
+
+		rings := make([][]Location, 0, len(rawRings))
+		for _, rawRing := range rawRings {
+			ring := make([]Location, 0, len(rawRing))
+			for _, position := range rawRing {
+				if len(position) < 2 || len(position) > 3 {
+					return nil, errors.New("GeoJSON position must have 2 or 3 coordinates")
+				}
+				ring = append(ring, Location{Latitude: position[1], Longitude: position[0]})
+			}
+			rings = append(rings, ring)
+		}
+
+		polygon, err := NewPolygon(rings[0], rings[1:]...)
+		if err != nil {
+			return nil, err
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	return polygons, nil
+}
+
+// stringProperty reads an optional string property from a Feature
+func stringProperty(properties map[string]any, key string) (string, error) {
+	value, ok := properties[key]
+	if !ok || value == nil {
+		return "", nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("GeoJSON property %q must be a string", key)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLocation_GeoJSONFeature(t *testing.T) {
+	loc, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	data, err := json.Marshal(loc.GeoJSONFeature())
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error = %v", err)
+	}
+
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[-87.6298,41.8781]},` +
+		`"properties":{"county":"Cook","region":"IL"}}`
+	if string(data) != expected {
+		t.Errorf("GeoJSONFeature() = %s, want %s", data, expected)
+	}
+}
+
+func TestAddress_GeoJSONFeature(t *testing.T) {
+	loc, _ := NewLocation(39.7817, -89.6501, "Sangamon", "IL")
+	addr, _ := NewAddress("123 Main St", "Springfield", "IL", "62701", loc)
+
+	feature := addr.GeoJSONFeature()
+	if feature.Geometry == nil || string(feature.Geometry.Coordinates) != "[-89.6501,39.7817]" {
+		t.Errorf("GeoJSONFeature() geometry = %v, want point at address location", feature.Geometry)
+	}
+	if feature.Properties["street"] != "123 Main St" || feature.Properties["zip_code"] != "62701" {
+		t.Errorf("GeoJSONFeature() properties = %v, want address components", feature.Properties)
+	}
+
+	// Unlocated addresses encode a null geometry
+	unlocated, _ := NewAddress("123 Main St", "Springfield", "IL", "62701", Location{})
+	data, _ := json.Marshal(unlocated.GeoJSONFeature())
+
+	var decoded map[string]json.RawMessage
+	_ = json.Unmarshal(data, &decoded)
+	if string(decoded["geometry"]) != "null" {
+		t.Errorf("GeoJSONFeature() geometry = %s, want null", decoded["geometry"])
+	}
+}
+
+func TestGeoJSONFeatureCollection(t *testing.T) {
+	empty := NewGeoJSONFeatureCollection()
+	data, _ := json.Marshal(empty)
+	if string(data) != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("NewGeoJSONFeatureCollection() = %s, want empty features array", data)
+	}
+
+	loc, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+	match := SpatialMatch{ID: "school-1", Location: loc, Distance: 3.5}
+
+	collection := empty.With(match.GeoJSONFeature())
+	if len(empty.Features) != 0 {
+		t.Errorf("With() modified the original collection")
+	}
+	if len(collection.Features) != 1 {
+		t.Fatalf("With() features = %d, want 1", len(collection.Features))
+	}
+
+	feature := collection.Features[0]
+	if feature.ID != "school-1" || feature.Properties["distance"] != 3.5 {
+		t.Errorf("SpatialMatch.GeoJSONFeature() = %+v, want id and distance", feature)
+	}
+}
+
+func TestLocationFromGeoJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Location
+		wantErr  bool
+	}{
+		{
+			name:     "point",
+			input:    `{"type":"Point","coordinates":[-87.6298,41.8781]}`,
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298},
+		},
+		{
+			name:     "point with altitude",
+			input:    `{"type":"Point","coordinates":[-87.6298,41.8781,180]}`,
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298},
+		},
+		{
+			name: "feature with properties",
+			input: `{"type":"Feature","geometry":{"type":"Point","coordinates":[-87.6298,41.8781]},` +
+				`"properties":{"county":" Cook ","region":"IL","name":"ignored"}}`,
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "feature with null properties",
+			input:    `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}`,
+			expected: Location{Latitude: 2, Longitude: 1},
+		},
+		{name: "malformed json", input: `{"type":`, wantErr: true},
+		{name: "unsupported type", input: `{"type":"LineString","coordinates":[[0,0],[1,1]]}`, wantErr: true},
+		{name: "too few coordinates", input: `{"type":"Point","coordinates":[1]}`, wantErr: true},
+		{name: "latitude out of range", input: `{"type":"Point","coordinates":[0,91]}`, wantErr: true},
+		{name: "swapped coordinates", input: `{"type":"Point","coordinates":[41.8781,-187.6298]}`, wantErr: true},
+		{name: "null geometry", input: `{"type":"Feature","geometry":null,"properties":{}}`, wantErr: true},
+		{
+			name:    "non-point feature",
+			input:   `{"type":"Feature","geometry":{"type":"Polygon","coordinates":[]},"properties":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "non-string county",
+			input:   `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"county":5}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LocationFromGeoJSON([]byte(tt.input))
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LocationFromGeoJSON() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LocationFromGeoJSON() unexpected error = %v", err)
+			}
+			if !loc.Equals(tt.expected) {
+				t.Errorf("LocationFromGeoJSON() = %v, want %v", loc, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocationFromGeoJSON_RoundTrip(t *testing.T) {
+	original, _ := NewLocation(-16.5, 179.9, "Cakaudrove", "Northern")
+
+	data, _ := json.Marshal(original.GeoJSONFeature())
+	decoded, err := LocationFromGeoJSON(data)
+	if err != nil {
+		t.Fatalf("LocationFromGeoJSON() unexpected error = %v", err)
+	}
+	if !decoded.Equals(original) {
+		t.Errorf("LocationFromGeoJSON() = %v, want %v", decoded, original)
+	}
+}
+
+func TestPolygonsFromGeoJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		geometry  GeoJSONGeometry
+		wantCount int
+		wantHoles int
+		wantErr   bool
+	}{
+		{
+			name: "polygon with hole",
+			geometry: GeoJSONGeometry{
+				Type: GeoJSONTypePolygon,
+				Coordinates: json.RawMessage(`[[[0,0],[10,0],[10,10],[0,10],[0,0]],` +
+					`[[4,4],[6,4],[6,6],[4,6],[4,4]]]`),
+			},
+			wantCount: 1,
+			wantHoles: 1,
+		},
+		{
+			name: "multipolygon",
+			geometry: GeoJSONGeometry{
+				Type: GeoJSONTypeMultiPolygon,
+				Coordinates: json.RawMessage(`[[[[0,0],[1,0],[1,1],[0,0]]],` +
+					`[[[5,5],[6,5],[6,6],[5,5]]]]`),
+			},
+			wantCount: 2,
+		},
+		{
+			name:     "point",
+			geometry: GeoJSONGeometry{Type: GeoJSONTypePoint, Coordinates: json.RawMessage(`[0,0]`)},
+			wantErr:  true,
+		},
+		{
+			name:     "too few vertices",
+			geometry: GeoJSONGeometry{Type: GeoJSONTypePolygon, Coordinates: json.RawMessage(`[[[0,0],[1,1],[0,0]]]`)},
+			wantErr:  true,
+		},
+		{
+			name:     "no rings",
+			geometry: GeoJSONGeometry{Type: GeoJSONTypeMultiPolygon, Coordinates: json.RawMessage(`[[]]`)},
+			wantErr:  true,
+		},
+		{
+			name:     "out of range",
+			geometry: GeoJSONGeometry{Type: GeoJSONTypePolygon, Coordinates: json.RawMessage(`[[[0,0],[0,95],[1,1]]]`)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygons, err := PolygonsFromGeoJSON(tt.geometry)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("PolygonsFromGeoJSON() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("PolygonsFromGeoJSON() unexpected error = %v", err)
+			}
+			if len(polygons) != tt.wantCount {
+				t.Fatalf("PolygonsFromGeoJSON() returned %d polygons, want %d", len(polygons), tt.wantCount)
+			}
+			if len(polygons[0].Holes) != tt.wantHoles {
+				t.Errorf("PolygonsFromGeoJSON() holes = %d, want %d", len(polygons[0].Holes), tt.wantHoles)
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package domain
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"strings"
+
+	"hrh-backend/internal/shared"
 )
 
+// earthRadiusKm is the mean Earth radius used for all great-circle calculations
+const earthRadiusKm = 6371.0
+
 // Location represents geographic coordinates and standardized address components
 // This is a Value Object - immutable and defined by its attributes
 type Location struct {
@@ -47,19 +51,22 @@ func (l Location) WithRegion(region string) (Location, error) {
 	return NewLocation(l.Latitude, l.Longitude, l.County, region)
 }
 
-// validate performs validation on location components
+// validate performs validation on location components, reporting every
+// invalid coordinate as a *shared.ValidationError
 func (l Location) validate() error {
-	// Validate latitude range (-90 to 90)
-	if l.Latitude < -90 || l.Latitude > 90 {
-		return errors.New("latitude must be between -90 and 90 degrees")
+	verr := &shared.ValidationError{}
+
+	// Validate latitude range (-90 to 90); the negated form also rejects NaN
+	if !(l.Latitude >= -90 && l.Latitude <= 90) {
+		verr.Add("latitude", shared.CodeLocationLatitudeRange, "latitude must be between -90 and 90 degrees")
 	}
 
 	// Validate longitude range (-180 to 180)
-	if l.Longitude < -180 || l.Longitude > 180 {
-		return errors.New("longitude must be between -180 and 180 degrees")
+	if !(l.Longitude >= -180 && l.Longitude <= 180) {
+		verr.Add("longitude", shared.CodeLocationLongitudeRange, "longitude must be between -180 and 180 degrees")
 	}
 
-	return nil
+	return verr.ErrOrNil()
 }
 
 // IsEmpty returns true if the location has no meaningful data
@@ -69,8 +76,6 @@ func (l Location) IsEmpty() bool {
 
 // DistanceTo calculates the distance in kilometers to another location using Haversine formula
 func (l Location) DistanceTo(other Location) float64 {
-	const earthRadiusKm = 6371.0
-
 	lat1Rad := l.Latitude * math.Pi / 180
 	lat2Rad := other.Latitude * math.Pi / 180
 	deltaLatRad := (other.Latitude - l.Latitude) * math.Pi / 180
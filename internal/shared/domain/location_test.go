@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"errors"
 	"math"
 	"testing"
+
+	"hrh-backend/internal/shared"
 )
 
 func TestNewLocation(t *testing.T) {
@@ -332,3 +335,21 @@ func TestLocation_WithCoordinates_ValidationError(t *testing.T) {
 		t.Errorf("WithCoordinates() expected error for invalid longitude")
 	}
 }
+
+func TestNewLocation_ValidationErrors(t *testing.T) {
+	_, err := NewLocation(math.NaN(), 181, "", "")
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("NewLocation() error = %v, want *shared.ValidationError", err)
+	}
+
+	if !verr.HasCode(shared.CodeLocationLatitudeRange) || !verr.HasCode(shared.CodeLocationLongitudeRange) {
+		t.Errorf("expected latitude and longitude codes, got %+v", verr.Fields)
+	}
+
+	want := "latitude must be between -90 and 90 degrees; longitude must be between -180 and 180 degrees"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// Polygon represents an area such as a county outline or a district boundary
+// This is a Value Object - immutable and defined by its attributes
+//
+// Rings are lists of vertices; the closing vertex may be omitted. Consecutive
+// vertices are joined by the shorter way around the globe, so a ring may cross the
+// antimeridian. Rings must not enclose a pole.
+type Polygon struct {
+	Exterior []Location   `json:"exterior"`
+	Holes    [][]Location `json:"holes,omitempty"`
+}
+
+// NewPolygon creates a new Polygon with validation
+func NewPolygon(exterior []Location, holes ...[]Location) (Polygon, error) {
+	poly := Polygon{
+		Exterior: openRing(exterior),
+		Holes:    make([][]Location, 0, len(holes)),
+	}
+
+	for _, hole := range holes {
+		poly.Holes = append(poly.Holes, openRing(hole))
+	}
+
+	if err := poly.validate(); err != nil {
+		return Polygon{}, err
+	}
+
+	return poly, nil
+}
+
+// openRing copies a ring, dropping the closing vertex if it repeats the first one
+func openRing(ring []Location) []Location {
+	out := make([]Location, len(ring))
+	copy(out, ring)
+
+	if len(out) > 1 && out[0].Latitude == out[len(out)-1].Latitude &&
+		out[0].Longitude == out[len(out)-1].Longitude {
+		out = out[:len(out)-1]
+	}
+
+	return out
+}
+
+// validate performs validation on polygon rings
+func (p Polygon) validate() error {
+	if len(p.Exterior) < 3 {
+		return errors.New("polygon exterior must have at least 3 vertices")
+	}
+
+	for _, ring := range append([][]Location{p.Exterior}, p.Holes...) {
+		if len(ring) < 3 {
+			return errors.New("polygon hole must have at least 3 vertices")
+		}
+		for _, vertex := range ring {
+			if err := vertex.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Bounds returns the smallest BoundingBox containing the exterior ring
+func (p Polygon) Bounds() BoundingBox {
+	ring := p.planarRings()[0]
+
+	minX, maxX := ring[0].x, ring[0].x
+	minY, maxY := ring[0].y, ring[0].y
+	for _, pt := range ring[1:] {
+		minX, maxX = math.Min(minX, pt.x), math.Max(maxX, pt.x)
+		minY, maxY = math.Min(minY, pt.y), math.Max(maxY, pt.y)
+	}
+
+	return lngInterval{west: minX, width: maxX - minX}.toBox(minY, maxY)
+}
+
+// Contains returns true if the location lies inside the exterior ring and outside
+// every hole
+func (p Polygon) Contains(location Location) bool {
+	if !p.Bounds().Contains(location) {
+		return false
+	}
+
+	return p.containsPlanar(p.planarRings(), location)
+}
+
+// Intersects returns true if the two polygons share at least one point
+func (p Polygon) Intersects(other Polygon) bool {
+	if !p.Bounds().Intersects(other.Bounds()) {
+		return false
+	}
+
+	return p.intersectsRings(other.planarRings(), other.Exterior[0], other.Contains)
+}
+
+// IntersectsBox returns true if the polygon and the box share at least one point
+func (p Polygon) IntersectsBox(box BoundingBox) bool {
+	if !p.Bounds().Intersects(box) {
+		return false
+	}
+
+	return p.intersectsRings([]planarRing{boxRing(box)}, Location{Latitude: box.South, Longitude: box.West}, box.Contains)
+}
+
+// WithinDistance returns true if the location lies inside the polygon or no further
+// than distance from its boundary, i.e. inside the polygon expanded by distance.
+// Distances to the boundary use a local flat-earth approximation, which is accurate
+// at county and district scale.
+func (p Polygon) WithinDistance(location Location, distance float64, unit DistanceUnit) (bool, error) {
+	expanded, err := p.Bounds().ExpandBy(distance, unit)
+	if err != nil {
+		return false, err
+	}
+
+	if !expanded.Contains(location) {
+		return false, nil
+	}
+
+	rings := p.planarRings()
+	if p.containsPlanar(rings, location) {
+		return true, nil
+	}
+
+	limitKm := unit.ToKilometers(distance)
+	kmPerDegreeLng := kmPerDegreeLatitude * math.Cos(location.Latitude*math.Pi/180)
+	for _, ring := range rings {
+		x := alignLongitude(location.Longitude, ring)
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			ax, ay := (a.x-x)*kmPerDegreeLng, (a.y-location.Latitude)*kmPerDegreeLatitude
+			bx, by := (b.x-x)*kmPerDegreeLng, (b.y-location.Latitude)*kmPerDegreeLatitude
+			if distanceToSegment(ax, ay, bx, by) <= limitKm {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// containsPlanar tests containment against precomputed planar rings
+func (p Polygon) containsPlanar(rings []planarRing, location Location) bool {
+	exterior := rings[0]
+	if !exterior.contains(alignLongitude(location.Longitude, exterior), location.Latitude) {
+		return false
+	}
+
+	for _, hole := range rings[1:] {
+		if hole.contains(alignLongitude(location.Longitude, hole), location.Latitude) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersectsRings tests whether any edge of the polygon crosses any of the other
+// rings, or whether either shape contains a vertex of the other
+func (p Polygon) intersectsRings(others []planarRing, otherVertex Location, otherContains func(Location) bool) bool {
+	if p.Contains(otherVertex) || otherContains(p.Exterior[0]) {
+		return true
+	}
+
+	rings := p.planarRings()
+	for _, ring := range rings {
+		for _, other := range others {
+			shift := alignLongitude(other[0].x, ring) - other[0].x
+			if ring.crosses(other, shift) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// planarPoint is a vertex on an unwrapped longitude/latitude plane
+type planarPoint struct {
+	x float64
+	y float64
+}
+
+// planarRing is a ring whose longitudes are unwrapped so consecutive vertices are
+// never more than 180 degrees apart
+type planarRing []planarPoint
+
+// planarRings unwraps the exterior and holes into a shared longitude frame
+func (p Polygon) planarRings() []planarRing {
+	exterior := unwrapRing(p.Exterior, p.Exterior[0].Longitude)
+	rings := []planarRing{exterior}
+
+	for _, hole := range p.Holes {
+		rings = append(rings, unwrapRing(hole, alignLongitude(hole[0].Longitude, exterior)))
+	}
+
+	return rings
+}
+
+// unwrapRing converts a ring to planar points, starting at the given longitude
+func unwrapRing(ring []Location, startX float64) planarRing {
+	out := make(planarRing, len(ring))
+	out[0] = planarPoint{x: startX, y: ring[0].Latitude}
+
+	for i := 1; i < len(ring); i++ {
+		delta := ring[i].Longitude - ring[i-1].Longitude
+		if delta > 180 {
+			delta -= 360
+		} else if delta < -180 {
+			delta += 360
+		}
+		out[i] = planarPoint{x: out[i-1].x + delta, y: ring[i].Latitude}
+	}
+
+	return out
+}
+
+// boxRing converts a BoundingBox to a planar ring. Edges are split so that no two
+// consecutive vertices are more than 90 degrees of longitude apart.
+func boxRing(box BoundingBox) planarRing {
+	lng := box.lngInterval()
+	steps := int(math.Max(1, math.Ceil(lng.width/90)))
+
+	ring := planarRing{}
+	for i := 0; i <= steps; i++ {
+		ring = append(ring, planarPoint{x: lng.west + lng.width*float64(i)/float64(steps), y: box.South})
+	}
+	for i := steps; i >= 0; i-- {
+		ring = append(ring, planarPoint{x: lng.west + lng.width*float64(i)/float64(steps), y: box.North})
+	}
+
+	return ring
+}
+
+// alignLongitude shifts a longitude by whole revolutions so it is as close as
+// possible to the middle of the ring's unwrapped longitude range
+func alignLongitude(longitude float64, ring planarRing) float64 {
+	minX, maxX := ring[0].x, ring[0].x
+	for _, pt := range ring[1:] {
+		minX, maxX = math.Min(minX, pt.x), math.Max(maxX, pt.x)
+	}
+
+	mid := (minX + maxX) / 2
+	return longitude + 360*math.Round((mid-longitude)/360)
+}
+
+// contains is an even-odd ray casting test on the plane
+func (r planarRing) contains(x, y float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.y > y) != (b.y > y) && x < (b.x-a.x)*(y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// crosses returns true if any edge of r intersects any edge of other after
+// shifting other by shift degrees of longitude
+func (r planarRing) crosses(other planarRing, shift float64) bool {
+	for i := range r {
+		a1, a2 := r[i], r[(i+1)%len(r)]
+		for j := range other {
+			b1, b2 := other[j], other[(j+1)%len(other)]
+			b1.x += shift
+			b2.x += shift
+			if segmentsIntersect(a1, a2, b1, b2) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// segmentsIntersect returns true if segments p1-p2 and q1-q2 share a point
+func segmentsIntersect(p1, p2, q1, q2 planarPoint) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(q1, q2, p1)) ||
+		(d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) ||
+		(d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// orientation returns the sign of the cross product (b-a) x (c-a)
+func orientation(a, b, c planarPoint) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+// onSegment returns true if c, known to be collinear with a-b, lies between them
+func onSegment(a, b, c planarPoint) bool {
+	return math.Min(a.x, b.x) <= c.x && c.x <= math.Max(a.x, b.x) &&
+		math.Min(a.y, b.y) <= c.y && c.y <= math.Max(a.y, b.y)
+}
+
+// distanceToSegment returns the distance from the origin to segment a-b
+func distanceToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+
+	t := 0.0
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	}
+
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"testing"
+)
+
+func square(south, west, north, east float64) []Location {
+	return []Location{
+		{Latitude: south, Longitude: west},
+		{Latitude: south, Longitude: east},
+		{Latitude: north, Longitude: east},
+		{Latitude: north, Longitude: west},
+	}
+}
+
+func TestNewPolygon(t *testing.T) {
+	closed := append(square(0, 0, 1, 1), Location{Latitude: 0, Longitude: 0})
+
+	poly, err := NewPolygon(closed)
+	if err != nil {
+		t.Fatalf("NewPolygon() unexpected error = %v", err)
+	}
+	if len(poly.Exterior) != 4 {
+		t.Errorf("NewPolygon() kept closing vertex, got %d vertices", len(poly.Exterior))
+	}
+
+	// The polygon must not share the caller's slice
+	closed[1].Latitude = 50
+	if poly.Exterior[1].Latitude != 0 {
+		t.Errorf("NewPolygon() aliased the input ring")
+	}
+
+	if _, err := NewPolygon(square(0, 0, 1, 1)[:2]); err == nil {
+		t.Errorf("NewPolygon() expected error for 2-vertex ring")
+	}
+	if _, err := NewPolygon(square(0, 0, 1, 1), square(0.2, 0.2, 0.4, 0.4)[:2]); err == nil {
+		t.Errorf("NewPolygon() expected error for 2-vertex hole")
+	}
+	if _, err := NewPolygon([]Location{{Latitude: 95}, {}, {Latitude: 1}}); err == nil {
+		t.Errorf("NewPolygon() expected error for invalid vertex")
+	}
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	withHole, _ := NewPolygon(square(0, 0, 10, 10), square(4, 4, 6, 6))
+	wrapped, _ := NewPolygon(square(-20, 170, -10, -170))
+	triangle, _ := NewPolygon([]Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 10},
+		{Latitude: 10, Longitude: 0},
+	})
+
+	tests := []struct {
+		name     string
+		polygon  Polygon
+		location Location
+		expected bool
+	}{
+		{name: "inside", polygon: withHole, location: Location{Latitude: 2, Longitude: 2}, expected: true},
+		{name: "in hole", polygon: withHole, location: Location{Latitude: 5, Longitude: 5}, expected: false},
+		{name: "outside", polygon: withHole, location: Location{Latitude: 12, Longitude: 5}, expected: false},
+		{name: "inside triangle", polygon: triangle, location: Location{Latitude: 2, Longitude: 2}, expected: true},
+		{name: "outside triangle within bounds", polygon: triangle, location: Location{Latitude: 8, Longitude: 8}, expected: false},
+		{name: "wrapped east side", polygon: wrapped, location: Location{Latitude: -15, Longitude: 175}, expected: true},
+		{name: "wrapped west side", polygon: wrapped, location: Location{Latitude: -15, Longitude: -175}, expected: true},
+		{name: "outside wrapped", polygon: wrapped, location: Location{Latitude: -15, Longitude: 0}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.polygon.Contains(tt.location); result != tt.expected {
+				t.Errorf("Contains() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolygon_Bounds(t *testing.T) {
+	wrapped, _ := NewPolygon(square(-20, 170, -10, -170))
+
+	expected := BoundingBox{South: -20, West: 170, North: -10, East: -170}
+	if bounds := wrapped.Bounds(); !bounds.Equals(expected) {
+		t.Errorf("Bounds() = %v, want %v", bounds, expected)
+	}
+}
+
+func TestPolygon_Intersects(t *testing.T) {
+	a, _ := NewPolygon(square(0, 0, 10, 10))
+	overlapping, _ := NewPolygon(square(5, 5, 15, 15))
+	inside, _ := NewPolygon(square(2, 2, 3, 3))
+	disjoint, _ := NewPolygon(square(20, 20, 30, 30))
+	// An L-shape whose bounds overlap a but whose area does not
+	lShape, _ := NewPolygon([]Location{
+		{Latitude: 11, Longitude: -5},
+		{Latitude: 11, Longitude: 20},
+		{Latitude: 12, Longitude: 20},
+		{Latitude: 12, Longitude: -4},
+		{Latitude: 5, Longitude: -4},
+		{Latitude: 5, Longitude: -5},
+	})
+	westOfLine, _ := NewPolygon(square(-15, 175, -5, 179.5))
+	acrossLine, _ := NewPolygon(square(-12, 179, -8, -179))
+
+	tests := []struct {
+		name     string
+		poly1    Polygon
+		poly2    Polygon
+		expected bool
+	}{
+		{name: "overlapping", poly1: a, poly2: overlapping, expected: true},
+		{name: "contained", poly1: a, poly2: inside, expected: true},
+		{name: "container", poly1: inside, poly2: a, expected: true},
+		{name: "disjoint", poly1: a, poly2: disjoint, expected: false},
+		{name: "bounds overlap only", poly1: a, poly2: lShape, expected: false},
+		{name: "across antimeridian", poly1: westOfLine, poly2: acrossLine, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.poly1.Intersects(tt.poly2); result != tt.expected {
+				t.Errorf("Intersects() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolygon_IntersectsBox(t *testing.T) {
+	triangle, _ := NewPolygon([]Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 10},
+		{Latitude: 10, Longitude: 0},
+	})
+
+	crossing, _ := NewBoundingBox(4, 4, 6, 12)
+	corner, _ := NewBoundingBox(8, 8, 10, 10)
+
+	if !triangle.IntersectsBox(crossing) {
+		t.Errorf("IntersectsBox() = false, want true")
+	}
+	if triangle.IntersectsBox(corner) {
+		t.Errorf("IntersectsBox() = true for box outside the hypotenuse")
+	}
+}
+
+func TestPolygon_WithinDistance(t *testing.T) {
+	// Roughly 11km on each side near the equator
+	poly, _ := NewPolygon(square(0, 0, 0.1, 0.1))
+
+	tests := []struct {
+		name     string
+		location Location
+		distance float64
+		unit     DistanceUnit
+		expected bool
+	}{
+		{name: "inside", location: Location{Latitude: 0.05, Longitude: 0.05}, distance: 0, unit: Kilometers, expected: true},
+		{name: "just outside within range", location: Location{Latitude: 0.05, Longitude: 0.12}, distance: 5, unit: Kilometers, expected: true},
+		{name: "just outside beyond range", location: Location{Latitude: 0.05, Longitude: 0.12}, distance: 1, unit: Kilometers, expected: false},
+		{name: "near corner in miles", location: Location{Latitude: 0.11, Longitude: 0.11}, distance: 2, unit: Miles, expected: true},
+		{name: "far away", location: Location{Latitude: 10, Longitude: 10}, distance: 50, unit: Kilometers, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := poly.WithinDistance(tt.location, tt.distance, tt.unit)
+			if err != nil {
+				t.Fatalf("WithinDistance() unexpected error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("WithinDistance() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := poly.WithinDistance(Location{}, -1, Kilometers); err == nil {
+		t.Errorf("WithinDistance() expected error for negative distance")
+	}
+}
+
+func TestSpatialIndex_WithinArea(t *testing.T) {
+	index, _ := NewSpatialIndex(10)
+
+	_ = index.Insert("inside", Location{Latitude: 2, Longitude: 2})
+	_ = index.Insert("outside", Location{Latitude: 8, Longitude: 8})
+	_ = index.Insert("far", Location{Latitude: 40, Longitude: 40})
+
+	triangle, _ := NewPolygon([]Location{
+		{Latitude: 0, Longitude: 0},
+		{Latitude: 0, Longitude: 10},
+		{Latitude: 10, Longitude: 0},
+	})
+
+	matches := index.WithinArea(triangle)
+	if len(matches) != 1 || matches[0].ID != "inside" {
+		t.Errorf("WithinArea(polygon) = %v, want [inside]", matches)
+	}
+
+	box, _ := NewBoundingBox(0, 0, 10, 10)
+	matches = index.WithinArea(box)
+	if len(matches) != 2 || matches[0].ID != "inside" || matches[1].ID != "outside" {
+		t.Errorf("WithinArea(box) = %v, want [inside outside]", matches)
+	}
+}
@@ -0,0 +1,293 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+// kmPerDegreeLatitude is the length of one degree of latitude on the mean Earth sphere
+const kmPerDegreeLatitude = earthRadiusKm * math.Pi / 180
+
+// DistanceUnit identifies the unit a distance or search radius is expressed in
+type DistanceUnit string
+
+const (
+	// Kilometers expresses distances in kilometers
+	Kilometers DistanceUnit = "km"
+	// Miles expresses distances in statute miles
+	Miles DistanceUnit = "mi"
+)
+
+// kmPerMile is the number of kilometers in one statute mile
+const kmPerMile = 1.609344
+
+// IsValid returns true if the unit is a supported distance unit
+func (u DistanceUnit) IsValid() bool {
+	return u == Kilometers || u == Miles
+}
+
+// ToKilometers converts a distance expressed in this unit to kilometers
+func (u DistanceUnit) ToKilometers(distance float64) float64 {
+	if u == Miles {
+		return distance * kmPerMile
+	}
+	return distance
+}
+
+// FromKilometers converts a distance in kilometers to this unit
+func (u DistanceUnit) FromKilometers(distanceKm float64) float64 {
+	if u == Miles {
+		return distanceKm / kmPerMile
+	}
+	return distanceKm
+}
+
+// SpatialMatch is a single result of a radius query against a SpatialIndex
+type SpatialMatch struct {
+	ID       string   `json:"id"`
+	Location Location `json:"location"`
+	// Distance from the query center, expressed in the unit of the query
+	Distance float64 `json:"distance"`
+}
+
+// gridCell identifies one latitude/longitude cell of a SpatialIndex
+type gridCell struct {
+	row int
+	col int
+}
+
+// SpatialIndex is an in-memory grid index of Locations keyed by an identifier
+// (a school ID, a wishlist ID, ...). Locations are bucketed into fixed-size cells
+// so a radius query only inspects the cells overlapping the search area instead
+// of computing the distance to every entry. It is safe for concurrent use.
+type SpatialIndex struct {
+	mu      sync.RWMutex
+	cellDeg float64
+	// colDeg is the longitude width of a column. It divides 360 exactly, so the
+	// last column ends at the antimeridian and wrapping stays aligned.
+	colDeg   float64
+	cols     int
+	cells    map[gridCell]map[string]Location
+	entryKey map[string]gridCell
+}
+
+// NewSpatialIndex creates an empty SpatialIndex whose grid cells are roughly
+// cellSizeKm on each side (measured along a meridian)
+func NewSpatialIndex(cellSizeKm float64) (*SpatialIndex, error) {
+	if cellSizeKm <= 0 || math.IsNaN(cellSizeKm) || math.IsInf(cellSizeKm, 0) {
+		return nil, errors.New("cell size must be a positive number of kilometers")
+	}
+
+	cellDeg := math.Min(cellSizeKm/kmPerDegreeLatitude, 180)
+	cols := int(math.Ceil(360 / cellDeg))
+
+	return &SpatialIndex{
+		cellDeg:  cellDeg,
+		colDeg:   360 / float64(cols),
+		cols:     cols,
+		cells:    make(map[gridCell]map[string]Location),
+		entryKey: make(map[string]gridCell),
+	}, nil
+}
+
+// Insert adds or replaces the Location stored under id
+func (s *SpatialIndex) Insert(id string, location Location) error {
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	if err := location.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeLocked(id)
+
+	cell := s.cellOf(location.Latitude, location.Longitude)
+	bucket, ok := s.cells[cell]
+	if !ok {
+		bucket = make(map[string]Location)
+		s.cells[cell] = bucket
+	}
+	bucket[id] = location
+	s.entryKey[id] = cell
+
+	return nil
+}
+
+// Remove deletes the Location stored under id, returning false if it was not indexed
+func (s *SpatialIndex) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeLocked(id)
+}
+
+// Len returns the number of indexed Locations
+func (s *SpatialIndex) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.entryKey)
+}
+
+// WithinRadius returns every indexed Location within radius of center, sorted by
+// ascending distance. Distances in the result are expressed in unit.
+func (s *SpatialIndex) WithinRadius(center Location, radius float64, unit DistanceUnit) ([]SpatialMatch, error) {
+	if !unit.IsValid() {
+		return nil, errors.New("distance unit must be km or mi")
+	}
+
+	if radius < 0 || math.IsNaN(radius) || math.IsInf(radius, 0) {
+		return nil, errors.New("radius must be a non-negative number")
+	}
+
+	if err := center.validate(); err != nil {
+		return nil, err
+	}
+
+	radiusKm := unit.ToKilometers(radius)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := []SpatialMatch{}
+	for _, cell := range s.candidateCells(center, radiusKm) {
+		for id, location := range s.cells[cell] {
+			distanceKm := center.DistanceTo(location)
+			if distanceKm <= radiusKm {
+				matches = append(matches, SpatialMatch{
+					ID:       id,
+					Location: location,
+					Distance: unit.FromKilometers(distanceKm),
+				})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches, nil
+}
+
+// WithinArea returns every indexed Location inside the area, sorted by ID.
+// Distance is left at zero since there is no query center.
+func (s *SpatialIndex) WithinArea(area Area) []SpatialMatch {
+	bounds := area.Bounds()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := []SpatialMatch{}
+	for _, cell := range s.cellsInBox(bounds) {
+		for id, location := range s.cells[cell] {
+			if area.Contains(location) {
+				matches = append(matches, SpatialMatch{ID: id, Location: location})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches
+}
+
+// removeLocked deletes id from the index; the caller must hold the write lock
+func (s *SpatialIndex) removeLocked(id string) bool {
+	cell, ok := s.entryKey[id]
+	if !ok {
+		return false
+	}
+
+	delete(s.entryKey, id)
+	delete(s.cells[cell], id)
+	if len(s.cells[cell]) == 0 {
+		delete(s.cells, cell)
+	}
+
+	return true
+}
+
+// cellOf returns the grid cell containing the given coordinates
+func (s *SpatialIndex) cellOf(latitude, longitude float64) gridCell {
+	return gridCell{
+		row: int(math.Floor((latitude + 90) / s.cellDeg)),
+		col: s.wrapCol(int(math.Floor((longitude + 180) / s.colDeg))),
+	}
+}
+
+// wrapCol maps a column index onto the grid, wrapping across the antimeridian
+func (s *SpatialIndex) wrapCol(col int) int {
+	col %= s.cols
+	if col < 0 {
+		col += s.cols
+	}
+	return col
+}
+
+// candidateCells returns the populated cells that may hold Locations within
+// radiusKm of center. The covered area is a conservative latitude/longitude box;
+// exact distances are checked by the caller.
+func (s *SpatialIndex) candidateCells(center Location, radiusKm float64) []gridCell {
+	latDelta := radiusKm / kmPerDegreeLatitude
+	minLat := math.Max(center.Latitude-latDelta, -90)
+	maxLat := math.Min(center.Latitude+latDelta, 90)
+
+	// Longitude degrees shrink towards the poles, so size the box using the
+	// most poleward latitude it reaches. A box touching a pole spans every meridian.
+	allCols := minLat <= -90 || maxLat >= 90
+	var lonDelta float64
+	if !allCols {
+		maxAbsLat := math.Max(math.Abs(minLat), math.Abs(maxLat))
+		lonDelta = latDelta / math.Cos(maxAbsLat*math.Pi/180)
+		allCols = lonDelta >= 180
+	}
+
+	if allCols {
+		return s.cellsInRange(minLat, maxLat, -180, 360)
+	}
+
+	return s.cellsInRange(minLat, maxLat, center.Longitude-lonDelta, 2*lonDelta)
+}
+
+// cellsInBox returns the populated cells overlapping a BoundingBox
+func (s *SpatialIndex) cellsInBox(box BoundingBox) []gridCell {
+	lng := box.lngInterval()
+	return s.cellsInRange(box.South, box.North, lng.west, lng.width)
+}
+
+// cellsInRange returns the populated cells overlapping the latitude range and the
+// longitude range starting at west and extending lngWidth degrees eastwards
+func (s *SpatialIndex) cellsInRange(minLat, maxLat, west, lngWidth float64) []gridCell {
+	minRow := s.cellOf(minLat, 0).row
+	maxRow := s.cellOf(maxLat, 0).row
+
+	firstCol := int(math.Floor((west + 180) / s.colDeg))
+	lastCol := int(math.Floor((west + lngWidth + 180) / s.colDeg))
+	if lastCol-firstCol+1 >= s.cols {
+		firstCol, lastCol = 0, s.cols-1
+	}
+
+	cells := []gridCell{}
+	for row := minRow; row <= maxRow; row++ {
+		for col := firstCol; col <= lastCol; col++ {
+			cell := gridCell{row: row, col: s.wrapCol(col)}
+			if _, ok := s.cells[cell]; ok {
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceUnit_Conversions(t *testing.T) {
+	if got := Miles.ToKilometers(10); math.Abs(got-16.09344) > 1e-9 {
+		t.Errorf("Miles.ToKilometers(10) = %v, want %v", got, 16.09344)
+	}
+	if got := Miles.FromKilometers(16.09344); math.Abs(got-10) > 1e-9 {
+		t.Errorf("Miles.FromKilometers(16.09344) = %v, want %v", got, 10)
+	}
+	if got := Kilometers.ToKilometers(10); got != 10 {
+		t.Errorf("Kilometers.ToKilometers(10) = %v, want %v", got, 10)
+	}
+	if DistanceUnit("ft").IsValid() {
+		t.Errorf("IsValid() = true for unsupported unit")
+	}
+}
+
+func TestNewSpatialIndex_InvalidCellSize(t *testing.T) {
+	for _, size := range []float64{0, -5, math.NaN(), math.Inf(1)} {
+		if _, err := NewSpatialIndex(size); err == nil {
+			t.Errorf("NewSpatialIndex(%v) expected error but got none", size)
+		}
+	}
+}
+
+func TestSpatialIndex_WithinRadius(t *testing.T) {
+	index, err := NewSpatialIndex(10)
+	if err != nil {
+		t.Fatalf("NewSpatialIndex() unexpected error = %v", err)
+	}
+
+	springfield, _ := NewLocation(39.7817, -89.6501, "Sangamon", "IL")
+	chatham, _ := NewLocation(39.6762, -89.7043, "Sangamon", "IL")
+	decatur, _ := NewLocation(39.8403, -88.9548, "Macon", "IL")
+	chicago, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	for id, loc := range map[string]Location{
+		"chatham": chatham,
+		"decatur": decatur,
+		"chicago": chicago,
+	} {
+		if err := index.Insert(id, loc); err != nil {
+			t.Fatalf("Insert(%q) unexpected error = %v", id, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		radius  float64
+		unit    DistanceUnit
+		wantIDs []string
+	}{
+		{name: "nearby only", radius: 20, unit: Kilometers, wantIDs: []string{"chatham"}},
+		{name: "sorted by distance", radius: 80, unit: Kilometers, wantIDs: []string{"chatham", "decatur"}},
+		{name: "miles", radius: 200, unit: Miles, wantIDs: []string{"chatham", "decatur", "chicago"}},
+		{name: "zero radius", radius: 0, unit: Kilometers, wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := index.WithinRadius(springfield, tt.radius, tt.unit)
+			if err != nil {
+				t.Fatalf("WithinRadius() unexpected error = %v", err)
+			}
+
+			if len(matches) != len(tt.wantIDs) {
+				t.Fatalf("WithinRadius() returned %d matches, want %d", len(matches), len(tt.wantIDs))
+			}
+
+			for i, match := range matches {
+				if match.ID != tt.wantIDs[i] {
+					t.Errorf("WithinRadius()[%d] = %v, want %v", i, match.ID, tt.wantIDs[i])
+				}
+				want := tt.unit.FromKilometers(springfield.DistanceTo(match.Location))
+				if math.Abs(match.Distance-want) > 1e-9 {
+					t.Errorf("WithinRadius()[%d] distance = %v, want %v", i, match.Distance, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpatialIndex_WithinRadius_Antimeridian(t *testing.T) {
+	index, _ := NewSpatialIndex(25)
+
+	east, _ := NewLocation(-16.5, 179.9, "", "")
+	west, _ := NewLocation(-16.5, -179.9, "", "")
+	if err := index.Insert("west", west); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+
+	matches, err := index.WithinRadius(east, 50, Kilometers)
+	if err != nil {
+		t.Fatalf("WithinRadius() unexpected error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "west" {
+		t.Errorf("WithinRadius() across antimeridian = %v, want [west]", matches)
+	}
+}
+
+func TestSpatialIndex_WithinRadius_AntimeridianUnevenColumns(t *testing.T) {
+	// 360 degrees is not a whole number of 78 km cells, so the columns must be
+	// widened to line up with the antimeridian
+	index, _ := NewSpatialIndex(78)
+
+	point, _ := NewLocation(0, -179.1, "", "")
+	center, _ := NewLocation(0, 179.9, "", "")
+	if err := index.Insert("point", point); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+
+	matches, err := index.WithinRadius(center, 115, Kilometers)
+	if err != nil {
+		t.Fatalf("WithinRadius() unexpected error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "point" {
+		t.Errorf("WithinRadius() across antimeridian = %v, want [point]", matches)
+	}
+}
+
+func TestSpatialIndex_WithinRadius_NearPole(t *testing.T) {
+	index, _ := NewSpatialIndex(50)
+
+	a, _ := NewLocation(89.9, 0, "", "")
+	b, _ := NewLocation(89.9, 180, "", "")
+	if err := index.Insert("b", b); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+
+	matches, _ := index.WithinRadius(a, 50, Kilometers)
+	if len(matches) != 1 {
+		t.Errorf("WithinRadius() near pole returned %d matches, want 1", len(matches))
+	}
+}
+
+func TestSpatialIndex_InsertReplaceAndRemove(t *testing.T) {
+	index, _ := NewSpatialIndex(10)
+
+	nyc, _ := NewLocation(40.7128, -74.0060, "New York", "NY")
+	chicago, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	_ = index.Insert("school-1", nyc)
+	_ = index.Insert("school-1", chicago)
+
+	if index.Len() != 1 {
+		t.Errorf("Len() = %v, want 1", index.Len())
+	}
+
+	matches, _ := index.WithinRadius(nyc, 10, Kilometers)
+	if len(matches) != 0 {
+		t.Errorf("WithinRadius() found replaced location")
+	}
+
+	if !index.Remove("school-1") {
+		t.Errorf("Remove() = false, want true")
+	}
+	if index.Remove("school-1") {
+		t.Errorf("Remove() of missing id = true, want false")
+	}
+	if index.Len() != 0 {
+		t.Errorf("Len() = %v, want 0", index.Len())
+	}
+}
+
+func TestSpatialIndex_Errors(t *testing.T) {
+	index, _ := NewSpatialIndex(10)
+	center, _ := NewLocation(40.7128, -74.0060, "New York", "NY")
+
+	if err := index.Insert("", center); err == nil {
+		t.Errorf("Insert() expected error for empty id")
+	}
+	if err := index.Insert("bad", Location{Latitude: 95}); err == nil {
+		t.Errorf("Insert() expected error for invalid location")
+	}
+	if _, err := index.WithinRadius(center, -1, Kilometers); err == nil {
+		t.Errorf("WithinRadius() expected error for negative radius")
+	}
+	if _, err := index.WithinRadius(center, 1, DistanceUnit("ft")); err == nil {
+		t.Errorf("WithinRadius() expected error for unsupported unit")
+	}
+}
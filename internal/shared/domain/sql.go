@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Value implements driver.Valuer, writing the location as EWKT so it can be bound
+// directly to a PostGIS geography(Point,4326) column or used in a query such as
+// ST_DWithin(location, $1::geography, $2). Empty locations are written as NULL.
+// Only the coordinates are stored; County and Region belong in their own columns.
+func (l Location) Value() (driver.Value, error) {
+	if l.IsEmpty() {
+		return nil, nil
+	}
+
+	if err := l.validate(); err != nil {
+		return nil, err
+	}
+
+	return l.EWKT(), nil
+}
+
+// Scan implements sql.Scanner, reading a PostGIS geography or geometry point as
+// hex or binary EWKB or as (E)WKT. NULL scans to empty coordinates. County and
+// Region are left untouched so they can be scanned from their own columns.
+func (l *Location) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		l.Latitude, l.Longitude = 0, 0
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Location", src)
+	}
+
+	point, err := parsePostGISPoint(data)
+	if err != nil {
+		return err
+	}
+
+	l.Latitude, l.Longitude = point.Latitude, point.Longitude
+	return nil
+}
+
+// Value implements driver.Valuer, writing the address as a JSON document for a
+// JSONB column. Empty addresses are written as NULL.
+func (a Address) Value() (driver.Value, error) {
+	if a.IsEmpty() && a.Location.IsEmpty() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Scan implements sql.Scanner, reading an address from a JSON or JSONB column.
+// NULL scans to an empty Address. The stored address is loaded as written, not
+// validated again, so addresses saved under older validation rules still load.
+func (a *Address) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*a = Address{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Address", src)
+	}
+
+	var decoded Address
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("invalid address JSON: %w", err)
+	}
+
+	*a = decoded
+	return nil
+}
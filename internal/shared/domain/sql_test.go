@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"testing"
+)
+
+// Compile-time checks that the value objects can be used as query arguments and
+// scan destinations
+var (
+	_ driver.Valuer = Location{}
+	_ sql.Scanner   = (*Location)(nil)
+	_ driver.Valuer = Address{}
+	_ sql.Scanner   = (*Address)(nil)
+)
+
+func TestLocation_Value(t *testing.T) {
+	loc, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	value, err := loc.Value()
+	if err != nil {
+		t.Fatalf("Value() unexpected error = %v", err)
+	}
+	if value != "SRID=4326;POINT(-87.6298 41.8781)" {
+		t.Errorf("Value() = %v, want EWKT point", value)
+	}
+
+	value, err = Location{}.Value()
+	if err != nil || value != nil {
+		t.Errorf("Value() of empty location = %v, %v, want nil, nil", value, err)
+	}
+
+	if _, err := (Location{Latitude: 100}).Value(); err == nil {
+		t.Errorf("Value() expected error for invalid location")
+	}
+}
+
+func TestLocation_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected Location
+		wantErr  bool
+	}{
+		{
+			name:     "hex EWKB string",
+			src:      "0101000020E610000055C1A8A44EE855C00E4FAF9465F04440",
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "binary EWKB",
+			src:      Location{Latitude: 41.8781, Longitude: -87.6298}.EWKB(),
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			// The last byte of the latitude is a space, which must not be trimmed
+			name:     "binary EWKB ending in a whitespace byte",
+			src:      Location{Latitude: math.Float64frombits(0x2000000000000000), Longitude: -87.6298}.EWKB(),
+			expected: Location{Latitude: math.Float64frombits(0x2000000000000000), Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "hex EWKB with surrounding whitespace",
+			src:      " 0101000020E610000055C1A8A44EE855C00E4FAF9465F04440\n",
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "EWKT bytes",
+			src:      []byte("SRID=4326;POINT(-87.6298 41.8781)"),
+			expected: Location{Latitude: 41.8781, Longitude: -87.6298, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "NULL",
+			src:      nil,
+			expected: Location{County: "Cook", Region: "IL"},
+		},
+		{name: "unsupported type", src: 42, wantErr: true},
+		{name: "garbage", src: "not a point", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// County and region come from their own columns and must survive the scan
+			loc := Location{County: "Cook", Region: "IL"}
+			err := loc.Scan(tt.src)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Scan() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Scan() unexpected error = %v", err)
+			}
+			if !loc.Equals(tt.expected) {
+				t.Errorf("Scan() = %v, want %v", loc, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddress_ValueScan_RoundTrip(t *testing.T) {
+	loc, _ := NewLocation(39.7817, -89.6501, "Sangamon", "IL")
+	original, _ := NewAddress("123 Main St", "Springfield", "IL", "62701", loc)
+
+	value, err := original.Value()
+	if err != nil {
+		t.Fatalf("Value() unexpected error = %v", err)
+	}
+
+	var scanned Address
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Scan() unexpected error = %v", err)
+	}
+	if !scanned.Equals(original) {
+		t.Errorf("Scan() = %v, want %v", scanned, original)
+	}
+
+	// Drivers may hand JSONB back as a string
+	var fromString Address
+	if err := fromString.Scan(string(value.([]byte))); err != nil || !fromString.Equals(original) {
+		t.Errorf("Scan(string) = %v, %v, want %v", fromString, err, original)
+	}
+}
+
+func TestAddress_ValueScan_Empty(t *testing.T) {
+	value, err := Address{}.Value()
+	if err != nil || value != nil {
+		t.Errorf("Value() of empty address = %v, %v, want nil, nil", value, err)
+	}
+
+	addr := Address{City: "Springfield"}
+	if err := addr.Scan(nil); err != nil || !addr.IsEmpty() {
+		t.Errorf("Scan(nil) = %v, %v, want empty address", addr, err)
+	}
+}
+
+func TestAddress_Scan_StoredAsWritten(t *testing.T) {
+	// An address saved before the state had to be a two-letter code still loads
+	var addr Address
+	if err := addr.Scan([]byte(`{"city":"Springfield","state":"Illinois"}`)); err != nil {
+		t.Fatalf("Scan() unexpected error = %v", err)
+	}
+	if addr.City != "Springfield" || addr.State != "Illinois" {
+		t.Errorf("Scan() = %v, want the stored address unchanged", addr)
+	}
+}
+
+func TestAddress_Scan_Invalid(t *testing.T) {
+	var addr Address
+
+	if err := addr.Scan([]byte(`{`)); err == nil {
+		t.Errorf("Scan() expected JSON error")
+	}
+	if err := addr.Scan(3.14); err == nil {
+		t.Errorf("Scan() expected error for unsupported type")
+	}
+}
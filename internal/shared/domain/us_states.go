@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StateKind classifies a USPS state code
+type StateKind string
+
+const (
+	// StateKindState is one of the 50 states
+	StateKindState StateKind = "state"
+	// StateKindDistrict is the District of Columbia
+	StateKindDistrict StateKind = "district"
+	// StateKindTerritory is a US territory or freely associated state
+	StateKindTerritory StateKind = "territory"
+	// StateKindMilitary is an APO/FPO/DPO military mail code
+	StateKindMilitary StateKind = "military"
+)
+
+// zipRange is an inclusive range of 3-digit ZIP code prefixes
+type zipRange struct {
+	from int
+	to   int
+}
+
+// USState describes a USPS state, district, territory or military code
+type USState struct {
+	Code string    `json:"code"`
+	Name string    `json:"name"`
+	Kind StateKind `json:"kind"`
+	// FIPS is the two-digit Census FIPS code; empty for military codes
+	FIPS      string `json:"fips,omitempty"`
+	zipRanges []zipRange
+}
+
+// HasZipPrefix returns true if the 3-digit ZIP prefix is assigned to the state
+func (s USState) HasZipPrefix(prefix int) bool {
+	for _, r := range s.zipRanges {
+		if prefix >= r.from && prefix <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// usStates lists every USPS code with its name, FIPS code and the 3-digit ZIP
+// prefixes assigned to it. Some prefixes are shared (e.g. 834 by Idaho and
+// Wyoming, 969 by Guam and the Pacific freely associated states).
+var usStates = []USState{
+	{"AL", "Alabama", StateKindState, "01", []zipRange{{350, 369}}},
+	{"AK", "Alaska", StateKindState, "02", []zipRange{{995, 999}}},
+	{"AZ", "Arizona", StateKindState, "04", []zipRange{{850, 865}}},
+	{"AR", "Arkansas", StateKindState, "05", []zipRange{{716, 729}}},
+	{"CA", "California", StateKindState, "06", []zipRange{{900, 961}}},
+	{"CO", "Colorado", StateKindState, "08", []zipRange{{800, 816}}},
+	{"CT", "Connecticut", StateKindState, "09", []zipRange{{60, 69}}},
+	{"DE", "Delaware", StateKindState, "10", []zipRange{{197, 199}}},
+	{"DC", "District of Columbia", StateKindDistrict, "11", []zipRange{{200, 200}, {202, 205}, {569, 569}}},
+	{"FL", "Florida", StateKindState, "12", []zipRange{{320, 339}, {341, 349}}},
+	{"GA", "Georgia", StateKindState, "13", []zipRange{{300, 319}, {398, 399}}},
+	{"HI", "Hawaii", StateKindState, "15", []zipRange{{967, 968}}},
+	{"ID", "Idaho", StateKindState, "16", []zipRange{{832, 838}}},
+	{"IL", "Illinois", StateKindState, "17", []zipRange{{600, 629}}},
+	{"IN", "Indiana", StateKindState, "18", []zipRange{{460, 479}}},
+	{"IA", "Iowa", StateKindState, "19", []zipRange{{500, 528}}},
+	{"KS", "Kansas", StateKindState, "20", []zipRange{{660, 679}}},
+	{"KY", "Kentucky", StateKindState, "21", []zipRange{{400, 427}}},
+	{"LA", "Louisiana", StateKindState, "22", []zipRange{{700, 714}}},
+	{"ME", "Maine", StateKindState, "23", []zipRange{{39, 49}}},
+	{"MD", "Maryland", StateKindState, "24", []zipRange{{206, 219}}},
+	{"MA", "Massachusetts", StateKindState, "25", []zipRange{{10, 27}, {55, 55}}},
+	{"MI", "Michigan", StateKindState, "26", []zipRange{{480, 499}}},
+	{"MN", "Minnesota", StateKindState, "27", []zipRange{{550, 567}}},
+	{"MS", "Mississippi", StateKindState, "28", []zipRange{{386, 397}}},
+	{"MO", "Missouri", StateKindState, "29", []zipRange{{630, 658}}},
+	{"MT", "Montana", StateKindState, "30", []zipRange{{590, 599}}},
+	{"NE", "Nebraska", StateKindState, "31", []zipRange{{680, 693}}},
+	{"NV", "Nevada", StateKindState, "32", []zipRange{{889, 898}}},
+	{"NH", "New Hampshire", StateKindState, "33", []zipRange{{30, 38}}},
+	{"NJ", "New Jersey", StateKindState, "34", []zipRange{{70, 89}}},
+	{"NM", "New Mexico", StateKindState, "35", []zipRange{{870, 884}}},
+	{"NY", "New York", StateKindState, "36", []zipRange{{5, 5}, {63, 63}, {100, 149}}},
+	{"NC", "North Carolina", StateKindState, "37", []zipRange{{270, 289}}},
+	{"ND", "North Dakota", StateKindState, "38", []zipRange{{580, 588}}},
+	{"OH", "Ohio", StateKindState, "39", []zipRange{{430, 459}}},
+	{"OK", "Oklahoma", StateKindState, "40", []zipRange{{730, 732}, {734, 749}}},
+	{"OR", "Oregon", StateKindState, "41", []zipRange{{970, 979}}},
+	{"PA", "Pennsylvania", StateKindState, "42", []zipRange{{150, 196}}},
+	{"RI", "Rhode Island", StateKindState, "44", []zipRange{{28, 29}}},
+	{"SC", "South Carolina", StateKindState, "45", []zipRange{{290, 299}}},
+	{"SD", "South Dakota", StateKindState, "46", []zipRange{{570, 577}}},
+	{"TN", "Tennessee", StateKindState, "47", []zipRange{{370, 385}}},
+	{"TX", "Texas", StateKindState, "48", []zipRange{{733, 733}, {750, 799}, {885, 885}}},
+	{"UT", "Utah", StateKindState, "49", []zipRange{{840, 847}}},
+	{"VT", "Vermont", StateKindState, "50", []zipRange{{50, 54}, {56, 59}}},
+	{"VA", "Virginia", StateKindState, "51", []zipRange{{201, 201}, {220, 246}}},
+	{"WA", "Washington", StateKindState, "53", []zipRange{{980, 994}}},
+	{"WV", "West Virginia", StateKindState, "54", []zipRange{{247, 268}}},
+	{"WI", "Wisconsin", StateKindState, "55", []zipRange{{530, 549}}},
+	{"WY", "Wyoming", StateKindState, "56", []zipRange{{820, 831}, {834, 834}}},
+	{"AS", "American Samoa", StateKindTerritory, "60", []zipRange{{967, 967}}},
+	{"FM", "Federated States of Micronesia", StateKindTerritory, "64", []zipRange{{969, 969}}},
+	{"GU", "Guam", StateKindTerritory, "66", []zipRange{{969, 969}}},
+	{"MH", "Marshall Islands", StateKindTerritory, "68", []zipRange{{969, 969}}},
+	{"MP", "Northern Mariana Islands", StateKindTerritory, "69", []zipRange{{969, 969}}},
+	{"PW", "Palau", StateKindTerritory, "70", []zipRange{{969, 969}}},
+	{"PR", "Puerto Rico", StateKindTerritory, "72", []zipRange{{6, 7}, {9, 9}}},
+	{"VI", "U.S. Virgin Islands", StateKindTerritory, "78", []zipRange{{8, 8}}},
+	{"AA", "Armed Forces Americas", StateKindMilitary, "", []zipRange{{340, 340}}},
+	{"AE", "Armed Forces Europe", StateKindMilitary, "", []zipRange{{90, 98}}},
+	{"AP", "Armed Forces Pacific", StateKindMilitary, "", []zipRange{{962, 966}}},
+}
+
+// stateLookup indexes usStates by code, upper-cased name and FIPS code
+var stateLookup = func() map[string]USState {
+	lookup := make(map[string]USState, 3*len(usStates))
+	for _, state := range usStates {
+		lookup[state.Code] = state
+		lookup[strings.ToUpper(state.Name)] = state
+		if state.FIPS != "" {
+			lookup["FIPS:"+state.FIPS] = state
+		}
+	}
+
+	// Common alternate spellings
+	lookup["VIRGIN ISLANDS"] = lookup["VI"]
+	lookup["US VIRGIN ISLANDS"] = lookup["VI"]
+	lookup["WASHINGTON DC"] = lookup["DC"]
+	lookup["WASHINGTON D C"] = lookup["DC"]
+
+	return lookup
+}()
+
+// LookupState returns the state for a USPS code ("TX") or full name ("Texas"),
+// ignoring case, surrounding whitespace and periods
+func LookupState(codeOrName string) (USState, bool) {
+	key := strings.ToUpper(strings.Join(strings.Fields(strings.ReplaceAll(codeOrName, ".", " ")), " "))
+	if strings.HasPrefix(key, "FIPS:") {
+		return USState{}, false
+	}
+
+	state, ok := stateLookup[key]
+	return state, ok
+}
+
+// StateCode converts a USPS code or full state name to its 2-letter USPS code,
+// e.g. "Texas" to "TX"
+func StateCode(codeOrName string) (string, error) {
+	state, ok := LookupState(codeOrName)
+	if !ok {
+		return "", fmt.Errorf("unknown US state or territory %q", strings.TrimSpace(codeOrName))
+	}
+
+	return state.Code, nil
+}
+
+// StateCodeFromFIPS converts a 2-digit Census FIPS state code to its USPS code
+func StateCodeFromFIPS(fips string) (string, error) {
+	fips = strings.TrimSpace(fips)
+	if len(fips) == 1 {
+		fips = "0" + fips
+	}
+
+	state, ok := stateLookup["FIPS:"+fips]
+	if !ok {
+		return "", fmt.Errorf("unknown FIPS state code %q", fips)
+	}
+
+	return state.Code, nil
+}
+
+// IsValidStateCode returns true if code is an upper-case USPS state, DC,
+// territory or military code
+func IsValidStateCode(code string) bool {
+	state, ok := stateLookup[code]
+	return ok && state.Code == code
+}
+
+// StateCodes returns every USPS code, sorted alphabetically
+func StateCodes() []string {
+	codes := make([]string, 0, len(usStates))
+	for _, state := range usStates {
+		codes = append(codes, state.Code)
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// ValidateZipForState checks that a 5-digit or ZIP+4 code is assigned to the state
+func ValidateZipForState(zipCode, stateCode string) error {
+	zip, err := zip5(zipCode)
+	if err != nil {
+		return err
+	}
+
+	state, ok := stateLookup[stateCode]
+	if !ok || state.Code != stateCode {
+		return errors.New("state must be a valid US state or territory code")
+	}
+
+	prefix := int(zip[0]-'0')*100 + int(zip[1]-'0')*10 + int(zip[2]-'0')
+	if !state.HasZipPrefix(prefix) {
+		return fmt.Errorf("zip code %s is not in %s", zip, state.Code)
+	}
+
+	return nil
+}
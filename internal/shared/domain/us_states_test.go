@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestStateCode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "Texas", expected: "TX"},
+		{input: "TX", expected: "TX"},
+		{input: "tx", expected: "TX"},
+		{input: "  new   york ", expected: "NY"},
+		{input: "District of Columbia", expected: "DC"},
+		{input: "Washington D.C.", expected: "DC"},
+		{input: "Puerto Rico", expected: "PR"},
+		{input: "Armed Forces Europe", expected: "AE"},
+		{input: "Texass", wantErr: true},
+		{input: "ZZ", wantErr: true},
+		{input: "FIPS:48", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			code, err := StateCode(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("StateCode(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("StateCode(%q) unexpected error = %v", tt.input, err)
+			}
+			if code != tt.expected {
+				t.Errorf("StateCode(%q) = %v, want %v", tt.input, code, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStateCodeFromFIPS(t *testing.T) {
+	for fips, expected := range map[string]string{"17": "IL", "6": "CA", "72": "PR", "11": "DC"} {
+		code, err := StateCodeFromFIPS(fips)
+		if err != nil || code != expected {
+			t.Errorf("StateCodeFromFIPS(%q) = %v, %v, want %v", fips, code, err, expected)
+		}
+	}
+
+	if _, err := StateCodeFromFIPS("03"); err == nil {
+		t.Errorf("StateCodeFromFIPS() expected error for unassigned code")
+	}
+}
+
+func TestIsValidStateCode(t *testing.T) {
+	for _, code := range []string{"IL", "DC", "GU", "AA", "AE", "AP"} {
+		if !IsValidStateCode(code) {
+			t.Errorf("IsValidStateCode(%q) = false, want true", code)
+		}
+	}
+
+	for _, code := range []string{"ZZ", "12", "il", "Illinois", ""} {
+		if IsValidStateCode(code) {
+			t.Errorf("IsValidStateCode(%q) = true, want false", code)
+		}
+	}
+
+	if codes := StateCodes(); len(codes) != 62 || codes[0] != "AA" {
+		t.Errorf("StateCodes() = %d codes starting with %v, want 62 starting with AA", len(codes), codes[0])
+	}
+}
+
+func TestValidateZipForState(t *testing.T) {
+	tests := []struct {
+		zipCode string
+		state   string
+		wantErr bool
+	}{
+		{zipCode: "62701", state: "IL"},
+		{zipCode: "60601-1234", state: "IL"},
+		{zipCode: "02134", state: "MA"},
+		{zipCode: "05501", state: "MA"},
+		{zipCode: "05501", state: "VT", wantErr: true},
+		{zipCode: "83414", state: "WY"},
+		{zipCode: "83414", state: "ID"},
+		{zipCode: "73301", state: "TX"},
+		{zipCode: "73301", state: "OK", wantErr: true},
+		{zipCode: "34001", state: "AA"},
+		{zipCode: "34001", state: "FL", wantErr: true},
+		{zipCode: "62701", state: "TX", wantErr: true},
+		{zipCode: "627", state: "IL", wantErr: true},
+		{zipCode: "62701", state: "ZZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipCode+"/"+tt.state, func(t *testing.T) {
+			err := ValidateZipForState(tt.zipCode, tt.state)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateZipForState(%q, %q) error = %v, wantErr %v", tt.zipCode, tt.state, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAddress_UppercasesState(t *testing.T) {
+	addr, err := NewAddress("123 Main St", "Springfield", " il ", "62701", Location{})
+	if err != nil {
+		t.Fatalf("NewAddress() unexpected error = %v", err)
+	}
+	if addr.State != "IL" {
+		t.Errorf("NewAddress() state = %v, want IL", addr.State)
+	}
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SRIDWGS84 is the spatial reference ID of WGS 84 longitude/latitude, the only
+// reference system Locations are stored in
+const SRIDWGS84 = 4326
+
+// EWKB geometry type flags used by PostGIS
+const (
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+	ewkbSRIDFlag = 0x20000000
+	wkbPointType = 1
+)
+
+// EWKT returns the location as PostGIS extended well-known text, e.g.
+// "SRID=4326;POINT(-87.6298 41.8781)"
+func (l Location) EWKT() string {
+	return fmt.Sprintf("SRID=%d;POINT(%s %s)", SRIDWGS84,
+		strconv.FormatFloat(l.Longitude, 'f', -1, 64),
+		strconv.FormatFloat(l.Latitude, 'f', -1, 64))
+}
+
+// EWKB returns the location as little-endian PostGIS extended well-known binary
+// with the WGS 84 SRID embedded
+func (l Location) EWKB() []byte {
+	buf := make([]byte, 25)
+	buf[0] = 1 // little endian
+	binary.LittleEndian.PutUint32(buf[1:5], wkbPointType|ewkbSRIDFlag)
+	binary.LittleEndian.PutUint32(buf[5:9], SRIDWGS84)
+	binary.LittleEndian.PutUint64(buf[9:17], math.Float64bits(l.Longitude))
+	binary.LittleEndian.PutUint64(buf[17:25], math.Float64bits(l.Latitude))
+
+	return buf
+}
+
+// LocationFromEWKB decodes a WKB or PostGIS EWKB Point into a validated Location.
+// An embedded SRID must be WGS 84; Z and M coordinates are ignored. An empty
+// point decodes to an empty Location.
+func LocationFromEWKB(data []byte) (Location, error) {
+	if len(data) < 5 {
+		return Location{}, errors.New("WKB point is too short")
+	}
+
+	var order binary.ByteOrder
+	switch data[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return Location{}, fmt.Errorf("invalid WKB byte order %d", data[0])
+	}
+
+	geometryType := order.Uint32(data[1:5])
+	offset := 5
+
+	hasZ := geometryType&ewkbZFlag != 0
+	hasM := geometryType&ewkbMFlag != 0
+	hasSRID := geometryType&ewkbSRIDFlag != 0
+	baseType := geometryType &^ (ewkbZFlag | ewkbMFlag | ewkbSRIDFlag)
+
+	// ISO WKB encodes dimensions as thousands: 1001 is Point Z, 2001 Point M, 3001 Point ZM
+	switch baseType / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	baseType %= 1000
+
+	if baseType != wkbPointType {
+		return Location{}, fmt.Errorf("WKB geometry must be a Point, got type %d", baseType)
+	}
+
+	if hasSRID {
+		if len(data) < offset+4 {
+			return Location{}, errors.New("WKB point is too short")
+		}
+		if srid := order.Uint32(data[offset : offset+4]); srid != SRIDWGS84 {
+			return Location{}, fmt.Errorf("WKB point must use SRID %d, got %d", SRIDWGS84, srid)
+		}
+		offset += 4
+	}
+
+	dimensions := 2
+	if hasZ {
+		dimensions++
+	}
+	if hasM {
+		dimensions++
+	}
+
+	if len(data) != offset+8*dimensions {
+		return Location{}, errors.New("WKB point has an unexpected length")
+	}
+
+	longitude := math.Float64frombits(order.Uint64(data[offset : offset+8]))
+	latitude := math.Float64frombits(order.Uint64(data[offset+8 : offset+16]))
+
+	if math.IsNaN(longitude) && math.IsNaN(latitude) {
+		return Location{}, nil
+	}
+	if math.IsNaN(longitude) || math.IsNaN(latitude) {
+		return Location{}, errors.New("WKB point coordinates must be numbers")
+	}
+
+	return NewLocation(latitude, longitude, "", "")
+}
+
+// LocationFromEWKT decodes WKT or PostGIS EWKT such as "SRID=4326;POINT(lng lat)"
+// into a validated Location. Z and M coordinates are ignored and "POINT EMPTY"
+// decodes to an empty Location.
+func LocationFromEWKT(text string) (Location, error) {
+	text = strings.TrimSpace(text)
+
+	if strings.HasPrefix(strings.ToUpper(text), "SRID=") {
+		sridText, rest, ok := strings.Cut(text[len("SRID="):], ";")
+		if !ok {
+			return Location{}, errors.New("EWKT SRID must be followed by ';'")
+		}
+		srid, err := strconv.Atoi(strings.TrimSpace(sridText))
+		if err != nil {
+			return Location{}, fmt.Errorf("invalid EWKT SRID %q", sridText)
+		}
+		if srid != SRIDWGS84 {
+			return Location{}, fmt.Errorf("EWKT point must use SRID %d, got %d", SRIDWGS84, srid)
+		}
+		text = strings.TrimSpace(rest)
+	}
+
+	upper := strings.ToUpper(text)
+	if !strings.HasPrefix(upper, "POINT") {
+		return Location{}, errors.New("WKT geometry must be a POINT")
+	}
+
+	body := strings.TrimSpace(upper[len("POINT"):])
+	for _, dims := range []string{"ZM", "Z", "M"} {
+		if strings.HasPrefix(body, dims) {
+			body = strings.TrimSpace(body[len(dims):])
+			break
+		}
+	}
+
+	if body == "EMPTY" {
+		return Location{}, nil
+	}
+
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return Location{}, errors.New("WKT POINT coordinates must be in parentheses")
+	}
+
+	fields := strings.Fields(body[1 : len(body)-1])
+	if len(fields) < 2 || len(fields) > 4 {
+		return Location{}, errors.New("WKT POINT must have 2 to 4 coordinates")
+	}
+
+	longitude, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid WKT longitude %q", fields[0])
+	}
+	latitude, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid WKT latitude %q", fields[1])
+	}
+
+	return NewLocation(latitude, longitude, "", "")
+}
+
+// parsePostGISPoint decodes any representation PostGIS may return for a point:
+// raw EWKB, hex-encoded EWKB (the default text output) or (E)WKT. Surrounding
+// whitespace is trimmed from text only, since coordinate bytes of raw EWKB may
+// happen to be whitespace characters.
+func parsePostGISPoint(data []byte) (Location, error) {
+	// Raw binary starts with a byte order marker, text never does
+	if len(data) > 0 && (data[0] == 0 || data[0] == 1) {
+		return LocationFromEWKB(data)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Location{}, nil
+	}
+
+	if isHex(data) {
+		raw := make([]byte, hex.DecodedLen(len(data)))
+		if _, err := hex.Decode(raw, data); err != nil {
+			return Location{}, fmt.Errorf("invalid hex EWKB: %w", err)
+		}
+		return LocationFromEWKB(raw)
+	}
+
+	return LocationFromEWKT(string(data))
+}
+
+// isHex returns true if data is a non-empty, even-length hexadecimal string
+func isHex(data []byte) bool {
+	if len(data)%2 != 0 {
+		return false
+	}
+
+	for _, c := range data {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestLocation_EWKT(t *testing.T) {
+	loc, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	if got := loc.EWKT(); got != "SRID=4326;POINT(-87.6298 41.8781)" {
+		t.Errorf("EWKT() = %v, want %v", got, "SRID=4326;POINT(-87.6298 41.8781)")
+	}
+}
+
+func TestLocation_EWKB_RoundTrip(t *testing.T) {
+	loc, _ := NewLocation(41.8781, -87.6298, "Cook", "IL")
+
+	encoded := loc.EWKB()
+	// Little-endian point type with the SRID flag, SRID 4326, then longitude and latitude
+	expected := "0101000020E610000055C1A8A44EE855C00E4FAF9465F04440"
+	if got := hex.EncodeToString(encoded); got != strings.ToLower(expected) {
+		t.Errorf("EWKB() = %v, want %v", got, strings.ToLower(expected))
+	}
+
+	decoded, err := LocationFromEWKB(encoded)
+	if err != nil {
+		t.Fatalf("LocationFromEWKB() unexpected error = %v", err)
+	}
+	if decoded.Latitude != loc.Latitude || decoded.Longitude != loc.Longitude {
+		t.Errorf("LocationFromEWKB() = %v, want %v", decoded, loc)
+	}
+}
+
+func TestLocationFromEWKB(t *testing.T) {
+	tests := []struct {
+		name     string
+		hex      string
+		expected Location
+		wantErr  bool
+	}{
+		{
+			name:     "plain WKB big endian",
+			hex:      "00000000013FF00000000000004000000000000000",
+			expected: Location{Latitude: 2, Longitude: 1},
+		},
+		{
+			name:     "ISO WKB point Z",
+			hex:      "01E9030000000000000000F03F00000000000000400000000000000840",
+			expected: Location{Latitude: 2, Longitude: 1},
+		},
+		{
+			name:     "EWKB point Z with SRID",
+			hex:      "01010000A0E6100000000000000000F03F00000000000000400000000000000840",
+			expected: Location{Latitude: 2, Longitude: 1},
+		},
+		{
+			name:     "empty point",
+			hex:      "0101000000000000000000F87F000000000000F87F",
+			expected: Location{},
+		},
+		{name: "wrong SRID", hex: "0101000020110F0000000000000000F03F0000000000000040", wantErr: true},
+		{name: "not a point", hex: "010200000000000000", wantErr: true},
+		{name: "truncated", hex: "0101000000000000000000F03F", wantErr: true},
+		{name: "bad byte order", hex: "0201000000000000000000F03F0000000000000040", wantErr: true},
+		{name: "latitude out of range", hex: "010100000000000000000000000000000000005940", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.hex)
+			loc, err := LocationFromEWKB(data)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LocationFromEWKB() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LocationFromEWKB() unexpected error = %v", err)
+			}
+			if !loc.Equals(tt.expected) {
+				t.Errorf("LocationFromEWKB() = %v, want %v", loc, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocationFromEWKT(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Location
+		wantErr  bool
+	}{
+		{name: "EWKT", input: "SRID=4326;POINT(-87.6298 41.8781)", expected: Location{Latitude: 41.8781, Longitude: -87.6298}},
+		{name: "WKT", input: "POINT(-87.6298 41.8781)", expected: Location{Latitude: 41.8781, Longitude: -87.6298}},
+		{name: "lower case with spaces", input: " point ( 1  2 ) ", expected: Location{Latitude: 2, Longitude: 1}},
+		{name: "point Z", input: "POINT Z (1 2 3)", expected: Location{Latitude: 2, Longitude: 1}},
+		{name: "empty", input: "POINT EMPTY", expected: Location{}},
+		{name: "wrong SRID", input: "SRID=3857;POINT(1 2)", wantErr: true},
+		{name: "missing separator", input: "SRID=4326 POINT(1 2)", wantErr: true},
+		{name: "not a point", input: "LINESTRING(0 0, 1 1)", wantErr: true},
+		{name: "missing parentheses", input: "POINT 1 2", wantErr: true},
+		{name: "one coordinate", input: "POINT(1)", wantErr: true},
+		{name: "not a number", input: "POINT(a 2)", wantErr: true},
+		{name: "out of range", input: "POINT(200 2)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LocationFromEWKT(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LocationFromEWKT() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LocationFromEWKT() unexpected error = %v", err)
+			}
+			if !loc.Equals(tt.expected) {
+				t.Errorf("LocationFromEWKT() = %v, want %v", loc, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrZipCodeNotFound is returned when a geocoder has no centroid for a ZIP code
+var ErrZipCodeNotFound = errors.New("zip code not found")
+
+// Geocoder resolves an Address to a Location
+type Geocoder interface {
+	Geocode(address Address) (Location, error)
+}
+
+// zipCodeRegex matches 5-digit and ZIP+4 codes
+var zipCodeRegex = regexp.MustCompile(`^(\d{5})(-\d{4})?$`)
+
+// zipColumnAliases maps accepted CSV header names to the columns the geocoder reads
+var zipColumnAliases = map[string][]string{
+	"zip":       {"zip", "zipcode", "zip_code", "zcta", "zcta5", "geoid"},
+	"latitude":  {"latitude", "lat", "intptlat"},
+	"longitude": {"longitude", "lng", "lon", "long", "intptlong"},
+	"county":    {"county", "county_name"},
+	"region":    {"region", "state", "state_code", "stusps"},
+}
+
+// ZipGeocoder is an offline Geocoder that resolves ZIP codes to the centroid of
+// the ZIP code area. It is safe for concurrent use.
+type ZipGeocoder struct {
+	mu        sync.RWMutex
+	centroids map[string]Location
+}
+
+// NewZipGeocoder creates an empty ZipGeocoder
+func NewZipGeocoder() *ZipGeocoder {
+	return &ZipGeocoder{centroids: make(map[string]Location)}
+}
+
+// LoadZipGeocoder creates a ZipGeocoder from a CSV of ZIP centroids
+func LoadZipGeocoder(r io.Reader) (*ZipGeocoder, error) {
+	g := NewZipGeocoder()
+	if _, err := g.Import(r); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Import adds or replaces centroids from a CSV file and returns the number of rows
+// imported. The header row must name a ZIP, latitude and longitude column (common
+// names such as "zip", "lat" and "lng" or the Census ZCTA gazetteer names are
+// accepted); "county" and "region"/"state" columns are optional. The import is
+// all-or-nothing: on error no centroids are changed.
+func (g *ZipGeocoder) Import(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("reading zip centroid header: %w", err)
+	}
+
+	columns := zipColumns(header)
+	for _, required := range []string{"zip", "latitude", "longitude"} {
+		if _, ok := columns[required]; !ok {
+			return 0, fmt.Errorf("zip centroid file has no %s column", required)
+		}
+	}
+
+	imported := make(map[string]Location)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading zip centroids: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		zip, loc, err := parseZipCentroid(record, columns)
+		if err != nil {
+			return 0, fmt.Errorf("zip centroid line %d: %w", line, err)
+		}
+		imported[zip] = loc
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for zip, loc := range imported {
+		g.centroids[zip] = loc
+	}
+
+	return len(imported), nil
+}
+
+// Add registers the centroid for a 5-digit ZIP code
+func (g *ZipGeocoder) Add(zipCode string, location Location) error {
+	zip, err := zip5(zipCode)
+	if err != nil {
+		return err
+	}
+
+	if err := location.validate(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.centroids[zip] = location
+	return nil
+}
+
+// Len returns the number of known ZIP codes
+func (g *ZipGeocoder) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.centroids)
+}
+
+// LookupZip returns the centroid of a 5-digit or ZIP+4 code, with county and
+// region filled in when the source data has them
+func (g *ZipGeocoder) LookupZip(zipCode string) (Location, error) {
+	zip, err := zip5(zipCode)
+	if err != nil {
+		return Location{}, err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	loc, ok := g.centroids[zip]
+	if !ok {
+		return Location{}, fmt.Errorf("%w: %s", ErrZipCodeNotFound, zip)
+	}
+
+	return loc, nil
+}
+
+// Geocode implements Geocoder using the address ZIP code
+func (g *ZipGeocoder) Geocode(address Address) (Location, error) {
+	if address.ZipCode == "" {
+		return Location{}, errors.New("zip code is required for geocoding")
+	}
+
+	return g.LookupZip(address.ZipCode)
+}
+
+// GeocodeIfMissing returns the address with its Location resolved by the geocoder
+// when it has no coordinates. Addresses that already have coordinates are
+// returned unchanged.
+func GeocodeIfMissing(geocoder Geocoder, address Address) (Address, error) {
+	if address.Location.Latitude != 0 || address.Location.Longitude != 0 {
+		return address, nil
+	}
+
+	loc, err := geocoder.Geocode(address)
+	if err != nil {
+		return Address{}, err
+	}
+
+	return address.WithLocation(loc)
+}
+
+// zip5 extracts the 5-digit ZIP from a 5-digit or ZIP+4 code
+func zip5(zipCode string) (string, error) {
+	match := zipCodeRegex.FindStringSubmatch(strings.TrimSpace(zipCode))
+	if match == nil {
+		return "", errors.New("zip code must be in format 12345 or 12345-6789")
+	}
+
+	return match[1], nil
+}
+
+// zipColumns maps the geocoder columns to their index in the header row
+func zipColumns(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		for column, aliases := range zipColumnAliases {
+			if _, ok := columns[column]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[column] = i
+				}
+			}
+		}
+	}
+
+	return columns
+}
+
+// parseZipCentroid converts a CSV record into a ZIP code and its centroid
+func parseZipCentroid(record []string, columns map[string]int) (string, Location, error) {
+	field := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	zip := field("zip")
+	// Spreadsheets often drop leading zeros from New England ZIP codes
+	if len(zip) > 0 && len(zip) < 5 {
+		zip = strings.Repeat("0", 5-len(zip)) + zip
+	}
+	zip, err := zip5(zip)
+	if err != nil {
+		return "", Location{}, err
+	}
+
+	latitude, err := strconv.ParseFloat(field("latitude"), 64)
+	if err != nil {
+		return "", Location{}, fmt.Errorf("invalid latitude %q", field("latitude"))
+	}
+	longitude, err := strconv.ParseFloat(field("longitude"), 64)
+	if err != nil {
+		return "", Location{}, fmt.Errorf("invalid longitude %q", field("longitude"))
+	}
+
+	loc, err := NewLocation(latitude, longitude, field("county"), field("region"))
+	if err != nil {
+		return "", Location{}, err
+	}
+
+	return zip, loc, nil
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const zipCentroidsCSV = `zip,lat,lng,county,state
+62701,39.8017,-89.6436,Sangamon,IL
+60601,41.8858,-87.6181,Cook,IL
+2134,42.3576,-71.1271,Suffolk,MA
+`
+
+func TestLoadZipGeocoder(t *testing.T) {
+	g, err := LoadZipGeocoder(strings.NewReader(zipCentroidsCSV))
+	if err != nil {
+		t.Fatalf("LoadZipGeocoder() unexpected error = %v", err)
+	}
+
+	if g.Len() != 3 {
+		t.Errorf("Len() = %v, want 3", g.Len())
+	}
+
+	tests := []struct {
+		name     string
+		zipCode  string
+		expected Location
+		wantErr  error
+	}{
+		{
+			name:     "5-digit",
+			zipCode:  "62701",
+			expected: Location{Latitude: 39.8017, Longitude: -89.6436, County: "Sangamon", Region: "IL"},
+		},
+		{
+			name:     "ZIP+4",
+			zipCode:  "60601-1234",
+			expected: Location{Latitude: 41.8858, Longitude: -87.6181, County: "Cook", Region: "IL"},
+		},
+		{
+			name:     "leading zero restored",
+			zipCode:  "02134",
+			expected: Location{Latitude: 42.3576, Longitude: -71.1271, County: "Suffolk", Region: "MA"},
+		},
+		{
+			name:    "unknown",
+			zipCode: "99999",
+			wantErr: ErrZipCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := g.LookupZip(tt.zipCode)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("LookupZip() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LookupZip() unexpected error = %v", err)
+			}
+			if !loc.Equals(tt.expected) {
+				t.Errorf("LookupZip() = %v, want %v", loc, tt.expected)
+			}
+		})
+	}
+
+	if _, err := g.LookupZip("1234"); err == nil {
+		t.Errorf("LookupZip() expected error for malformed zip code")
+	}
+}
+
+func TestLoadZipGeocoder_GazetteerHeader(t *testing.T) {
+	gazetteer := "GEOID,ALAND,AWATER,INTPTLAT,INTPTLONG\n00601,166847909,799292,18.180555,-66.749961\n"
+
+	g, err := LoadZipGeocoder(strings.NewReader(gazetteer))
+	if err != nil {
+		t.Fatalf("LoadZipGeocoder() unexpected error = %v", err)
+	}
+
+	loc, err := g.LookupZip("00601")
+	if err != nil {
+		t.Fatalf("LookupZip() unexpected error = %v", err)
+	}
+	if loc.Latitude != 18.180555 || loc.Longitude != -66.749961 {
+		t.Errorf("LookupZip() = %v, want 18.180555, -66.749961", loc)
+	}
+}
+
+func TestZipGeocoder_Import_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty file", input: ""},
+		{name: "missing longitude column", input: "zip,lat\n62701,39.8\n"},
+		{name: "bad latitude", input: "zip,lat,lng\n62701,north,-89.6\n"},
+		{name: "out of range", input: "zip,lat,lng\n62701,95,-89.6\n"},
+		{name: "bad zip", input: "zip,lat,lng\n6270A,39.8,-89.6\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewZipGeocoder()
+			if _, err := g.Import(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Import() expected error but got none")
+			}
+		})
+	}
+
+	// A failed import must not leave partial data behind
+	g := NewZipGeocoder()
+	_, err := g.Import(strings.NewReader("zip,lat,lng\n62701,39.8,-89.6\n60601,bad,-87.6\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Import() error = %v, want error on line 3", err)
+	}
+	if g.Len() != 0 {
+		t.Errorf("Import() kept %d rows from a failed import", g.Len())
+	}
+}
+
+func TestGeocodeIfMissing(t *testing.T) {
+	g, _ := LoadZipGeocoder(strings.NewReader(zipCentroidsCSV))
+
+	addr, _ := NewAddress("1 Main St", "Springfield", "IL", "62701", Location{})
+	geocoded, err := GeocodeIfMissing(g, addr)
+	if err != nil {
+		t.Fatalf("GeocodeIfMissing() unexpected error = %v", err)
+	}
+	if geocoded.Location.County != "Sangamon" || geocoded.Location.Latitude != 39.8017 {
+		t.Errorf("GeocodeIfMissing() location = %v, want ZIP centroid", geocoded.Location)
+	}
+
+	// Existing coordinates are kept
+	located, _ := addr.WithLocation(Location{Latitude: 39.78, Longitude: -89.65})
+	unchanged, err := GeocodeIfMissing(g, located)
+	if err != nil || !unchanged.Equals(located) {
+		t.Errorf("GeocodeIfMissing() = %v, %v, want address unchanged", unchanged, err)
+	}
+
+	noZip, _ := NewAddress("1 Main St", "Springfield", "IL", "", Location{})
+	if _, err := GeocodeIfMissing(g, noZip); err == nil {
+		t.Errorf("GeocodeIfMissing() expected error for address without zip code")
+	}
+}
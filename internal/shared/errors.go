@@ -0,0 +1,123 @@
+// Package shared holds utilities, constants and errors used across the internal
+// bounded contexts.
+package shared
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrValidation matches every ValidationError with errors.Is
+	ErrValidation = errors.New("validation failed")
+	// ErrNotFound is wrapped by repositories when a requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is wrapped when a write would violate an invariant because of a
+	// concurrent change, e.g. an optimistic lock failure
+	ErrConflict = errors.New("conflict")
+	// ErrForbidden is wrapped when the caller may not act on an entity
+	ErrForbidden = errors.New("forbidden")
+)
+
+// FieldError describes a single invalid field
+type FieldError struct {
+	// Field is the JSON name of the field, with a dotted path for nested values
+	// (e.g. "location.latitude")
+	Field string `json:"field"`
+	// Code is a stable, machine-readable identifier such as "address.zip_code.format"
+	// that clients can use to localize the message
+	Code string `json:"code"`
+	// Message is a human-readable English description of the problem
+	Message string `json:"message"`
+}
+
+// Error returns the human-readable message
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationError lists every invalid field of a value object or request, so all
+// problems can be reported at once. Use errors.As to retrieve it from a wrapped
+// error and errors.Is(err, ErrValidation) to detect it.
+type ValidationError struct {
+	Fields []FieldError `json:"errors"`
+}
+
+// NewValidationError creates a ValidationError holding the given field errors
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: append([]FieldError{}, fields...)}
+}
+
+// Add records an invalid field
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Merge records the field errors of a nested ValidationError, prefixing their
+// field paths with prefix (e.g. "location"). Any other non-nil error is recorded
+// as-is under prefix with the given fallback code.
+func (e *ValidationError) Merge(prefix, fallbackCode string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested *ValidationError
+	if !errors.As(err, &nested) {
+		e.Add(prefix, fallbackCode, err.Error())
+		return
+	}
+
+	for _, field := range nested.Fields {
+		if prefix != "" {
+			field.Field = prefix + "." + field.Field
+		}
+		e.Fields = append(e.Fields, field)
+	}
+}
+
+// HasErrors returns true if at least one field is invalid
+func (e *ValidationError) HasErrors() bool {
+	return e != nil && len(e.Fields) > 0
+}
+
+// HasCode returns true if any field error has the given code
+func (e *ValidationError) HasCode(code string) bool {
+	if e == nil {
+		return false
+	}
+
+	for _, field := range e.Fields {
+		if field.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrOrNil returns the ValidationError as an error, or nil if no field is
+// invalid. Returning it this way avoids a non-nil error interface holding a nil
+// pointer.
+func (e *ValidationError) ErrOrNil() error {
+	if !e.HasErrors() {
+		return nil
+	}
+	return e
+}
+
+// Error joins the messages of every field error
+func (e *ValidationError) Error() string {
+	if !e.HasErrors() {
+		return ErrValidation.Error()
+	}
+
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
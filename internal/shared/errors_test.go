@@ -0,0 +1,80 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidationError_Error(t *testing.T) {
+	verr := &ValidationError{}
+	if verr.ErrOrNil() != nil {
+		t.Error("ErrOrNil() should be nil without field errors")
+	}
+
+	verr.Add("city", CodeAddressCityRequired, "city is required")
+	if got := verr.Error(); got != "city is required" {
+		t.Errorf("Error() = %q, want single message", got)
+	}
+
+	verr.Add("state", CodeAddressStateRequired, "state is required")
+	if got := verr.Error(); got != "city is required; state is required" {
+		t.Errorf("Error() = %q, want joined messages", got)
+	}
+}
+
+func TestValidationError_ErrorsAsAndIs(t *testing.T) {
+	wrapped := fmt.Errorf("create school: %w", NewValidationError(FieldError{Field: "city", Code: CodeAddressCityRequired, Message: "city is required"}))
+
+	if !errors.Is(wrapped, ErrValidation) {
+		t.Error("errors.Is(wrapped, ErrValidation) = false")
+	}
+
+	var verr *ValidationError
+	if !errors.As(wrapped, &verr) {
+		t.Fatal("errors.As did not find *ValidationError")
+	}
+	if !verr.HasCode(CodeAddressCityRequired) {
+		t.Errorf("HasCode(%s) = false", CodeAddressCityRequired)
+	}
+	if verr.HasCode(CodeAddressStateRequired) {
+		t.Errorf("HasCode(%s) = true", CodeAddressStateRequired)
+	}
+}
+
+func TestValidationError_Merge(t *testing.T) {
+	nested := NewValidationError(FieldError{Field: "latitude", Code: CodeLocationLatitudeRange, Message: "bad latitude"})
+
+	verr := &ValidationError{}
+	verr.Merge("location", CodeAddressLocationInvalid, nested)
+	verr.Merge("location", CodeAddressLocationInvalid, errors.New("plain failure"))
+	verr.Merge("location", CodeAddressLocationInvalid, nil)
+
+	want := []FieldError{
+		{Field: "location.latitude", Code: CodeLocationLatitudeRange, Message: "bad latitude"},
+		{Field: "location", Code: CodeAddressLocationInvalid, Message: "plain failure"},
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("got %+v, want %+v", verr.Fields, want)
+	}
+	for i := range want {
+		if verr.Fields[i] != want[i] {
+			t.Errorf("Fields[%d] = %+v, want %+v", i, verr.Fields[i], want[i])
+		}
+	}
+}
+
+func TestValidationError_JSON(t *testing.T) {
+	verr := NewValidationError(FieldError{Field: "zip_code", Code: CodeAddressZipCodeFormat, Message: "zip code must be in format 12345 or 12345-6789"})
+
+	data, err := json.Marshal(verr)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"errors":[{"field":"zip_code","code":"address.zip_code.format","message":"zip code must be in format 12345 or 12345-6789"}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// NewID returns a random RFC 4122 version 4 UUID in its canonical string form.
+// Entity IDs are generated by services so that they are known before the entity
+// is persisted.
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand only fails if the OS entropy source is unavailable
+		panic(fmt.Sprintf("shared: generate id: %v", err))
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
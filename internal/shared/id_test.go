@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewID(t *testing.T) {
+	uuidV4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewID()
+		if !uuidV4.MatchString(id) {
+			t.Fatalf("NewID() = %q, not a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewID() returned duplicate %q", id)
+		}
+		seen[id] = true
+	}
+}
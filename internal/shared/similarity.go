@@ -0,0 +1,69 @@
+package shared
+
+// TrigramSimilarity is the similarity of two words from 0 to 1, as computed by
+// the PostgreSQL pg_trgm extension: the share of distinct trigrams of the padded
+// words that they have in common. It tolerates typos in longer words; words of
+// one or two letters only match themselves closely.
+func TrigramSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+	common := 0
+	for trigram := range trigramsA {
+		if trigramsB[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(trigramsA)+len(trigramsB)-common)
+}
+
+// trigrams returns the set of three-rune sequences of a word padded with two
+// spaces in front and one behind
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]bool, len(runes))
+	for idx := 0; idx+3 <= len(runes); idx++ {
+		set[string(runes[idx:idx+3])] = true
+	}
+	return set
+}
+
+// LevenshteinSimilarity is the similarity of two words from 0 to 1: one minus
+// their edit distance over the length of the longer word. It scores a single
+// typo in a short word higher than TrigramSimilarity does.
+func LevenshteinSimilarity(a, b string) float64 {
+	runesA, runesB := []rune(a), []rune(b)
+	longest := len(runesA)
+	if len(runesB) > longest {
+		longest = len(runesB)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(runesA, runesB))/float64(longest)
+}
+
+// editDistance counts the insertions, deletions and substitutions that turn a
+// into b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"lincoln", "lincoln", 1},
+		{"lincoln", "lincon", 0.5},
+		{"elementary", "middle", 0},
+		{"", "", 1},
+	}
+
+	for _, tt := range tests {
+		if got := TrigramSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TrigramSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"lincoln", "lincoln", 1},
+		{"lincon", "lincoln", 1 - 1.0/7},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"", "abc", 0},
+		{"", "", 1},
+	}
+
+	for _, tt := range tests {
+		if got := LevenshteinSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("LevenshteinSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package teacherwishlist
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hrh-backend/internal/shared"
+)
+
+// CatalogItem is a canonical supply item shared by all teachers, such as
+// "Crayola Crayons 24ct". Wishlist items that refer to the same product link to
+// one catalog item, so demand can be counted across classrooms.
+type CatalogItem struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Category ItemCategory `json:"category"`
+	// TypicalPriceCents is the usual unit price, used when a teacher picks the
+	// item without giving a price
+	TypicalPriceCents int64 `json:"typical_price_cents"`
+	// Aliases are other names teachers and retailers use for the item
+	Aliases []string `json:"aliases"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the catalog item's fields, returning a *shared.ValidationError
+// listing all problems
+func (c *CatalogItem) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if c.Name == "" {
+		verr.Add("name", shared.CodeCatalogNameRequired, "name is required")
+	} else if utf8.RuneCountInString(c.Name) > shared.MaxItemNameLength {
+		verr.Add("name", shared.CodeCatalogNameLength,
+			fmt.Sprintf("name must be at most %d characters", shared.MaxItemNameLength))
+	}
+
+	if !c.Category.IsValid() {
+		verr.Add("category", shared.CodeCatalogCategoryInvalid, fmt.Sprintf("unknown item category %q", c.Category))
+	}
+
+	if c.TypicalPriceCents < 0 || c.TypicalPriceCents > shared.MaxItemUnitPriceCents {
+		verr.Add("typical_price_cents", shared.CodeCatalogPriceRange,
+			fmt.Sprintf("typical price must be between 0 and %d cents", shared.MaxItemUnitPriceCents))
+	}
+
+	if len(c.Aliases) > shared.MaxCatalogAliases {
+		verr.Add("aliases", shared.CodeCatalogAliasesLimit,
+			fmt.Sprintf("a catalog item can have at most %d aliases", shared.MaxCatalogAliases))
+	}
+
+	seen := map[string]bool{NormalizeItemName(c.Name): true}
+	for idx, alias := range c.Aliases {
+		normalized := NormalizeItemName(alias)
+		switch {
+		case normalized == "" || utf8.RuneCountInString(alias) > shared.MaxItemNameLength:
+			verr.Add(fmt.Sprintf("aliases[%d]", idx), shared.CodeCatalogAliasInvalid,
+				fmt.Sprintf("aliases must be 1 to %d characters", shared.MaxItemNameLength))
+		case seen[normalized]:
+			verr.Add(fmt.Sprintf("aliases[%d]", idx), shared.CodeCatalogAliasInvalid,
+				fmt.Sprintf("alias %q repeats the name or another alias", alias))
+		}
+		seen[normalized] = true
+	}
+
+	return verr.ErrOrNil()
+}
+
+// Names returns the name followed by the aliases
+func (c *CatalogItem) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+var (
+	// itemCountRegex joins pack sizes such as "24 count", "24-ct" or "24pk" into
+	// a single "24ct" or "24pk" token
+	itemCountRegex = regexp.MustCompile(`\b(\d+)\s*-?\s*(count|ct|pack|pk|pc|pcs|piece|pieces)\b`)
+	itemWordRegex  = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// itemUnits maps the pack size units matched by itemCountRegex to one spelling
+var itemUnits = map[string]string{
+	"count": "ct", "ct": "ct", "pc": "ct", "pcs": "ct", "piece": "ct", "pieces": "ct",
+	"pack": "pk", "pk": "pk",
+}
+
+// NormalizeItemName reduces an item name to lower-case words so that spelling
+// variants compare equal: "Crayola Crayons, 24-Count" becomes
+// "crayola crayon 24ct". Apostrophes are dropped and simple plurals singularized.
+func NormalizeItemName(name string) string {
+	name = strings.ToLower(strings.NewReplacer("'", "", "’", "").Replace(name))
+	name = itemCountRegex.ReplaceAllStringFunc(name, func(match string) string {
+		parts := itemCountRegex.FindStringSubmatch(match)
+		return parts[1] + itemUnits[parts[2]]
+	})
+
+	words := itemWordRegex.FindAllString(name, -1)
+	for idx, word := range words {
+		words[idx] = singularize(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// singularize strips a plural "s" or "es" from a word of letters, leaving words
+// ending in "ss" or "us" and short words alone
+func singularize(word string) string {
+	switch {
+	case len(word) <= 3 || !strings.HasSuffix(word, "s") || strings.IndexFunc(word, isDigit) >= 0:
+		return word
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+		return word
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "xes"):
+		return word[:len(word)-2]
+	}
+	return word[:len(word)-1]
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// CatalogMatch is a catalog item matched to a free-text item name
+type CatalogMatch struct {
+	Item *CatalogItem `json:"item"`
+	// Score is the similarity of the name to the item's closest name or alias,
+	// from 0 to 1
+	Score float64 `json:"score"`
+}
+
+// MatchCatalog ranks catalog items by how similar their name or closest alias is
+// to name, best first, keeping those scoring at least minScore. Ties are broken
+// by catalog name.
+func MatchCatalog(name string, items []*CatalogItem, minScore float64) []CatalogMatch {
+	words := strings.Fields(NormalizeItemName(name))
+	if len(words) == 0 {
+		return []CatalogMatch{}
+	}
+
+	matches := []CatalogMatch{}
+	for _, item := range items {
+		best := 0.0
+		for _, candidate := range item.Names() {
+			if score := nameSimilarity(words, strings.Fields(NormalizeItemName(candidate))); score > best {
+				best = score
+			}
+		}
+		if best >= minScore {
+			matches = append(matches, CatalogMatch{Item: item, Score: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Item.Name < matches[j].Item.Name
+	})
+	return matches
+}
+
+// nameSimilarity compares two normalized names word by word. Each word is paired
+// with its most similar word on the other side, which tolerates typos and
+// reordering. The score is the harmonic mean of how well the words of each side
+// are covered, so extra words such as a brand lower it without ruling out a
+// match: "elmer glue stick 30pk" still matches "glue stick".
+func nameSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	coverA, coverB := wordCoverage(a, b), wordCoverage(b, a)
+	if coverA+coverB == 0 {
+		return 0
+	}
+	return 2 * coverA * coverB / (coverA + coverB)
+}
+
+// wordCoverage returns the mean over the words of a of their best similarity to
+// a word of b
+func wordCoverage(a, b []string) float64 {
+	total := 0.0
+	for _, word := range a {
+		best := 0.0
+		for _, other := range b {
+			if score := shared.TrigramSimilarity(word, other); score > best {
+				best = score
+			}
+		}
+		total += best
+	}
+	return total / float64(len(a))
+}
@@ -0,0 +1,106 @@
+package teacherwishlist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hrh-backend/internal/shared"
+)
+
+func TestNormalizeItemName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Crayola Crayons, 24-Count", "crayola crayon 24ct"},
+		{"crayola crayons 24 ct", "crayola crayon 24ct"},
+		{"Elmer's Glue Sticks (30 pk)", "elmer glue stick 30pk"},
+		{"Boxes of Tissues", "box of tissue"},
+		{"Art Supplies", "art supply"},
+		{"Glass Beakers", "glass beaker"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeItemName(tt.name); got != tt.want {
+			t.Errorf("NormalizeItemName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchCatalog(t *testing.T) {
+	crayons := &CatalogItem{ID: "crayons", Name: "Crayola Crayons 24ct", Aliases: []string{"crayon box"}}
+	glue := &CatalogItem{ID: "glue", Name: "Glue Sticks"}
+	markers := &CatalogItem{ID: "markers", Name: "Dry Erase Markers"}
+	catalog := []*CatalogItem{crayons, glue, markers}
+
+	tests := []struct {
+		name   string
+		wantID string
+	}{
+		{"crayola crayons, 24 count", "crayons"},
+		{"Crayon boxes", "crayons"},
+		{"Elmer's glue sticks 30 pack", "glue"},
+		{"glue stiks", "glue"},
+		{"dry-erase markers", "markers"},
+		{"classroom rug", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		matches := MatchCatalog(tt.name, catalog, shared.CatalogMatchThreshold)
+		switch {
+		case tt.wantID == "" && len(matches) > 0:
+			t.Errorf("MatchCatalog(%q) = %s (%.2f), want no match", tt.name, matches[0].Item.ID, matches[0].Score)
+		case tt.wantID != "" && (len(matches) == 0 || matches[0].Item.ID != tt.wantID):
+			t.Errorf("MatchCatalog(%q) = %+v, want %s first", tt.name, matches, tt.wantID)
+		}
+	}
+
+	if matches := MatchCatalog("Glue Sticks", catalog, 0); matches[0].Score != 1 {
+		t.Errorf("exact match score = %.2f, want 1", matches[0].Score)
+	}
+}
+
+func TestCatalogItem_Validate(t *testing.T) {
+	tooMany := make([]string, shared.MaxCatalogAliases+1)
+	for idx := range tooMany {
+		tooMany[idx] = "alias " + strings.Repeat("x", idx+1)
+	}
+
+	tests := []struct {
+		name     string
+		item     CatalogItem
+		wantCode string
+	}{
+		{"valid", CatalogItem{Name: "Glue Sticks", Category: CategorySupplies, Aliases: []string{"glue stick 30pk"}}, ""},
+		{"missing name", CatalogItem{Category: CategorySupplies}, shared.CodeCatalogNameRequired},
+		{"unknown category", CatalogItem{Name: "Glue Sticks", Category: "glue"}, shared.CodeCatalogCategoryInvalid},
+		{"price too high", CatalogItem{Name: "Glue Sticks", Category: CategorySupplies,
+			TypicalPriceCents: shared.MaxItemUnitPriceCents + 1}, shared.CodeCatalogPriceRange},
+		{"alias repeats name", CatalogItem{Name: "Glue Sticks", Category: CategorySupplies,
+			Aliases: []string{"glue stick"}}, shared.CodeCatalogAliasInvalid},
+		{"blank alias", CatalogItem{Name: "Glue Sticks", Category: CategorySupplies,
+			Aliases: []string{"--"}}, shared.CodeCatalogAliasInvalid},
+		{"too many aliases", CatalogItem{Name: "Glue Sticks", Category: CategorySupplies,
+			Aliases: tooMany}, shared.CodeCatalogAliasesLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) || !verr.HasCode(tt.wantCode) {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package teacherwishlist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hrh-backend/internal/shared"
+)
+
+// ImportFormat is the file format of a retailer list export
+type ImportFormat string
+
+const (
+	// ImportCSV is a comma-separated export with a header row
+	ImportCSV ImportFormat = "csv"
+	// ImportHTML is a list page saved from a retailer site, with the items in a
+	// table whose header row names the columns
+	ImportHTML ImportFormat = "html"
+)
+
+// ImportFormatFromFilename returns the import format matching the file extension
+func ImportFormatFromFilename(name string) (ImportFormat, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return ImportCSV, nil
+	case ".html", ".htm":
+		return ImportHTML, nil
+	}
+	return "", errUnsupportedImportFormat()
+}
+
+func errUnsupportedImportFormat() error {
+	return shared.NewValidationError(shared.FieldError{
+		Field:   "format",
+		Code:    shared.CodeImportFormatUnsupported,
+		Message: "only .csv and .html files can be imported",
+	})
+}
+
+// ImportRowError lists why one row of an import file was skipped
+type ImportRowError struct {
+	// Line is the line of the file the row starts on, counting from 1
+	Line   int                 `json:"line"`
+	Errors []shared.FieldError `json:"errors"`
+}
+
+// ImportedList is a parsed retailer export
+type ImportedList struct {
+	// Items are the rows that parsed into valid wishlist items, in file order
+	Items []WishlistItemInput `json:"items"`
+	// Skipped are the rows that could not be parsed
+	Skipped []ImportRowError `json:"skipped"`
+}
+
+// ParseRetailerFile reads a retailer export saved on the local file system,
+// choosing the format from the file extension
+func ParseRetailerFile(path string) (*ImportedList, error) {
+	format, err := ImportFormatFromFilename(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open import file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseRetailerList(file, format)
+}
+
+// ParseRetailerList parses a retailer export into wishlist item inputs. The file
+// needs a header row naming at least the product name column; quantity, price
+// and URL columns are optional. Rows that cannot be parsed or do not make a valid
+// item are reported in Skipped instead of failing the whole import.
+func ParseRetailerList(r io.Reader, format ImportFormat) (*ImportedList, error) {
+	data, err := io.ReadAll(io.LimitReader(r, shared.MaxImportFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	if len(data) > shared.MaxImportFileBytes {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "file",
+			Code:    shared.CodeImportFileSize,
+			Message: fmt.Sprintf("import files must be at most %d bytes", shared.MaxImportFileBytes),
+		})
+	}
+
+	var records []importRecord
+	var skipped []ImportRowError
+	switch format {
+	case ImportCSV:
+		records, skipped = readCSVRecords(data)
+	case ImportHTML:
+		records, err = readHTMLRecords(data)
+	default:
+		err = errUnsupportedImportFormat()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Exports often start with a list title or notes, so the header is the first
+	// row naming a product name column
+	header := -1
+	var columns importColumns
+	for idx, record := range records {
+		if columns = newImportColumns(record.cells); columns.name >= 0 {
+			header = idx
+			break
+		}
+	}
+	if header < 0 {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "file",
+			Code:    shared.CodeImportNameColumnMissing,
+			Message: "the file has no header row with a product name column",
+		})
+	}
+
+	list := &ImportedList{Items: []WishlistItemInput{}}
+	for _, record := range records[header+1:] {
+		if record.isBlank() {
+			continue
+		}
+
+		item, err := columns.item(record)
+		var verr *shared.ValidationError
+		if errors.As(err, &verr) {
+			skipped = append(skipped, ImportRowError{Line: record.line, Errors: verr.Fields})
+			continue
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	// CSV syntax errors are found while reading, so put them back in file order
+	sort.SliceStable(skipped, func(i, j int) bool { return skipped[i].Line < skipped[j].Line })
+	list.Skipped = append([]ImportRowError{}, skipped...)
+	return list, nil
+}
+
+// importRecord is one table row of an import file
+type importRecord struct {
+	line  int
+	cells []string
+	// link is the first hyperlink in an HTML row, used as the product URL when
+	// there is no URL column
+	link string
+}
+
+func (r importRecord) isBlank() bool {
+	for _, cell := range r.cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSVRecords reads every CSV record, reporting malformed lines as skipped rows
+func readCSVRecords(data []byte) ([]importRecord, []ImportRowError) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records []importRecord
+	var skipped []ImportRowError
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			skipped = append(skipped, ImportRowError{
+				Line: parseErr.StartLine,
+				Errors: []shared.FieldError{{
+					Field:   "cells",
+					Code:    shared.CodeImportRowMalformed,
+					Message: fmt.Sprintf("line %d is not valid CSV: %v", parseErr.StartLine, parseErr.Err),
+				}},
+			})
+			continue
+		}
+		if err != nil {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, importRecord{line: line, cells: cells})
+	}
+
+	return records, skipped
+}
+
+var (
+	htmlScriptRegex = regexp.MustCompile(`(?is)<script\b.*?</script\s*>`)
+	htmlStyleRegex  = regexp.MustCompile(`(?is)<style\b.*?</style\s*>`)
+)
+
+// readHTMLRecords reads the rows of every table in a saved HTML page. Scripts
+// and styles are blanked first, keeping their line breaks so line numbers still
+// match the file, because their content is rarely well-formed enough for the
+// lenient XML decoder.
+func readHTMLRecords(data []byte) ([]importRecord, error) {
+	blank := func(match []byte) []byte {
+		return bytes.Repeat([]byte("\n"), bytes.Count(match, []byte("\n")))
+	}
+	data = htmlScriptRegex.ReplaceAllFunc(data, blank)
+	data = htmlStyleRegex.ReplaceAllFunc(data, blank)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var records []importRecord
+	var row *importRecord
+	var cell *strings.Builder
+	endCell := func() {
+		if row != nil && cell != nil {
+			row.cells = append(row.cells, strings.Join(strings.Fields(cell.String()), " "))
+		}
+		cell = nil
+	}
+	endRow := func() {
+		endCell()
+		if row != nil {
+			records = append(records, *row)
+		}
+		row = nil
+	}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, shared.NewValidationError(shared.FieldError{
+				Field:   "file",
+				Code:    shared.CodeImportFileUnreadable,
+				Message: fmt.Sprintf("the HTML file could not be read: %v", err),
+			})
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "tr":
+				endRow()
+				line, _ := decoder.InputPos()
+				row = &importRecord{line: line}
+			case "td", "th":
+				endCell()
+				cell = &strings.Builder{}
+			case "a":
+				if row != nil && row.link == "" {
+					row.link = attr(t, "href")
+				}
+			case "br":
+				if cell != nil {
+					cell.WriteString(" ")
+				}
+			}
+		case xml.EndElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "td", "th":
+				endCell()
+			case "tr", "table":
+				endRow()
+			}
+		case xml.CharData:
+			if cell != nil {
+				cell.Write(t)
+			}
+		}
+	}
+	endRow()
+
+	return records, nil
+}
+
+// attr returns the value of the named attribute of an element
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return strings.TrimSpace(a.Value)
+		}
+	}
+	return ""
+}
+
+// importColumns holds the position of each known column, or -1 if absent
+type importColumns struct {
+	name, quantity, price, url int
+}
+
+// importHeaders maps normalized header names used by retailer exports to the
+// item field they hold
+var importHeaders = map[string]string{
+	"name":               "name",
+	"item":               "name",
+	"item name":          "name",
+	"product":            "name",
+	"product name":       "name",
+	"title":              "name",
+	"quantity":           "quantity",
+	"qty":                "quantity",
+	"quantity requested": "quantity",
+	"requested":          "quantity",
+	"price":              "price",
+	"unit price":         "price",
+	"price each":         "price",
+	"cost":               "price",
+	"url":                "url",
+	"link":               "url",
+	"product url":        "url",
+	"product link":       "url",
+}
+
+// newImportColumns finds the known columns in a header row. The first matching
+// column wins.
+func newImportColumns(header []string) importColumns {
+	columns := importColumns{name: -1, quantity: -1, price: -1, url: -1}
+	for idx, cell := range header {
+		normalized := strings.Join(strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(
+			strings.ToLower(cell))), " ")
+
+		var position *int
+		switch importHeaders[normalized] {
+		case "name":
+			position = &columns.name
+		case "quantity":
+			position = &columns.quantity
+		case "price":
+			position = &columns.price
+		case "url":
+			position = &columns.url
+		default:
+			continue
+		}
+		if *position < 0 {
+			*position = idx
+		}
+	}
+	return columns
+}
+
+// item parses one data row into a validated item input, returning a
+// *shared.ValidationError listing every problem with the row
+func (c importColumns) item(record importRecord) (WishlistItemInput, error) {
+	verr := &shared.ValidationError{}
+	cell := func(idx int) string {
+		if idx < 0 || idx >= len(record.cells) {
+			return ""
+		}
+		return strings.TrimSpace(record.cells[idx])
+	}
+
+	input := WishlistItemInput{Name: cell(c.name), Quantity: 1, ProductURL: cell(c.url)}
+	if c.url < 0 {
+		input.ProductURL = record.link
+	}
+
+	if raw := cell(c.quantity); raw != "" {
+		quantity, err := strconv.Atoi(raw)
+		if err != nil {
+			verr.Add("quantity_requested", shared.CodeImportQuantityFormat,
+				fmt.Sprintf("quantity %q is not a whole number", raw))
+		}
+		input.Quantity = quantity
+	}
+
+	if raw := cell(c.price); raw != "" {
+		cents, ok := parsePriceCents(raw)
+		if !ok {
+			verr.Add("unit_price_cents", shared.CodeImportPriceFormat,
+				fmt.Sprintf("price %q is not a dollar amount", raw))
+		}
+		input.UnitPriceCents = cents
+	}
+
+	if verr.HasErrors() {
+		return WishlistItemInput{}, verr
+	}
+	if err := newItemFromInput(input).Validate(); err != nil {
+		return WishlistItemInput{}, err
+	}
+	return input, nil
+}
+
+var priceRegex = regexp.MustCompile(`^(\d+)(?:\.(\d{1,2}))?$`)
+
+// parsePriceCents parses a dollar amount such as "$1,299.99" or "4.5 USD" into
+// cents
+func parsePriceCents(raw string) (int64, bool) {
+	cleaned := strings.NewReplacer("$", "", "USD", "", "usd", "", ",", "", " ", "").Replace(raw)
+	match := priceRegex.FindStringSubmatch(cleaned)
+	if match == nil {
+		return 0, false
+	}
+
+	dollars, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	cents, _ := strconv.ParseInt((match[2] + "00")[:2], 10, 64)
+	return dollars*100 + cents, true
+}
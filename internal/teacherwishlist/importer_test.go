@@ -0,0 +1,184 @@
+package teacherwishlist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hrh-backend/internal/shared"
+)
+
+const testCSVExport = "\ufeffMy classroom list\n" +
+	"Product Name,Qty,Price,Product URL\n" +
+	"Crayola Crayons 24ct,12,$1.29,https://shop.example.com/p/crayons\n" +
+	"\"Composition Notebook, Wide Ruled\",30,\"$1,002.5\",\n" +
+	",,,\n" +
+	"Glue Sticks,lots,0.59,\n" +
+	"Dry Erase Markers,2,free,ftp://example.com/markers\n" +
+	"Tissues,,3,\n"
+
+func TestParseRetailerList_CSV(t *testing.T) {
+	list, err := ParseRetailerList(strings.NewReader(testCSVExport), ImportCSV)
+	if err != nil {
+		t.Fatalf("ParseRetailerList() error = %v", err)
+	}
+
+	want := []WishlistItemInput{
+		{Name: "Crayola Crayons 24ct", Quantity: 12, UnitPriceCents: 129, ProductURL: "https://shop.example.com/p/crayons"},
+		{Name: "Composition Notebook, Wide Ruled", Quantity: 30, UnitPriceCents: 100250},
+		{Name: "Tissues", Quantity: 1, UnitPriceCents: 300},
+	}
+	if len(list.Items) != len(want) {
+		t.Fatalf("Items = %+v, want %d items", list.Items, len(want))
+	}
+	for idx := range want {
+		if list.Items[idx] != want[idx] {
+			t.Errorf("Items[%d] = %+v, want %+v", idx, list.Items[idx], want[idx])
+		}
+	}
+
+	if len(list.Skipped) != 2 {
+		t.Fatalf("Skipped = %+v, want 2 rows", list.Skipped)
+	}
+	if glue := list.Skipped[0]; glue.Line != 6 || glue.Errors[0].Code != shared.CodeImportQuantityFormat {
+		t.Errorf("Skipped[0] = %+v, want quantity error on line 6", glue)
+	}
+	markers := shared.NewValidationError(list.Skipped[1].Errors...)
+	if list.Skipped[1].Line != 7 || !markers.HasCode(shared.CodeImportPriceFormat) {
+		t.Errorf("Skipped[1] = %+v, want price error on line 7", list.Skipped[1])
+	}
+	if markers.HasCode(shared.CodeItemProductURLFormat) {
+		t.Errorf("Skipped[1] = %+v, item validation should only run on rows that parsed", list.Skipped[1])
+	}
+}
+
+func TestParseRetailerList_CSVMalformedRow(t *testing.T) {
+	export := "name,quantity\nPencils,10\nRuler 12\" long,4\nErasers,3\n"
+
+	list, err := ParseRetailerList(strings.NewReader(export), ImportCSV)
+	if err != nil {
+		t.Fatalf("ParseRetailerList() error = %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[1].Name != "Erasers" {
+		t.Errorf("Items = %+v, want pencils and erasers", list.Items)
+	}
+	if len(list.Skipped) != 1 || list.Skipped[0].Line != 3 || list.Skipped[0].Errors[0].Code != shared.CodeImportRowMalformed {
+		t.Errorf("Skipped = %+v, want malformed row on line 3", list.Skipped)
+	}
+}
+
+const testHTMLExport = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Saved list</title>
+  <script>if (a < b && c) { render("<tr><td>") }</script>
+  <style>td > a { color: red }</style>
+</head>
+<body>
+  <h1>Room 12&nbsp;wish list</h1>
+  <table class="list">
+    <tr><th>Item</th><th>Quantity</th><th>Price each</th></tr>
+    <tr>
+      <td><a href="https://shop.example.com/p/scissors">Safety Scissors</a><br>Blunt tip</td>
+      <td>24</td>
+      <td>$2.49</td>
+    </tr>
+    <tr><td>Globe</td><td>1</td><td>$39.00</td></tr>
+    <tr><td></td><td>3</td><td>$1.00</td></tr>
+  </table>
+</body>
+</html>
+`
+
+func TestParseRetailerList_HTML(t *testing.T) {
+	list, err := ParseRetailerList(strings.NewReader(testHTMLExport), ImportHTML)
+	if err != nil {
+		t.Fatalf("ParseRetailerList() error = %v", err)
+	}
+
+	want := []WishlistItemInput{
+		{Name: "Safety Scissors Blunt tip", Quantity: 24, UnitPriceCents: 249, ProductURL: "https://shop.example.com/p/scissors"},
+		{Name: "Globe", Quantity: 1, UnitPriceCents: 3900},
+	}
+	if len(list.Items) != len(want) {
+		t.Fatalf("Items = %+v, want %d items", list.Items, len(want))
+	}
+	for idx := range want {
+		if list.Items[idx] != want[idx] {
+			t.Errorf("Items[%d] = %+v, want %+v", idx, list.Items[idx], want[idx])
+		}
+	}
+
+	if len(list.Skipped) != 1 || list.Skipped[0].Line != 18 || list.Skipped[0].Errors[0].Code != shared.CodeItemNameRequired {
+		t.Errorf("Skipped = %+v, want missing name on line 18", list.Skipped)
+	}
+}
+
+func TestParseRetailerList_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		format   ImportFormat
+		wantCode string
+	}{
+		{"no name column", "sku,qty\n123,4\n", ImportCSV, shared.CodeImportNameColumnMissing},
+		{"empty file", "", ImportCSV, shared.CodeImportNameColumnMissing},
+		{"unsupported format", "name\nPencils\n", ImportFormat("xlsx"), shared.CodeImportFormatUnsupported},
+		{"too large", "name\n" + strings.Repeat("x", shared.MaxImportFileBytes), ImportCSV, shared.CodeImportFileSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRetailerList(strings.NewReader(tt.data), tt.format)
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) || !verr.HasCode(tt.wantCode) {
+				t.Errorf("ParseRetailerList() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParseRetailerFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list.CSV")
+	if err := os.WriteFile(path, []byte("Item Name,QTY\nPencils,10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := ParseRetailerFile(path)
+	if err != nil {
+		t.Fatalf("ParseRetailerFile() error = %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Quantity != 10 {
+		t.Errorf("Items = %+v", list.Items)
+	}
+
+	if _, err := ParseRetailerFile(filepath.Join(dir, "list.pdf")); !errors.Is(err, shared.ErrValidation) {
+		t.Errorf("ParseRetailerFile(.pdf) error = %v, want validation error", err)
+	}
+}
+
+func TestParsePriceCents(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int64
+		ok   bool
+	}{
+		{"$12.99", 1299, true},
+		{"4.5 USD", 450, true},
+		{"1,299", 129900, true},
+		{"0", 0, true},
+		{"-3.00", 0, false},
+		{"12.999", 0, false},
+		{"about 5", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parsePriceCents(tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parsePriceCents(%q) = %d, %v, want %d, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
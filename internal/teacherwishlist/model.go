@@ -0,0 +1,1152 @@
+// Package teacherwishlist holds the core domain of teachers and their classroom
+// wishlists.
+package teacherwishlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+// ItemPriority expresses how urgently a teacher needs an item
+type ItemPriority string
+
+const (
+	// PriorityLow marks nice-to-have items
+	PriorityLow ItemPriority = "low"
+	// PriorityMedium is the default priority
+	PriorityMedium ItemPriority = "medium"
+	// PriorityHigh marks items the classroom cannot do without
+	PriorityHigh ItemPriority = "high"
+)
+
+// IsValid returns true if the priority is a known value
+func (p ItemPriority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+// rank orders priorities from most to least urgent
+func (p ItemPriority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ItemCategory groups wishlist items for browsing and reporting
+type ItemCategory string
+
+// Known item categories
+const (
+	CategorySupplies   ItemCategory = "supplies"
+	CategoryBooks      ItemCategory = "books"
+	CategoryTechnology ItemCategory = "technology"
+	CategoryArt        ItemCategory = "art"
+	CategoryScience    ItemCategory = "science"
+	CategoryFurniture  ItemCategory = "furniture"
+	CategoryHygiene    ItemCategory = "hygiene"
+	CategoryOther      ItemCategory = "other"
+)
+
+// IsValid returns true if the category is a known value
+func (c ItemCategory) IsValid() bool {
+	switch c {
+	case CategorySupplies, CategoryBooks, CategoryTechnology, CategoryArt,
+		CategoryScience, CategoryFurniture, CategoryHygiene, CategoryOther:
+		return true
+	}
+	return false
+}
+
+// WishlistItem is one line of a Wishlist. Prices are kept in integer cents so
+// totals are exact.
+type WishlistItem struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	QuantityRequested int    `json:"quantity_requested"`
+	QuantityFulfilled int    `json:"quantity_fulfilled"`
+	// QuantityPledged is held by donors' pending pledges and not yet received
+	QuantityPledged int          `json:"quantity_pledged"`
+	UnitPriceCents  int64        `json:"unit_price_cents"`
+	Priority        ItemPriority `json:"priority"`
+	Category        ItemCategory `json:"category"`
+	ProductURL      string       `json:"product_url,omitempty"`
+	// CatalogItemID links the item to the canonical catalog item it requests, if
+	// the teacher picked one or the name matched one
+	CatalogItemID string `json:"catalog_item_id,omitempty"`
+}
+
+// NewWishlistItem creates a WishlistItem with validation. An empty priority
+// defaults to PriorityMedium and an empty category to CategoryOther.
+func NewWishlistItem(name string, quantity int, unitPriceCents int64, priority ItemPriority,
+	category ItemCategory, productURL string) (WishlistItem, error) {
+	item := newWishlistItem(name, quantity, unitPriceCents, priority, category, productURL)
+
+	if err := item.Validate(); err != nil {
+		return WishlistItem{}, err
+	}
+
+	return item, nil
+}
+
+// newWishlistItem normalizes the item fields and applies defaults without
+// validating them, so callers building a whole wishlist can report every
+// problem at once
+func newWishlistItem(name string, quantity int, unitPriceCents int64, priority ItemPriority,
+	category ItemCategory, productURL string) WishlistItem {
+	item := WishlistItem{
+		ID:                shared.NewID(),
+		Name:              strings.TrimSpace(name),
+		QuantityRequested: quantity,
+		UnitPriceCents:    unitPriceCents,
+		Priority:          ItemPriority(strings.ToLower(strings.TrimSpace(string(priority)))),
+		Category:          ItemCategory(strings.ToLower(strings.TrimSpace(string(category)))),
+		ProductURL:        strings.TrimSpace(productURL),
+	}
+
+	if item.Priority == "" {
+		item.Priority = PriorityMedium
+	}
+	if item.Category == "" {
+		item.Category = CategoryOther
+	}
+
+	return item
+}
+
+// Validate checks every field of the item and its quantity invariants, returning
+// a *shared.ValidationError listing all problems
+func (i WishlistItem) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if i.Name == "" {
+		verr.Add("name", shared.CodeItemNameRequired, "item name is required")
+	} else if utf8.RuneCountInString(i.Name) > shared.MaxItemNameLength {
+		verr.Add("name", shared.CodeItemNameLength,
+			fmt.Sprintf("item name must be at most %d characters", shared.MaxItemNameLength))
+	}
+
+	if i.QuantityRequested < 1 || i.QuantityRequested > shared.MaxItemQuantity {
+		verr.Add("quantity_requested", shared.CodeItemQuantityRange,
+			fmt.Sprintf("quantity requested must be between 1 and %d", shared.MaxItemQuantity))
+	} else if committed := i.QuantityFulfilled + i.QuantityPledged; committed > i.QuantityRequested {
+		verr.Add("quantity_requested", shared.CodeItemQuantityBelowFilled,
+			fmt.Sprintf("quantity requested cannot be less than the %d already fulfilled or pledged", committed))
+	}
+
+	if i.QuantityFulfilled < 0 {
+		verr.Add("quantity_fulfilled", shared.CodeItemFulfilledRange, "quantity fulfilled cannot be negative")
+	}
+
+	if i.QuantityPledged < 0 {
+		verr.Add("quantity_pledged", shared.CodeItemPledgedRange, "quantity pledged cannot be negative")
+	}
+
+	if i.UnitPriceCents < 0 {
+		verr.Add("unit_price_cents", shared.CodeItemUnitPriceNegative, "unit price cannot be negative")
+	} else if i.UnitPriceCents > shared.MaxItemUnitPriceCents {
+		verr.Add("unit_price_cents", shared.CodeItemUnitPriceMax,
+			fmt.Sprintf("unit price cannot exceed %s", formatCents(shared.MaxItemUnitPriceCents)))
+	}
+
+	if !i.Priority.IsValid() {
+		verr.Add("priority", shared.CodeItemPriorityInvalid, "priority must be low, medium or high")
+	}
+
+	if !i.Category.IsValid() {
+		verr.Add("category", shared.CodeItemCategoryInvalid, fmt.Sprintf("unknown item category %q", i.Category))
+	}
+
+	if i.ProductURL != "" && !isProductURL(i.ProductURL) {
+		verr.Add("product_url", shared.CodeItemProductURLFormat, "product URL must be an absolute http or https URL")
+	}
+
+	return verr.ErrOrNil()
+}
+
+// QuantityRemaining returns how many units have not been received yet,
+// including units held by pending pledges
+func (i WishlistItem) QuantityRemaining() int {
+	if remaining := i.QuantityRequested - i.QuantityFulfilled; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// QuantityAvailable returns how many units are still open for donors to pledge
+func (i WishlistItem) QuantityAvailable() int {
+	if available := i.QuantityRequested - i.QuantityFulfilled - i.QuantityPledged; available > 0 {
+		return available
+	}
+	return 0
+}
+
+// IsFulfilled returns true if every requested unit has been received
+func (i WishlistItem) IsFulfilled() bool {
+	return i.QuantityRemaining() == 0
+}
+
+// Fulfill records quantity units as received outside of a pledge. Units held by
+// pending pledges cannot be fulfilled this way. It fails without changing the
+// item if that would exceed the quantity available.
+func (i *WishlistItem) Fulfill(quantity int) error {
+	if quantity < 1 || quantity > i.QuantityAvailable() {
+		return shared.NewValidationError(shared.FieldError{
+			Field:   "quantity",
+			Code:    shared.CodeItemFulfillmentQuantity,
+			Message: fmt.Sprintf("fulfilled quantity must be between 1 and the %d still available", i.QuantityAvailable()),
+		})
+	}
+
+	i.QuantityFulfilled += quantity
+	return nil
+}
+
+// reserve holds quantity units for a new pledge
+func (i *WishlistItem) reserve(quantity int) error {
+	if quantity > i.QuantityAvailable() {
+		return fmt.Errorf("%w: %d requested, %d available", ErrInsufficientQuantity, quantity, i.QuantityAvailable())
+	}
+
+	i.QuantityPledged += quantity
+	return nil
+}
+
+// settle releases quantity held units, counting them as fulfilled if the pledge
+// was confirmed
+func (i *WishlistItem) settle(quantity int, status PledgeStatus) {
+	i.QuantityPledged -= quantity
+	if status == PledgeConfirmed {
+		i.QuantityFulfilled += quantity
+	}
+}
+
+// WishlistStatus is the lifecycle state of a wishlist
+type WishlistStatus string
+
+const (
+	// WishlistDraft is being prepared and is only visible to its teacher
+	WishlistDraft WishlistStatus = "draft"
+	// WishlistPublished is visible to donors between PublishAt and ExpireAt
+	WishlistPublished WishlistStatus = "published"
+	// WishlistPaused is temporarily hidden from donors
+	WishlistPaused WishlistStatus = "paused"
+	// WishlistArchived is closed and read-only
+	WishlistArchived WishlistStatus = "archived"
+)
+
+// wishlistTransitions lists the statuses reachable from each status
+var wishlistTransitions = map[WishlistStatus][]WishlistStatus{
+	WishlistDraft:     {WishlistPublished, WishlistArchived},
+	WishlistPublished: {WishlistPaused, WishlistArchived},
+	WishlistPaused:    {WishlistPublished, WishlistArchived},
+	// An archived list can be reopened as a draft, e.g. for the next school year
+	WishlistArchived: {WishlistDraft},
+}
+
+// CanTransitionTo returns true if the lifecycle allows moving to next
+func (s WishlistStatus) CanTransitionTo(next WishlistStatus) bool {
+	for _, allowed := range wishlistTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// SchoolYearEnd returns the end of the school year containing t: the start of
+// July 1 (UTC) of the same year, or of the next year from July onwards
+func SchoolYearEnd(t time.Time) time.Time {
+	t = t.UTC()
+	end := time.Date(t.Year(), shared.SchoolYearEndMonth, 1, 0, 0, 0, 0, time.UTC)
+	if !t.Before(end) {
+		end = end.AddDate(1, 0, 0)
+	}
+	return end
+}
+
+// GradeLevel is the grade of the students a wishlist is for
+type GradeLevel string
+
+const (
+	// GradePreK is pre-kindergarten
+	GradePreK GradeLevel = "pre_k"
+	// GradeKindergarten is kindergarten
+	GradeKindergarten GradeLevel = "k"
+	// GradeMixed is a class spanning several grades
+	GradeMixed GradeLevel = "mixed"
+)
+
+// IsValid returns true if the grade is pre-K, K, 1 to 12 or mixed
+func (g GradeLevel) IsValid() bool {
+	switch g {
+	case GradePreK, GradeKindergarten, GradeMixed:
+		return true
+	}
+
+	grade, err := strconv.Atoi(string(g))
+	return err == nil && grade >= 1 && grade <= 12 && strconv.Itoa(grade) == string(g)
+}
+
+// SchoolGrade returns the NCES grade of the grade level, e.g. "03" for "3", to
+// compare it with a school's grade span. A mixed class has no single grade, so
+// it returns false for mixed and for invalid levels.
+func (g GradeLevel) SchoolGrade() (schooldirectory.Grade, bool) {
+	switch {
+	case g == GradePreK:
+		return schooldirectory.GradePreK, true
+	case g == GradeKindergarten:
+		return schooldirectory.GradeKindergarten, true
+	case g == GradeMixed || !g.IsValid():
+		return "", false
+	}
+
+	grade, _ := strconv.Atoi(string(g))
+	return schooldirectory.Grade(fmt.Sprintf("%02d", grade)), true
+}
+
+// GradeLevelOf returns the grade level of an NCES grade, e.g. "3" for "03".
+// Ungraded maps to mixed. It returns false for an unknown grade.
+func GradeLevelOf(grade schooldirectory.Grade) (GradeLevel, bool) {
+	switch grade {
+	case schooldirectory.GradePreK:
+		return GradePreK, true
+	case schooldirectory.GradeKindergarten:
+		return GradeKindergarten, true
+	case schooldirectory.GradeUngraded:
+		return GradeMixed, true
+	}
+	if !grade.IsValid() {
+		return "", false
+	}
+
+	number, _ := strconv.Atoi(string(grade))
+	return GradeLevel(strconv.Itoa(number)), true
+}
+
+// WishlistVisibility controls where donors can find a wishlist
+type WishlistVisibility string
+
+const (
+	// VisibilityPublic lists the wishlist in public search. It is the default, so
+	// an empty visibility also means public.
+	VisibilityPublic WishlistVisibility = "public"
+	// VisibilityUnlisted keeps the wishlist out of search; donors need its link
+	VisibilityUnlisted WishlistVisibility = "unlisted"
+)
+
+// IsValid returns true if the visibility is a known value
+func (v WishlistVisibility) IsValid() bool {
+	return v == VisibilityPublic || v == VisibilityUnlisted
+}
+
+// WishlistScope describes the class period, event or trip a wishlist is for and
+// who can find it, so a teacher can keep several lists apart
+type WishlistScope struct {
+	GradeLevel   GradeLevel         `json:"grade_level,omitempty"`
+	Subject      string             `json:"subject,omitempty"`
+	StudentCount int                `json:"student_count,omitempty"`
+	Visibility   WishlistVisibility `json:"visibility"`
+}
+
+// normalize trims the scope and applies the default visibility
+func (s WishlistScope) normalize() WishlistScope {
+	s.GradeLevel = GradeLevel(strings.ToLower(strings.TrimSpace(string(s.GradeLevel))))
+	s.Subject = strings.TrimSpace(s.Subject)
+	s.Visibility = WishlistVisibility(strings.ToLower(strings.TrimSpace(string(s.Visibility))))
+	if s.Visibility == "" {
+		s.Visibility = VisibilityPublic
+	}
+	return s
+}
+
+// validate records every invalid scope field in verr
+func (s WishlistScope) validate(verr *shared.ValidationError) {
+	if s.GradeLevel != "" && !s.GradeLevel.IsValid() {
+		verr.Add("grade_level", shared.CodeWishlistGradeLevel,
+			fmt.Sprintf("unknown grade level %q; use pre_k, k, 1 to 12 or mixed", s.GradeLevel))
+	}
+
+	if utf8.RuneCountInString(s.Subject) > shared.MaxWishlistSubjectLength {
+		verr.Add("subject", shared.CodeWishlistSubjectLength,
+			fmt.Sprintf("subject must be at most %d characters", shared.MaxWishlistSubjectLength))
+	}
+
+	if s.StudentCount < 0 || s.StudentCount > shared.MaxWishlistStudentCount {
+		verr.Add("student_count", shared.CodeWishlistStudentCount,
+			fmt.Sprintf("student count must be between 0 and %d", shared.MaxWishlistStudentCount))
+	}
+
+	if s.Visibility != "" && !s.Visibility.IsValid() {
+		verr.Add("visibility", shared.CodeWishlistVisibility, "visibility must be public or unlisted")
+	}
+}
+
+// Wishlist is a teacher's list of classroom needs. A teacher can keep several,
+// e.g. one per class period, event or field trip.
+type Wishlist struct {
+	ID          string `json:"id"`
+	TeacherID   string `json:"teacher_id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	WishlistScope
+	Items  []WishlistItem `json:"items"`
+	Status WishlistStatus `json:"status"`
+	// PublishAt optionally delays when a published list becomes visible
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	// ExpireAt is when a published list stops being visible and gets archived
+	ExpireAt   *time.Time `json:"expire_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// Revision is the number of the latest WishlistRevision; it only changes
+	// when the teacher edits the list
+	Revision int `json:"revision"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the wishlist and all of its items, returning a
+// *shared.ValidationError listing all problems. Item errors are reported under
+// "items[i]".
+func (w *Wishlist) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if strings.TrimSpace(w.TeacherID) == "" {
+		verr.Add("teacher_id", shared.CodeWishlistTeacherRequired, "teacher is required")
+	}
+
+	if w.Title == "" {
+		verr.Add("title", shared.CodeWishlistTitleRequired, "title is required")
+	} else if utf8.RuneCountInString(w.Title) > shared.MaxWishlistTitleLength {
+		verr.Add("title", shared.CodeWishlistTitleLength,
+			fmt.Sprintf("title must be at most %d characters", shared.MaxWishlistTitleLength))
+	}
+
+	w.WishlistScope.validate(verr)
+
+	if len(w.Items) > shared.MaxWishlistItems {
+		verr.Add("items", shared.CodeWishlistItemsLimit,
+			fmt.Sprintf("a wishlist can have at most %d items", shared.MaxWishlistItems))
+	}
+
+	seen := make(map[string]bool, len(w.Items))
+	for idx, item := range w.Items {
+		prefix := fmt.Sprintf("items[%d]", idx)
+		if seen[item.ID] {
+			verr.Add(prefix+".id", shared.CodeWishlistItemsDuplicate, fmt.Sprintf("item %s appears more than once", item.ID))
+		}
+		seen[item.ID] = true
+
+		verr.Merge(prefix, shared.CodeWishlistItemInvalid, item.Validate())
+	}
+
+	return verr.ErrOrNil()
+}
+
+// IsListed returns true if the wishlist shows up in public search at now
+func (w *Wishlist) IsListed(now time.Time) bool {
+	return w.Visibility != VisibilityUnlisted && w.IsVisibleAt(now)
+}
+
+// IsVisibleAt returns true if donors can see the wishlist at now: it is
+// published, its publish time has come and it has not expired. Unlisted
+// wishlists are visible to donors who have the link.
+func (w *Wishlist) IsVisibleAt(now time.Time) bool {
+	if w.Status != WishlistPublished {
+		return false
+	}
+	if w.PublishAt != nil && now.Before(*w.PublishAt) {
+		return false
+	}
+	return w.ExpireAt == nil || now.Before(*w.ExpireAt)
+}
+
+// IsExpiredAt returns true if the wishlist is still open but its expiry has passed
+func (w *Wishlist) IsExpiredAt(now time.Time) bool {
+	open := w.Status == WishlistPublished || w.Status == WishlistPaused
+	return open && w.ExpireAt != nil && !now.Before(*w.ExpireAt)
+}
+
+// Publish makes the wishlist visible to donors from publishAt (or now, if nil)
+// until expireAt (or the end of that school year, if nil)
+func (w *Wishlist) Publish(publishAt, expireAt *time.Time, now time.Time) error {
+	start := now
+	if publishAt != nil {
+		start = *publishAt
+	}
+
+	end := SchoolYearEnd(start)
+	if expireAt != nil {
+		end = *expireAt
+	}
+
+	if !end.After(start) || !end.After(now) {
+		return shared.NewValidationError(shared.FieldError{
+			Field:   "expire_at",
+			Code:    shared.CodeWishlistExpireAtOrder,
+			Message: "expiry must be after the publish time and in the future",
+		})
+	}
+
+	if err := w.transition(WishlistPublished, now); err != nil {
+		return err
+	}
+
+	w.PublishAt = cloneTime(publishAt)
+	w.ExpireAt = &end
+	return nil
+}
+
+// Pause hides a published wishlist from donors
+func (w *Wishlist) Pause(now time.Time) error {
+	return w.transition(WishlistPaused, now)
+}
+
+// Archive closes the wishlist
+func (w *Wishlist) Archive(now time.Time) error {
+	if err := w.transition(WishlistArchived, now); err != nil {
+		return err
+	}
+
+	archivedAt := now
+	w.ArchivedAt = &archivedAt
+	return nil
+}
+
+// Reopen turns an archived wishlist back into a draft and clears its schedule
+func (w *Wishlist) Reopen(now time.Time) error {
+	if err := w.transition(WishlistDraft, now); err != nil {
+		return err
+	}
+
+	w.PublishAt, w.ExpireAt, w.ArchivedAt = nil, nil, nil
+	return nil
+}
+
+// transition moves the wishlist to the next lifecycle status
+func (w *Wishlist) transition(next WishlistStatus, now time.Time) error {
+	if !w.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidWishlistTransition, w.Status, next)
+	}
+
+	w.Status = next
+	w.UpdatedAt = now
+	return nil
+}
+
+// Item returns a pointer to the item with the given ID, or nil
+func (w *Wishlist) Item(itemID string) *WishlistItem {
+	for idx := range w.Items {
+		if w.Items[idx].ID == itemID {
+			return &w.Items[idx]
+		}
+	}
+	return nil
+}
+
+// OutstandingItems returns the items donors can still pledge, most urgent first
+// and then by name
+func (w *Wishlist) OutstandingItems() []WishlistItem {
+	outstanding := make([]WishlistItem, 0, len(w.Items))
+	for _, item := range w.Items {
+		if item.QuantityAvailable() > 0 {
+			outstanding = append(outstanding, item)
+		}
+	}
+
+	sort.SliceStable(outstanding, func(a, b int) bool {
+		if ra, rb := outstanding[a].Priority.rank(), outstanding[b].Priority.rank(); ra != rb {
+			return ra < rb
+		}
+		return outstanding[a].Name < outstanding[b].Name
+	})
+
+	return outstanding
+}
+
+// IsFulfilled returns true if every item has been fully received
+func (w *Wishlist) IsFulfilled() bool {
+	for _, item := range w.Items {
+		if !item.IsFulfilled() {
+			return false
+		}
+	}
+	return true
+}
+
+// Percent is a percentage with two decimal places, held in basis points so it is
+// exact: 4250 is 42.50%
+type Percent int64
+
+// String formats the percentage with two decimal places, e.g. "42.50"
+func (p Percent) String() string {
+	sign := ""
+	if p < 0 {
+		sign, p = "-", -p
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, p/100, p%100)
+}
+
+// MarshalJSON encodes the percentage as a JSON number with two decimal places
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Funding sums the cost of a wishlist's items in integer cents
+type Funding struct {
+	RequestedCents int64 `json:"requested_cents"`
+	FulfilledCents int64 `json:"fulfilled_cents"`
+	PledgedCents   int64 `json:"pledged_cents"`
+	// PercentFunded is the share of the requested cost that has been fulfilled or
+	// is held by pledges, rounded down so a list only shows 100% once it is
+	PercentFunded Percent `json:"percent_funded"`
+}
+
+// RemainingCents returns the cost of the units nobody has fulfilled or pledged
+func (f Funding) RemainingCents() int64 {
+	return f.RequestedCents - f.FulfilledCents - f.PledgedCents
+}
+
+// IsFullyFunded returns true if the list has a cost and all of it is fulfilled
+// or pledged
+func (f Funding) IsFullyFunded() bool {
+	return f.RequestedCents > 0 && f.RemainingCents() <= 0
+}
+
+// Funding computes the budget totals of the wishlist from its items. Items
+// without a price do not count towards the totals.
+func (w *Wishlist) Funding() Funding {
+	var funding Funding
+	for _, item := range w.Items {
+		funding.RequestedCents += int64(item.QuantityRequested) * item.UnitPriceCents
+		funding.FulfilledCents += int64(item.QuantityFulfilled) * item.UnitPriceCents
+		funding.PledgedCents += int64(item.QuantityPledged) * item.UnitPriceCents
+	}
+
+	if funding.RequestedCents > 0 {
+		funded := funding.FulfilledCents + funding.PledgedCents
+		funding.PercentFunded = Percent(funded * 10000 / funding.RequestedCents)
+	}
+	return funding
+}
+
+// MarshalJSON encodes the wishlist together with its computed Funding
+func (w Wishlist) MarshalJSON() ([]byte, error) {
+	type wishlist Wishlist
+	return json.Marshal(struct {
+		wishlist
+		Funding Funding `json:"funding"`
+	}{wishlist(w), w.Funding()})
+}
+
+// formatCents formats an amount of cents as dollars, e.g. "$1,299.00"
+func formatCents(cents int64) string {
+	dollars := strconv.FormatInt(cents/100, 10)
+	for idx := len(dollars) - 3; idx > 0; idx -= 3 {
+		dollars = dollars[:idx] + "," + dollars[idx:]
+	}
+	return fmt.Sprintf("$%s.%02d", dollars, cents%100)
+}
+
+// RevisionSource records which operation produced a wishlist revision
+type RevisionSource string
+
+const (
+	// RevisionCreated is the first revision of a wishlist
+	RevisionCreated RevisionSource = "created"
+	// RevisionUpdated is produced by UpdateWishlist
+	RevisionUpdated RevisionSource = "updated"
+	// RevisionRestored is produced by restoring an earlier revision
+	RevisionRestored RevisionSource = "restored"
+)
+
+// WishlistRevision is an immutable snapshot of a wishlist's editable content
+// after one edit. Item quantities are recorded as they were at that moment.
+type WishlistRevision struct {
+	WishlistID string         `json:"wishlist_id"`
+	Number     int            `json:"number"`
+	Source     RevisionSource `json:"source"`
+	// RestoredFrom is the revision number that was restored, for RevisionRestored
+	RestoredFrom int            `json:"restored_from,omitempty"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description,omitempty"`
+	Items        []WishlistItem `json:"items"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// newWishlistRevision snapshots the wishlist's current revision
+func newWishlistRevision(w *Wishlist, source RevisionSource, restoredFrom int) *WishlistRevision {
+	return &WishlistRevision{
+		WishlistID:   w.ID,
+		Number:       w.Revision,
+		Source:       source,
+		RestoredFrom: restoredFrom,
+		Title:        w.Title,
+		Description:  w.Description,
+		Items:        append([]WishlistItem{}, w.Items...),
+		CreatedAt:    w.UpdatedAt,
+	}
+}
+
+// FieldChange is one changed field between two revisions
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ItemChange lists the changed fields of an item present in both revisions
+type ItemChange struct {
+	ItemID  string        `json:"item_id"`
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// WishlistDiff describes how a wishlist changed between two revisions
+type WishlistDiff struct {
+	FromRevision int            `json:"from_revision"`
+	ToRevision   int            `json:"to_revision"`
+	Changes      []FieldChange  `json:"changes"`
+	AddedItems   []WishlistItem `json:"added_items"`
+	RemovedItems []WishlistItem `json:"removed_items"`
+	ChangedItems []ItemChange   `json:"changed_items"`
+}
+
+// IsEmpty returns true if the two revisions have the same content
+func (d WishlistDiff) IsEmpty() bool {
+	return len(d.Changes) == 0 && len(d.AddedItems) == 0 && len(d.RemovedItems) == 0 && len(d.ChangedItems) == 0
+}
+
+// DiffWishlistRevisions compares the editable content of two revisions. Items
+// are matched by ID; fulfilled and pledged quantities are not compared because
+// they change through donations rather than edits.
+func DiffWishlistRevisions(from, to WishlistRevision) WishlistDiff {
+	diff := WishlistDiff{
+		FromRevision: from.Number,
+		ToRevision:   to.Number,
+		Changes:      []FieldChange{},
+		AddedItems:   []WishlistItem{},
+		RemovedItems: []WishlistItem{},
+		ChangedItems: []ItemChange{},
+	}
+
+	diff.Changes = appendChange(diff.Changes, "title", from.Title, to.Title)
+	diff.Changes = appendChange(diff.Changes, "description", from.Description, to.Description)
+
+	before := make(map[string]WishlistItem, len(from.Items))
+	for _, item := range from.Items {
+		before[item.ID] = item
+	}
+
+	seen := make(map[string]bool, len(to.Items))
+	for _, item := range to.Items {
+		seen[item.ID] = true
+		old, ok := before[item.ID]
+		if !ok {
+			diff.AddedItems = append(diff.AddedItems, item)
+			continue
+		}
+
+		if changes := diffItems(old, item); len(changes) > 0 {
+			diff.ChangedItems = append(diff.ChangedItems, ItemChange{ItemID: item.ID, Name: item.Name, Changes: changes})
+		}
+	}
+
+	for _, item := range from.Items {
+		if !seen[item.ID] {
+			diff.RemovedItems = append(diff.RemovedItems, item)
+		}
+	}
+
+	return diff
+}
+
+// diffItems returns the changed editable fields of an item
+func diffItems(from, to WishlistItem) []FieldChange {
+	changes := []FieldChange{}
+	changes = appendChange(changes, "name", from.Name, to.Name)
+	changes = appendChange(changes, "quantity_requested",
+		strconv.Itoa(from.QuantityRequested), strconv.Itoa(to.QuantityRequested))
+	changes = appendChange(changes, "unit_price_cents",
+		strconv.FormatInt(from.UnitPriceCents, 10), strconv.FormatInt(to.UnitPriceCents, 10))
+	changes = appendChange(changes, "priority", string(from.Priority), string(to.Priority))
+	changes = appendChange(changes, "category", string(from.Category), string(to.Category))
+	changes = appendChange(changes, "product_url", from.ProductURL, to.ProductURL)
+	changes = appendChange(changes, "catalog_item_id", from.CatalogItemID, to.CatalogItemID)
+	return changes
+}
+
+// appendChange appends a FieldChange if the values differ
+func appendChange(changes []FieldChange, field, from, to string) []FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, From: from, To: to})
+}
+
+// PledgeStatus is the state of a donor's pledge
+type PledgeStatus string
+
+const (
+	// PledgeHeld reserves units for the donor until the hold expires
+	PledgeHeld PledgeStatus = "held"
+	// PledgeConfirmed means the teacher received the donation
+	PledgeConfirmed PledgeStatus = "confirmed"
+	// PledgeCancelled means the donor withdrew the pledge
+	PledgeCancelled PledgeStatus = "cancelled"
+	// PledgeExpired means the hold ran out before the teacher confirmed delivery
+	PledgeExpired PledgeStatus = "expired"
+)
+
+// IsFinal returns true once the pledge no longer holds any units
+func (s PledgeStatus) IsFinal() bool {
+	return s == PledgeConfirmed || s == PledgeCancelled || s == PledgeExpired
+}
+
+// Pledge is a donor's claim on some units of a wishlist item. While held, the
+// units are reserved so two donors do not buy the same thing.
+type Pledge struct {
+	ID         string       `json:"id"`
+	WishlistID string       `json:"wishlist_id"`
+	ItemID     string       `json:"item_id"`
+	DonorName  string       `json:"donor_name"`
+	DonorEmail string       `json:"donor_email"`
+	Quantity   int          `json:"quantity"`
+	Status     PledgeStatus `json:"status"`
+	CreatedAt  time.Time    `json:"created_at"`
+	// ExpiresAt is when an unconfirmed hold lapses
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Validate checks the donor details and quantity, returning a
+// *shared.ValidationError listing all problems
+func (p *Pledge) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if p.DonorName == "" {
+		verr.Add("donor_name", shared.CodePledgeDonorNameRequired, "donor name is required")
+	}
+
+	if addr, err := mail.ParseAddress(p.DonorEmail); err != nil || addr.Address != p.DonorEmail {
+		verr.Add("donor_email", shared.CodePledgeDonorEmailFormat, "donor email must be a valid email address")
+	}
+
+	if p.Quantity < 1 || p.Quantity > shared.MaxItemQuantity {
+		verr.Add("quantity", shared.CodePledgeQuantityRange,
+			fmt.Sprintf("pledged quantity must be between 1 and %d", shared.MaxItemQuantity))
+	}
+
+	return verr.ErrOrNil()
+}
+
+// IsHeld returns true if the pledge still reserves units
+func (p *Pledge) IsHeld() bool {
+	return p.Status == PledgeHeld
+}
+
+// HoldExpired returns true if the pledge is held but its hold ended at or before now
+func (p *Pledge) HoldExpired(now time.Time) bool {
+	return p.IsHeld() && !now.Before(p.ExpiresAt)
+}
+
+// resolve moves a held pledge to a final status
+func (p *Pledge) resolve(status PledgeStatus, at time.Time) error {
+	if !p.IsHeld() {
+		return fmt.Errorf("%w: pledge is %s", ErrPledgeNotHeld, p.Status)
+	}
+
+	resolvedAt := at
+	p.Status = status
+	p.ResolvedAt = &resolvedAt
+	return nil
+}
+
+// cloneTime returns a copy of an optional time
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// isProductURL returns true for absolute http and https URLs
+func isProductURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ValidationStatus is the verification status of a teacher
+type ValidationStatus string
+
+const (
+	// ValidationUnverified is the status of a newly registered teacher
+	ValidationUnverified ValidationStatus = "unverified"
+	// ValidationPendingReview means the teacher is waiting for an admin review
+	ValidationPendingReview ValidationStatus = "pending_review"
+	// ValidationVerified means an admin confirmed the teacher works at their school
+	ValidationVerified ValidationStatus = "verified"
+	// ValidationRejected means the review failed; the teacher may resubmit
+	ValidationRejected ValidationStatus = "rejected"
+	// ValidationSuspended means a verified teacher was suspended by an admin
+	ValidationSuspended ValidationStatus = "suspended"
+	// ValidationExpired means the verification lapsed and must be renewed
+	ValidationExpired ValidationStatus = "expired"
+)
+
+// validationTransitions lists the statuses reachable from each status
+var validationTransitions = map[ValidationStatus][]ValidationStatus{
+	ValidationUnverified:    {ValidationPendingReview},
+	ValidationPendingReview: {ValidationVerified, ValidationRejected},
+	// A verified teacher goes back to review when their school changes
+	ValidationVerified:  {ValidationPendingReview, ValidationSuspended, ValidationExpired},
+	ValidationRejected:  {ValidationPendingReview},
+	ValidationSuspended: {ValidationVerified, ValidationRejected},
+	ValidationExpired:   {ValidationPendingReview},
+}
+
+// IsValid returns true if the status is a known value
+func (s ValidationStatus) IsValid() bool {
+	_, ok := validationTransitions[s]
+	return ok
+}
+
+// CanTransitionTo returns true if the state machine allows moving to next
+func (s ValidationStatus) CanTransitionTo(next ValidationStatus) bool {
+	for _, allowed := range validationTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// requiresReason returns true for statuses that must be explained to the teacher
+func (s ValidationStatus) requiresReason() bool {
+	return s == ValidationRejected || s == ValidationSuspended
+}
+
+// ValidationTransition records one change of a teacher's validation status
+type ValidationTransition struct {
+	From ValidationStatus `json:"from"`
+	To   ValidationStatus `json:"to"`
+	// Actor is the ID of the admin or teacher who made the change, or "system"
+	Actor  string    `json:"actor"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// ValidationState is the verification status of a teacher together with the
+// history of every transition that led to it. This is a Value Object:
+// transitions return a new ValidationState.
+type ValidationState struct {
+	Status  ValidationStatus       `json:"status"`
+	History []ValidationTransition `json:"history"`
+}
+
+// NewValidationState returns the state of a newly registered teacher
+func NewValidationState() ValidationState {
+	return ValidationState{Status: ValidationUnverified, History: []ValidationTransition{}}
+}
+
+// TransitionTo returns the state after moving to next. Illegal transitions fail
+// with an error wrapping ErrInvalidValidationTransition. An actor is always
+// required, and a reason is required when rejecting or suspending.
+func (s ValidationState) TransitionTo(next ValidationStatus, actor, reason string,
+	at time.Time) (ValidationState, error) {
+	actor = strings.TrimSpace(actor)
+	reason = strings.TrimSpace(reason)
+
+	verr := &shared.ValidationError{}
+	if actor == "" {
+		verr.Add("actor", shared.CodeValidationActorRequired, "the actor making the change is required")
+	}
+	if reason == "" && next.requiresReason() {
+		verr.Add("reason", shared.CodeValidationReasonRequired,
+			fmt.Sprintf("a reason is required to mark a teacher %s", next))
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return s, err
+	}
+
+	if !s.Status.CanTransitionTo(next) {
+		return s, fmt.Errorf("%w: %s to %s", ErrInvalidValidationTransition, s.Status, next)
+	}
+
+	history := make([]ValidationTransition, len(s.History), len(s.History)+1)
+	copy(history, s.History)
+	history = append(history, ValidationTransition{
+		From:   s.Status,
+		To:     next,
+		Actor:  actor,
+		Reason: reason,
+		At:     at,
+	})
+
+	return ValidationState{Status: next, History: history}, nil
+}
+
+// IsVerified returns true if the teacher is currently verified
+func (s ValidationState) IsVerified() bool {
+	return s.Status == ValidationVerified
+}
+
+// LastTransition returns the most recent transition, if any
+func (s ValidationState) LastTransition() (ValidationTransition, bool) {
+	if len(s.History) == 0 {
+		return ValidationTransition{}, false
+	}
+	return s.History[len(s.History)-1], true
+}
+
+// SchoolTransfer records a teacher moving from one school to another
+type SchoolTransfer struct {
+	FromSchoolID string `json:"from_school_id"`
+	ToSchoolID   string `json:"to_school_id"`
+	// Actor is the ID of the admin or teacher who made the change
+	Actor string    `json:"actor"`
+	At    time.Time `json:"at"`
+}
+
+// Teacher is a registered teacher at a school
+type Teacher struct {
+	ID              string          `json:"id"`
+	SchoolID        string          `json:"school_id"`
+	Name            string          `json:"name"`
+	Email           string          `json:"email"`
+	ValidationState ValidationState `json:"validation_state"`
+	// PrimaryWishlistID is the wishlist featured on the teacher's public profile
+	PrimaryWishlistID string `json:"primary_wishlist_id,omitempty"`
+	// SchoolHistory lists every school the teacher transferred away from, oldest
+	// first
+	SchoolHistory []SchoolTransfer `json:"school_history,omitempty"`
+	// Version is incremented on every update and used for optimistic locking
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the teacher's fields, returning a *shared.ValidationError
+// listing all problems
+func (t *Teacher) Validate() error {
+	verr := &shared.ValidationError{}
+
+	if strings.TrimSpace(t.SchoolID) == "" {
+		verr.Add("school_id", shared.CodeTeacherSchoolRequired, "school is required")
+	}
+
+	if t.Name == "" {
+		verr.Add("name", shared.CodeTeacherNameRequired, "name is required")
+	}
+
+	if addr, err := mail.ParseAddress(t.Email); err != nil || addr.Address != t.Email {
+		verr.Add("email", shared.CodeTeacherEmailFormat, "email must be a valid email address")
+	}
+
+	if !t.ValidationState.Status.IsValid() {
+		verr.Add("validation_state", shared.CodeTeacherValidationStatus,
+			fmt.Sprintf("unknown validation status %q", t.ValidationState.Status))
+	}
+
+	return verr.ErrOrNil()
+}
+
+// Transition moves the teacher's validation state, leaving the teacher unchanged
+// on error
+func (t *Teacher) Transition(next ValidationStatus, actor, reason string, at time.Time) error {
+	state, err := t.ValidationState.TransitionTo(next, actor, reason, at)
+	if err != nil {
+		return err
+	}
+
+	t.ValidationState = state
+	t.UpdatedAt = at
+	return nil
+}
+
+// TransferTo moves the teacher to another school. The old school is kept in
+// SchoolHistory and the teacher goes back to review, since verification vouches
+// for employment at one school. Suspended teachers cannot transfer. The teacher
+// is left unchanged on error.
+func (t *Teacher) TransferTo(schoolID, actor string, at time.Time) error {
+	schoolID = strings.TrimSpace(schoolID)
+	actor = strings.TrimSpace(actor)
+
+	verr := &shared.ValidationError{}
+	if schoolID == "" {
+		verr.Add("school_id", shared.CodeTeacherSchoolRequired, "school is required")
+	} else if schoolID == t.SchoolID {
+		verr.Add("school_id", shared.CodeTeacherSchoolUnchanged, "the teacher already works at this school")
+	}
+	if actor == "" {
+		verr.Add("actor", shared.CodeValidationActorRequired, "the actor making the change is required")
+	}
+	if err := verr.ErrOrNil(); err != nil {
+		return err
+	}
+
+	state := t.ValidationState
+	if state.Status != ValidationPendingReview {
+		var err error
+		state, err = state.TransitionTo(ValidationPendingReview, actor, "transferred to school "+schoolID, at)
+		if err != nil {
+			return err
+		}
+	}
+
+	history := make([]SchoolTransfer, len(t.SchoolHistory), len(t.SchoolHistory)+1)
+	copy(history, t.SchoolHistory)
+	t.SchoolHistory = append(history, SchoolTransfer{
+		FromSchoolID: t.SchoolID,
+		ToSchoolID:   schoolID,
+		Actor:        actor,
+		At:           at,
+	})
+	t.ValidationState = state
+	t.SchoolID = schoolID
+	t.UpdatedAt = at
+	return nil
+}
+
+// MoveToMergedSchool points the teacher at the school their school was merged
+// into as a duplicate. Both records stand for the same school, so unlike
+// TransferTo the teacher keeps their validation status; the move is still kept
+// in SchoolHistory.
+func (t *Teacher) MoveToMergedSchool(schoolID, actor string, at time.Time) {
+	if schoolID == t.SchoolID {
+		return
+	}
+
+	history := make([]SchoolTransfer, len(t.SchoolHistory), len(t.SchoolHistory)+1)
+	copy(history, t.SchoolHistory)
+	t.SchoolHistory = append(history, SchoolTransfer{
+		FromSchoolID: t.SchoolID,
+		ToSchoolID:   schoolID,
+		Actor:        actor,
+		At:           at,
+	})
+	t.SchoolID = schoolID
+	t.UpdatedAt = at
+}
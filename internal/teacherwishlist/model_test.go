@@ -0,0 +1,765 @@
+package teacherwishlist
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+func TestNewWishlistItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		itemName  string
+		quantity  int
+		price     int64
+		priority  ItemPriority
+		category  ItemCategory
+		url       string
+		wantCodes []string
+	}{
+		{
+			name:     "valid item",
+			itemName: "Crayola crayons, 24 count",
+			quantity: 24,
+			price:    149,
+			priority: PriorityHigh,
+			category: CategoryArt,
+			url:      "https://example.com/crayons",
+		},
+		{
+			name:     "defaults for empty priority and category",
+			itemName: "Glue sticks",
+			quantity: 10,
+		},
+		{
+			name:      "missing name",
+			quantity:  1,
+			wantCodes: []string{shared.CodeItemNameRequired},
+		},
+		{
+			name:      "name too long",
+			itemName:  strings.Repeat("x", shared.MaxItemNameLength+1),
+			quantity:  1,
+			wantCodes: []string{shared.CodeItemNameLength},
+		},
+		{
+			name:      "zero quantity",
+			itemName:  "Pencils",
+			wantCodes: []string{shared.CodeItemQuantityRange},
+		},
+		{
+			name:      "quantity over limit",
+			itemName:  "Pencils",
+			quantity:  shared.MaxItemQuantity + 1,
+			wantCodes: []string{shared.CodeItemQuantityRange},
+		},
+		{
+			name:      "every field invalid",
+			quantity:  -1,
+			price:     -5,
+			priority:  "critical",
+			category:  "snacks",
+			url:       "ftp://example.com/file",
+			wantCodes: []string{shared.CodeItemNameRequired, shared.CodeItemQuantityRange, shared.CodeItemUnitPriceNegative, shared.CodeItemPriorityInvalid, shared.CodeItemCategoryInvalid, shared.CodeItemProductURLFormat},
+		},
+		{
+			name:      "relative URL",
+			itemName:  "Books",
+			quantity:  1,
+			url:       "/books/123",
+			wantCodes: []string{shared.CodeItemProductURLFormat},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := NewWishlistItem(tt.itemName, tt.quantity, tt.price, tt.priority, tt.category, tt.url)
+
+			if len(tt.wantCodes) == 0 {
+				if err != nil {
+					t.Fatalf("NewWishlistItem() unexpected error = %v", err)
+				}
+				if item.ID == "" {
+					t.Error("NewWishlistItem() did not assign an ID")
+				}
+				if tt.priority == "" && item.Priority != PriorityMedium {
+					t.Errorf("Priority = %q, want default %q", item.Priority, PriorityMedium)
+				}
+				if tt.category == "" && item.Category != CategoryOther {
+					t.Errorf("Category = %q, want default %q", item.Category, CategoryOther)
+				}
+				return
+			}
+
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("NewWishlistItem() error = %v, want *shared.ValidationError", err)
+			}
+			if len(verr.Fields) != len(tt.wantCodes) {
+				t.Fatalf("got %d field errors %+v, want codes %v", len(verr.Fields), verr.Fields, tt.wantCodes)
+			}
+			for _, code := range tt.wantCodes {
+				if !verr.HasCode(code) {
+					t.Errorf("missing code %s in %+v", code, verr.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistItem_Fulfill(t *testing.T) {
+	item, err := NewWishlistItem("Markers", 5, 399, PriorityMedium, CategoryArt, "")
+	if err != nil {
+		t.Fatalf("NewWishlistItem() error = %v", err)
+	}
+
+	if err := item.Fulfill(3); err != nil {
+		t.Fatalf("Fulfill(3) error = %v", err)
+	}
+	if item.QuantityFulfilled != 3 || item.QuantityRemaining() != 2 {
+		t.Errorf("after Fulfill(3): fulfilled = %d, remaining = %d", item.QuantityFulfilled, item.QuantityRemaining())
+	}
+
+	if err := item.Fulfill(3); err == nil {
+		t.Error("Fulfill(3) with 2 remaining should fail")
+	}
+	if err := item.Fulfill(0); err == nil {
+		t.Error("Fulfill(0) should fail")
+	}
+	if item.QuantityFulfilled != 3 {
+		t.Errorf("failed Fulfill changed QuantityFulfilled to %d", item.QuantityFulfilled)
+	}
+
+	if err := item.Fulfill(2); err != nil {
+		t.Fatalf("Fulfill(2) error = %v", err)
+	}
+	if !item.IsFulfilled() {
+		t.Error("IsFulfilled() = false after fulfilling every unit")
+	}
+}
+
+func TestWishlistItem_ValidateFulfilledInvariant(t *testing.T) {
+	item, _ := NewWishlistItem("Markers", 5, 399, PriorityMedium, CategoryArt, "")
+	item.QuantityFulfilled = 6
+
+	var verr *shared.ValidationError
+	if !errors.As(item.Validate(), &verr) || !verr.HasCode(shared.CodeItemQuantityBelowFilled) {
+		t.Errorf("Validate() = %v, want %s", verr, shared.CodeItemQuantityBelowFilled)
+	}
+
+	item.QuantityFulfilled = -1
+	if !errors.As(item.Validate(), &verr) || !verr.HasCode(shared.CodeItemFulfilledRange) {
+		t.Errorf("Validate() = %v, want %s", verr, shared.CodeItemFulfilledRange)
+	}
+}
+
+func TestWishlist_Validate(t *testing.T) {
+	good, _ := NewWishlistItem("Pencils", 10, 25, PriorityLow, CategorySupplies, "")
+	bad := good
+	bad.ID = "bad"
+	bad.Name = ""
+
+	wishlist := &Wishlist{
+		TeacherID: "",
+		Title:     strings.Repeat("t", shared.MaxWishlistTitleLength+1),
+		Items:     []WishlistItem{good, bad, good},
+	}
+
+	var verr *shared.ValidationError
+	if !errors.As(wishlist.Validate(), &verr) {
+		t.Fatal("Validate() expected *shared.ValidationError")
+	}
+
+	want := map[string]string{
+		"teacher_id":    shared.CodeWishlistTeacherRequired,
+		"title":         shared.CodeWishlistTitleLength,
+		"items[1].name": shared.CodeItemNameRequired,
+		"items[2].id":   shared.CodeWishlistItemsDuplicate,
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("got %+v, want %v", verr.Fields, want)
+	}
+	for _, field := range verr.Fields {
+		if want[field.Field] != field.Code {
+			t.Errorf("unexpected field error %+v", field)
+		}
+	}
+}
+
+func TestWishlist_OutstandingItems(t *testing.T) {
+	paper, _ := NewWishlistItem("Paper", 2, 500, PriorityLow, CategorySupplies, "")
+	books, _ := NewWishlistItem("Books", 3, 800, PriorityHigh, CategoryBooks, "")
+	art, _ := NewWishlistItem("Art kit", 1, 2000, PriorityHigh, CategoryArt, "")
+	done, _ := NewWishlistItem("Tissues", 1, 300, PriorityHigh, CategoryHygiene, "")
+	done.QuantityFulfilled = 1
+
+	wishlist := &Wishlist{Items: []WishlistItem{paper, books, done, art}}
+
+	outstanding := wishlist.OutstandingItems()
+	names := make([]string, 0, len(outstanding))
+	for _, item := range outstanding {
+		names = append(names, item.Name)
+	}
+
+	if got, want := strings.Join(names, ","), "Art kit,Books,Paper"; got != want {
+		t.Errorf("OutstandingItems() = %s, want %s", got, want)
+	}
+	if wishlist.IsFulfilled() {
+		t.Error("IsFulfilled() = true with outstanding items")
+	}
+	if wishlist.Item(done.ID) == nil || wishlist.Item("missing") != nil {
+		t.Error("Item() lookup by ID failed")
+	}
+}
+
+func TestWishlistItem_ReserveAndSettle(t *testing.T) {
+	item, _ := NewWishlistItem("Markers", 5, 399, PriorityMedium, CategoryArt, "")
+
+	if err := item.reserve(3); err != nil {
+		t.Fatalf("reserve(3) error = %v", err)
+	}
+	if err := item.reserve(3); !errors.Is(err, ErrInsufficientQuantity) {
+		t.Errorf("reserve(3) with 2 available error = %v", err)
+	}
+	if err := item.Fulfill(3); err == nil {
+		t.Error("Fulfill() should not consume units held by pledges")
+	}
+
+	item.settle(2, PledgeConfirmed)
+	item.settle(1, PledgeExpired)
+	if item.QuantityPledged != 0 || item.QuantityFulfilled != 2 || item.QuantityAvailable() != 3 {
+		t.Errorf("after settle: %+v", item)
+	}
+}
+
+func TestPledge_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		pledge    Pledge
+		wantCodes []string
+	}{
+		{
+			name:   "valid pledge",
+			pledge: Pledge{DonorName: "Pat", DonorEmail: "pat@example.com", Quantity: 2},
+		},
+		{
+			name:      "missing donor",
+			pledge:    Pledge{Quantity: 1},
+			wantCodes: []string{shared.CodePledgeDonorNameRequired, shared.CodePledgeDonorEmailFormat},
+		},
+		{
+			name:      "display name is not a bare email",
+			pledge:    Pledge{DonorName: "Pat", DonorEmail: "Pat <pat@example.com>", Quantity: 1},
+			wantCodes: []string{shared.CodePledgeDonorEmailFormat},
+		},
+		{
+			name:      "zero quantity",
+			pledge:    Pledge{DonorName: "Pat", DonorEmail: "pat@example.com"},
+			wantCodes: []string{shared.CodePledgeQuantityRange},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pledge.Validate()
+			if len(tt.wantCodes) == 0 {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var verr *shared.ValidationError
+			if !errors.As(err, &verr) || len(verr.Fields) != len(tt.wantCodes) {
+				t.Fatalf("Validate() = %v, want codes %v", err, tt.wantCodes)
+			}
+			for _, code := range tt.wantCodes {
+				if !verr.HasCode(code) {
+					t.Errorf("missing code %s", code)
+				}
+			}
+		})
+	}
+}
+
+func TestPledge_HoldExpired(t *testing.T) {
+	expires := time.Date(2025, time.October, 1, 12, 0, 0, 0, time.UTC)
+	pledge := Pledge{Status: PledgeHeld, ExpiresAt: expires}
+
+	if pledge.HoldExpired(expires.Add(-time.Second)) {
+		t.Error("HoldExpired() before ExpiresAt = true")
+	}
+	if !pledge.HoldExpired(expires) {
+		t.Error("HoldExpired() at ExpiresAt = false")
+	}
+
+	if err := pledge.resolve(PledgeConfirmed, expires); err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if pledge.HoldExpired(expires.Add(time.Hour)) || !pledge.Status.IsFinal() {
+		t.Error("a confirmed pledge cannot expire")
+	}
+	if err := pledge.resolve(PledgeCancelled, expires); !errors.Is(err, ErrPledgeNotHeld) {
+		t.Errorf("resolve() on confirmed pledge error = %v", err)
+	}
+}
+
+func TestValidationState_TransitionTo(t *testing.T) {
+	at := time.Date(2025, time.August, 20, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		from    ValidationStatus
+		to      ValidationStatus
+		reason  string
+		wantErr error
+	}{
+		{name: "submit for review", from: ValidationUnverified, to: ValidationPendingReview},
+		{name: "verify", from: ValidationPendingReview, to: ValidationVerified},
+		{name: "reject with reason", from: ValidationPendingReview, to: ValidationRejected, reason: "school email not confirmed"},
+		{name: "resubmit after rejection", from: ValidationRejected, to: ValidationPendingReview},
+		{name: "suspend", from: ValidationVerified, to: ValidationSuspended, reason: "donor complaint"},
+		{name: "reinstate", from: ValidationSuspended, to: ValidationVerified},
+		{name: "expire", from: ValidationVerified, to: ValidationExpired},
+		{name: "renew after expiry", from: ValidationExpired, to: ValidationPendingReview},
+		{name: "unverified cannot skip review", from: ValidationUnverified, to: ValidationVerified, wantErr: ErrInvalidValidationTransition},
+		{name: "rejected cannot be verified directly", from: ValidationRejected, to: ValidationVerified, wantErr: ErrInvalidValidationTransition},
+		{name: "expired cannot be suspended", from: ValidationExpired, to: ValidationSuspended, reason: "x", wantErr: ErrInvalidValidationTransition},
+		{name: "same status", from: ValidationVerified, to: ValidationVerified, wantErr: ErrInvalidValidationTransition},
+		{name: "unknown status", from: ValidationPendingReview, to: "approved", wantErr: ErrInvalidValidationTransition},
+		{name: "reject without reason", from: ValidationPendingReview, to: ValidationRejected, wantErr: shared.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := ValidationState{Status: tt.from, History: []ValidationTransition{}}
+
+			next, err := state.TransitionTo(tt.to, "admin-7", tt.reason, at)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("TransitionTo() error = %v, want %v", err, tt.wantErr)
+				}
+				if next.Status != tt.from || len(next.History) != 0 {
+					t.Errorf("failed transition changed the state to %+v", next)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("TransitionTo() unexpected error = %v", err)
+			}
+			want := ValidationTransition{From: tt.from, To: tt.to, Actor: "admin-7", Reason: tt.reason, At: at}
+			if last, ok := next.LastTransition(); next.Status != tt.to || !ok || last != want {
+				t.Errorf("TransitionTo() = %+v, want last transition %+v", next, want)
+			}
+			if len(state.History) != 0 {
+				t.Error("TransitionTo() modified the original state")
+			}
+		})
+	}
+}
+
+func TestValidationState_RequiresActor(t *testing.T) {
+	_, err := NewValidationState().TransitionTo(ValidationPendingReview, " ", "", time.Now())
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeValidationActorRequired) {
+		t.Errorf("TransitionTo() without actor error = %v", err)
+	}
+}
+
+func TestValidationState_History(t *testing.T) {
+	start := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	state := NewValidationState()
+
+	steps := []struct {
+		to     ValidationStatus
+		actor  string
+		reason string
+	}{
+		{ValidationPendingReview, "teacher-1", ""},
+		{ValidationRejected, "admin-1", "could not confirm employment"},
+		{ValidationPendingReview, "teacher-1", "uploaded staff ID"},
+		{ValidationVerified, "admin-2", ""},
+	}
+
+	for i, step := range steps {
+		var err error
+		state, err = state.TransitionTo(step.to, step.actor, step.reason, start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	if !state.IsVerified() || len(state.History) != len(steps) {
+		t.Fatalf("state = %+v", state)
+	}
+	if state.History[1].Reason != "could not confirm employment" || state.History[3].From != ValidationPendingReview {
+		t.Errorf("history = %+v", state.History)
+	}
+}
+
+func TestDiffWishlistRevisions_NoChanges(t *testing.T) {
+	item, _ := NewWishlistItem("Pencils", 10, 25, PriorityLow, CategorySupplies, "")
+	from := WishlistRevision{Number: 1, Title: "Supplies", Items: []WishlistItem{item}}
+
+	item.QuantityFulfilled = 4
+	to := WishlistRevision{Number: 2, Title: "Supplies", Items: []WishlistItem{item}}
+
+	diff := DiffWishlistRevisions(from, to)
+	if !diff.IsEmpty() || diff.FromRevision != 1 || diff.ToRevision != 2 {
+		t.Errorf("DiffWishlistRevisions() = %+v, want empty diff", diff)
+	}
+}
+
+func TestSchoolYearEnd(t *testing.T) {
+	tests := []struct {
+		at   time.Time
+		want time.Time
+	}{
+		{time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC), time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, time.July, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := SchoolYearEnd(tt.at); !got.Equal(tt.want) {
+			t.Errorf("SchoolYearEnd(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestWishlistStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to WishlistStatus
+		want     bool
+	}{
+		{WishlistDraft, WishlistPublished, true},
+		{WishlistDraft, WishlistPaused, false},
+		{WishlistPublished, WishlistPaused, true},
+		{WishlistPaused, WishlistPublished, true},
+		{WishlistPublished, WishlistDraft, false},
+		{WishlistArchived, WishlistPublished, false},
+		{WishlistArchived, WishlistDraft, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestWishlist_Publish(t *testing.T) {
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+
+	t.Run("defaults expiry to the end of the school year", func(t *testing.T) {
+		w := Wishlist{Status: WishlistDraft}
+		if err := w.Publish(nil, nil, now); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if w.Status != WishlistPublished || w.PublishAt != nil || !w.ExpireAt.Equal(SchoolYearEnd(now)) {
+			t.Errorf("wishlist = %+v", w)
+		}
+		if !w.IsVisibleAt(now) || w.IsVisibleAt(*w.ExpireAt) {
+			t.Error("IsVisibleAt() does not follow the schedule")
+		}
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		publishAt := now.Add(7 * 24 * time.Hour)
+		w := Wishlist{Status: WishlistDraft}
+		if err := w.Publish(&publishAt, nil, now); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if w.IsVisibleAt(now) || !w.IsVisibleAt(publishAt) {
+			t.Error("a scheduled wishlist is visible only from its publish time")
+		}
+	})
+
+	t.Run("expiry before publish time", func(t *testing.T) {
+		publishAt := now.Add(48 * time.Hour)
+		expireAt := now.Add(24 * time.Hour)
+		w := Wishlist{Status: WishlistDraft}
+
+		err := w.Publish(&publishAt, &expireAt, now)
+		var verr *shared.ValidationError
+		if !errors.As(err, &verr) || !verr.HasCode(shared.CodeWishlistExpireAtOrder) {
+			t.Errorf("Publish() error = %v, want %s", err, shared.CodeWishlistExpireAtOrder)
+		}
+		if w.Status != WishlistDraft {
+			t.Errorf("Status = %s, want unchanged", w.Status)
+		}
+	})
+
+	t.Run("archived", func(t *testing.T) {
+		w := Wishlist{Status: WishlistArchived}
+		if err := w.Publish(nil, nil, now); !errors.Is(err, ErrInvalidWishlistTransition) {
+			t.Errorf("Publish() error = %v, want ErrInvalidWishlistTransition", err)
+		}
+	})
+}
+
+func TestWishlist_ArchiveAndReopen(t *testing.T) {
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+	w := Wishlist{Status: WishlistDraft}
+	if err := w.Publish(nil, nil, now); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := w.Pause(now); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if w.IsVisibleAt(now) || w.IsExpiredAt(now) || !w.IsExpiredAt(*w.ExpireAt) {
+		t.Errorf("paused wishlist = %+v", w)
+	}
+
+	if err := w.Archive(now); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if w.ArchivedAt == nil || !w.ArchivedAt.Equal(now) || w.IsExpiredAt(*w.ExpireAt) {
+		t.Errorf("archived wishlist = %+v", w)
+	}
+
+	if err := w.Reopen(now); err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if w.Status != WishlistDraft || w.ExpireAt != nil || w.ArchivedAt != nil {
+		t.Errorf("reopened wishlist = %+v", w)
+	}
+}
+
+func TestWishlist_Funding(t *testing.T) {
+	w := Wishlist{Items: []WishlistItem{
+		{QuantityRequested: 30, QuantityFulfilled: 10, QuantityPledged: 5, UnitPriceCents: 59},
+		{QuantityRequested: 3, QuantityPledged: 1, UnitPriceCents: 1299},
+		{QuantityRequested: 100, QuantityFulfilled: 40},
+	}}
+
+	funding := w.Funding()
+	want := Funding{RequestedCents: 5667, FulfilledCents: 590, PledgedCents: 1594, PercentFunded: 3853}
+	if funding != want {
+		t.Errorf("Funding() = %+v, want %+v", funding, want)
+	}
+	if funding.RemainingCents() != 3483 || funding.IsFullyFunded() {
+		t.Errorf("RemainingCents() = %d, IsFullyFunded() = %v", funding.RemainingCents(), funding.IsFullyFunded())
+	}
+
+	// Rounding down keeps an almost funded list below 100%
+	w = Wishlist{Items: []WishlistItem{{QuantityRequested: 10000, QuantityFulfilled: 9999, UnitPriceCents: 1}}}
+	if got := w.Funding().PercentFunded; got != 9999 {
+		t.Errorf("PercentFunded = %s, want 99.99", got)
+	}
+
+	if funding := (&Wishlist{}).Funding(); funding.PercentFunded != 0 || funding.IsFullyFunded() {
+		t.Errorf("empty wishlist Funding() = %+v", funding)
+	}
+}
+
+func TestPercent_String(t *testing.T) {
+	tests := map[Percent]string{0: "0.00", 5: "0.05", 4250: "42.50", 10000: "100.00", -125: "-1.25"}
+	for percent, want := range tests {
+		if got := percent.String(); got != want {
+			t.Errorf("Percent(%d).String() = %q, want %q", int64(percent), got, want)
+		}
+	}
+}
+
+func TestWishlist_MarshalJSON_Funding(t *testing.T) {
+	w := &Wishlist{ID: "w-1", Title: "Supplies", Items: []WishlistItem{
+		{ID: "i-1", QuantityRequested: 4, QuantityFulfilled: 1, UnitPriceCents: 250},
+	}}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded struct {
+		ID      string          `json:"id"`
+		Funding json.RawMessage `json:"funding"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	wantFunding := `{"requested_cents":1000,"fulfilled_cents":250,"pledged_cents":0,"percent_funded":25.00}`
+	if decoded.ID != "w-1" || string(decoded.Funding) != wantFunding {
+		t.Errorf("Marshal() = %s", data)
+	}
+}
+
+func TestWishlistItem_ValidateUnitPriceMax(t *testing.T) {
+	_, err := NewWishlistItem("Smartboard", 1, shared.MaxItemUnitPriceCents+1, PriorityHigh, CategoryTechnology, "")
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeItemUnitPriceMax) {
+		t.Fatalf("NewWishlistItem() error = %v, want %s", err, shared.CodeItemUnitPriceMax)
+	}
+	if verr.Fields[0].Message != "unit price cannot exceed $1,000,000.00" {
+		t.Errorf("message = %q", verr.Fields[0].Message)
+	}
+}
+
+func TestGradeLevel_IsValid(t *testing.T) {
+	for _, grade := range []GradeLevel{GradePreK, GradeKindergarten, "1", "9", "12", GradeMixed} {
+		if !grade.IsValid() {
+			t.Errorf("GradeLevel(%q).IsValid() = false", grade)
+		}
+	}
+	for _, grade := range []GradeLevel{"", "0", "13", "07", "+3", "first"} {
+		if grade.IsValid() {
+			t.Errorf("GradeLevel(%q).IsValid() = true", grade)
+		}
+	}
+}
+
+func TestGradeLevel_SchoolGrade(t *testing.T) {
+	tests := []struct {
+		level GradeLevel
+		grade schooldirectory.Grade
+	}{
+		{level: GradePreK, grade: schooldirectory.GradePreK},
+		{level: GradeKindergarten, grade: schooldirectory.GradeKindergarten},
+		{level: "1", grade: "01"},
+		{level: "9", grade: "09"},
+		{level: "12", grade: "12"},
+	}
+
+	for _, tt := range tests {
+		grade, ok := tt.level.SchoolGrade()
+		if !ok || grade != tt.grade {
+			t.Errorf("GradeLevel(%q).SchoolGrade() = %q, %v, want %q", tt.level, grade, ok, tt.grade)
+		}
+		level, ok := GradeLevelOf(tt.grade)
+		if !ok || level != tt.level {
+			t.Errorf("GradeLevelOf(%q) = %q, %v, want %q", tt.grade, level, ok, tt.level)
+		}
+	}
+
+	for _, level := range []GradeLevel{GradeMixed, "", "13", "07"} {
+		if grade, ok := level.SchoolGrade(); ok {
+			t.Errorf("GradeLevel(%q).SchoolGrade() = %q, want no grade", level, grade)
+		}
+	}
+	if level, ok := GradeLevelOf(schooldirectory.GradeUngraded); !ok || level != GradeMixed {
+		t.Errorf("GradeLevelOf(UG) = %q, %v, want %q", level, ok, GradeMixed)
+	}
+	if level, ok := GradeLevelOf("7"); ok {
+		t.Errorf("GradeLevelOf(\"7\") = %q, want no level", level)
+	}
+
+	// Every grade a school can span has a grade level
+	for _, grade := range schooldirectory.RankedGrades() {
+		level, ok := GradeLevelOf(grade)
+		if back, _ := level.SchoolGrade(); !ok || back != grade {
+			t.Errorf("GradeLevelOf(%q) = %q does not map back", grade, level)
+		}
+	}
+}
+
+func TestWishlist_ValidateScope(t *testing.T) {
+	w := Wishlist{
+		TeacherID: "teacher-1",
+		Title:     "Period 3 chemistry",
+		WishlistScope: WishlistScope{
+			GradeLevel:   "13",
+			Subject:      strings.Repeat("s", shared.MaxWishlistSubjectLength+1),
+			StudentCount: -1,
+			Visibility:   "friends",
+		},
+	}
+
+	err := w.Validate()
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want ValidationError", err)
+	}
+	for _, code := range []string{shared.CodeWishlistGradeLevel, shared.CodeWishlistSubjectLength,
+		shared.CodeWishlistStudentCount, shared.CodeWishlistVisibility} {
+		if !verr.HasCode(code) {
+			t.Errorf("Validate() is missing %s: %v", code, err)
+		}
+	}
+
+	w.WishlistScope = WishlistScope{GradeLevel: "10", Subject: "Chemistry", StudentCount: 28}
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, an empty visibility means public", err)
+	}
+}
+
+func TestWishlist_IsListed(t *testing.T) {
+	now := time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+	w := Wishlist{Status: WishlistPublished, WishlistScope: WishlistScope{Visibility: VisibilityPublic}}
+	if !w.IsListed(now) {
+		t.Error("IsListed() = false for a public published wishlist")
+	}
+
+	w.Visibility = VisibilityUnlisted
+	if w.IsListed(now) || !w.IsVisibleAt(now) {
+		t.Error("an unlisted wishlist is visible by link but not listed")
+	}
+}
+
+func TestTeacher_TransferTo(t *testing.T) {
+	at := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	teacher := &Teacher{ID: "teacher-1", SchoolID: "school-1", ValidationState: NewValidationState()}
+	state, _ := teacher.ValidationState.TransitionTo(ValidationPendingReview, "teacher-1", "", at)
+	teacher.ValidationState, _ = state.TransitionTo(ValidationVerified, "admin-1", "", at)
+
+	if err := teacher.TransferTo("school-2", "admin-1", at); err != nil {
+		t.Fatalf("TransferTo() error = %v", err)
+	}
+	if teacher.SchoolID != "school-2" || teacher.ValidationState.Status != ValidationPendingReview {
+		t.Errorf("teacher = %+v, want pending review at school-2", teacher)
+	}
+	if last, _ := teacher.ValidationState.LastTransition(); last.Reason != "transferred to school school-2" {
+		t.Errorf("LastTransition() = %+v", last)
+	}
+
+	// A teacher already waiting for review stays pending and keeps every move
+	if err := teacher.TransferTo("school-3", "admin-1", at.Add(time.Hour)); err != nil {
+		t.Fatalf("TransferTo() while pending error = %v", err)
+	}
+	if len(teacher.ValidationState.History) != 3 || len(teacher.SchoolHistory) != 2 {
+		t.Errorf("teacher = %+v, want 3 transitions and 2 transfers", teacher)
+	}
+	if teacher.SchoolHistory[1].FromSchoolID != "school-2" || teacher.SchoolHistory[1].ToSchoolID != "school-3" {
+		t.Errorf("SchoolHistory[1] = %+v", teacher.SchoolHistory[1])
+	}
+}
+
+func TestTeacher_TransferToErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		schoolID string
+		actor    string
+		status   ValidationStatus
+		wantErr  error
+		wantCode string
+	}{
+		{"missing school", " ", "admin-1", ValidationVerified, shared.ErrValidation, shared.CodeTeacherSchoolRequired},
+		{"same school", "school-1", "admin-1", ValidationVerified, shared.ErrValidation, shared.CodeTeacherSchoolUnchanged},
+		{"missing actor", "school-2", "", ValidationPendingReview, shared.ErrValidation, shared.CodeValidationActorRequired},
+		{"suspended", "school-2", "admin-1", ValidationSuspended, ErrInvalidValidationTransition, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teacher := &Teacher{SchoolID: "school-1", ValidationState: ValidationState{Status: tt.status}}
+			err := teacher.TransferTo(tt.schoolID, tt.actor, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransferTo() error = %v, want %v", err, tt.wantErr)
+			}
+			var verr *shared.ValidationError
+			if tt.wantCode != "" && (!errors.As(err, &verr) || !verr.HasCode(tt.wantCode)) {
+				t.Errorf("TransferTo() error = %v, want %s", err, tt.wantCode)
+			}
+			if teacher.SchoolID != "school-1" || len(teacher.SchoolHistory) != 0 || teacher.ValidationState.Status != tt.status {
+				t.Errorf("teacher = %+v, want it unchanged", teacher)
+			}
+		})
+	}
+}
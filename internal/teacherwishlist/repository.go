@@ -0,0 +1,177 @@
+package teacherwishlist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+var (
+	// ErrWishlistNotFound is returned when a wishlist does not exist
+	ErrWishlistNotFound = fmt.Errorf("wishlist %w", shared.ErrNotFound)
+	// ErrWishlistItemNotFound is returned when an item is not on the wishlist
+	ErrWishlistItemNotFound = fmt.Errorf("wishlist item %w", shared.ErrNotFound)
+	// ErrWishlistVersionConflict is returned when a wishlist was changed by someone
+	// else since it was loaded
+	ErrWishlistVersionConflict = fmt.Errorf("wishlist was modified concurrently: %w", shared.ErrConflict)
+	// ErrInvalidWishlistTransition is returned for a lifecycle change the wishlist
+	// status does not allow
+	ErrInvalidWishlistTransition = fmt.Errorf("invalid wishlist status change: %w", shared.ErrConflict)
+	// ErrWishlistArchived is returned when editing an archived wishlist
+	ErrWishlistArchived = fmt.Errorf("wishlist is archived: %w", shared.ErrConflict)
+	// ErrWishlistNotVisible is returned when donors act on a wishlist that is not
+	// published or has expired
+	ErrWishlistNotVisible = fmt.Errorf("wishlist is not open for donations: %w", shared.ErrConflict)
+	// ErrRevisionNotFound is returned when a wishlist revision does not exist
+	ErrRevisionNotFound = fmt.Errorf("wishlist revision %w", shared.ErrNotFound)
+	// ErrWishlistLimitReached is returned when a teacher already has the maximum
+	// number of wishlists that are not archived
+	ErrWishlistLimitReached = fmt.Errorf("teacher has reached the maximum number of wishlists: %w", shared.ErrConflict)
+	// ErrNotWishlistOwner is returned when a teacher acts on another teacher's wishlist
+	ErrNotWishlistOwner = fmt.Errorf("wishlist belongs to another teacher: %w", shared.ErrForbidden)
+	// ErrNotPledgeDonor is returned when someone other than the donor acts on a
+	// pledge
+	ErrNotPledgeDonor = fmt.Errorf("pledge was made by another donor: %w", shared.ErrForbidden)
+
+	// ErrTeacherNotFound is returned when a teacher does not exist
+	ErrTeacherNotFound = fmt.Errorf("teacher %w", shared.ErrNotFound)
+	// ErrTeacherEmailTaken is returned when registering an email that is already in use
+	ErrTeacherEmailTaken = fmt.Errorf("teacher email is already registered: %w", shared.ErrConflict)
+	// ErrTeacherVersionConflict is returned when a teacher was changed by someone
+	// else since it was loaded
+	ErrTeacherVersionConflict = fmt.Errorf("teacher was modified concurrently: %w", shared.ErrConflict)
+	// ErrInvalidValidationTransition is returned for a validation status change the
+	// state machine does not allow
+	ErrInvalidValidationTransition = fmt.Errorf("invalid validation transition: %w", shared.ErrConflict)
+	// ErrTeacherNotVerified is returned when an action requires a verified teacher
+	ErrTeacherNotVerified = fmt.Errorf("teacher is not verified: %w", shared.ErrForbidden)
+
+	// ErrCatalogItemNotFound is returned when a catalog item does not exist
+	ErrCatalogItemNotFound = fmt.Errorf("catalog item %w", shared.ErrNotFound)
+	// ErrCatalogNameTaken is returned when a catalog item's name or alias is
+	// already used by another catalog item
+	ErrCatalogNameTaken = fmt.Errorf("catalog item name is already in use: %w", shared.ErrConflict)
+	// ErrCatalogVersionConflict is returned when a catalog item was changed by
+	// someone else since it was loaded
+	ErrCatalogVersionConflict = fmt.Errorf("catalog item was modified concurrently: %w", shared.ErrConflict)
+
+	// ErrPledgeNotFound is returned when a pledge does not exist
+	ErrPledgeNotFound = fmt.Errorf("pledge %w", shared.ErrNotFound)
+	// ErrInsufficientQuantity is returned when a pledge asks for more units than
+	// are still available, usually because another donor claimed them first
+	ErrInsufficientQuantity = fmt.Errorf("not enough units available to pledge: %w", shared.ErrConflict)
+	// ErrPledgeNotHeld is returned when confirming or cancelling a pledge that was
+	// already resolved
+	ErrPledgeNotHeld = fmt.Errorf("pledge is no longer held: %w", shared.ErrConflict)
+	// ErrPledgeExpired is returned when confirming a pledge whose hold has lapsed
+	ErrPledgeExpired = fmt.Errorf("pledge hold has expired: %w", shared.ErrConflict)
+)
+
+// TeacherRepository persists teachers together with their validation history
+type TeacherRepository interface {
+	// Create stores a new teacher. It returns an error wrapping
+	// ErrTeacherEmailTaken if the email is already registered.
+	Create(ctx context.Context, teacher *Teacher) error
+	// GetByID returns the teacher with the full validation history, or an error
+	// wrapping ErrTeacherNotFound
+	GetByID(ctx context.Context, id string) (*Teacher, error)
+	// GetByEmail returns the teacher registered with the email, ignoring case
+	GetByEmail(ctx context.Context, email string) (*Teacher, error)
+	// Update saves the teacher and appends any new validation transitions and
+	// school transfers if the stored version still equals teacher.Version, then
+	// increments teacher.Version. Stored history is never modified. A stale version
+	// returns an error wrapping ErrTeacherVersionConflict.
+	Update(ctx context.Context, teacher *Teacher) error
+	// ListByValidationStatus returns the teachers with the given status, longest
+	// waiting first
+	ListByValidationStatus(ctx context.Context, status ValidationStatus) ([]*Teacher, error)
+	// ListBySchool returns the teachers currently at a school, oldest first
+	ListBySchool(ctx context.Context, schoolID string) ([]*Teacher, error)
+}
+
+// WishlistRepository persists wishlists together with their items
+type WishlistRepository interface {
+	// Create stores a new wishlist and its items. A non-nil revision is stored in
+	// the same transaction. If maxPerTeacher is positive and the teacher already
+	// has that many wishlists that are not archived, it returns an error wrapping
+	// ErrWishlistLimitReached; the check is atomic with the insert.
+	Create(ctx context.Context, wishlist *Wishlist, revision *WishlistRevision, maxPerTeacher int) error
+	// GetByID returns the wishlist with its items, or an error wrapping
+	// ErrWishlistNotFound
+	GetByID(ctx context.Context, id string) (*Wishlist, error)
+	// Update replaces the stored wishlist and its items if the stored version still
+	// equals wishlist.Version, then increments wishlist.Version. A stale version
+	// returns an error wrapping ErrWishlistVersionConflict. A non-nil revision is
+	// appended to the history in the same transaction; stored revisions are never
+	// modified.
+	Update(ctx context.Context, wishlist *Wishlist, revision *WishlistRevision) error
+	// Reopen stores a wishlist that was moved back out of the archive, as Update
+	// does without a revision. If maxPerTeacher is positive and the teacher
+	// already has that many other wishlists that are not archived, it returns an
+	// error wrapping ErrWishlistLimitReached; the check is atomic with the update.
+	Reopen(ctx context.Context, wishlist *Wishlist, maxPerTeacher int) error
+	// ListByTeacher returns every wishlist of a teacher, oldest first
+	ListByTeacher(ctx context.Context, teacherID string) ([]*Wishlist, error)
+	// ArchiveExpired archives every published or paused wishlist whose ExpireAt is
+	// at or before now and returns how many were archived
+	ArchiveExpired(ctx context.Context, now time.Time) (int, error)
+	RevisionReader
+}
+
+// RevisionReader reads the stored revision history of wishlists
+type RevisionReader interface {
+	// ListRevisions returns every revision of a wishlist, oldest first
+	ListRevisions(ctx context.Context, wishlistID string) ([]*WishlistRevision, error)
+	// GetRevision returns one revision, or an error wrapping ErrRevisionNotFound
+	GetRevision(ctx context.Context, wishlistID string, number int) (*WishlistRevision, error)
+}
+
+// PledgeRepository persists donor pledges. Every method that changes a pledge
+// also adjusts the item's pledged and fulfilled quantities and increments the
+// wishlist version in the same atomic step, so concurrent claims cannot reserve
+// more units than requested and stale wishlist edits are rejected.
+type PledgeRepository interface {
+	// HoldPledge stores a new held pledge and reserves its quantity on the item.
+	// It returns an error wrapping ErrInsufficientQuantity if fewer units are
+	// available, or ErrWishlistItemNotFound if the item is not on the wishlist.
+	HoldPledge(ctx context.Context, pledge *Pledge) error
+	// GetPledge returns a pledge, or an error wrapping ErrPledgeNotFound
+	GetPledge(ctx context.Context, id string) (*Pledge, error)
+	// ListPledges returns every pledge on a wishlist, oldest first
+	ListPledges(ctx context.Context, wishlistID string) ([]*Pledge, error)
+	// ResolvePledge moves a held pledge to a final status at the given time,
+	// releasing its hold and, for PledgeConfirmed, counting its units as
+	// fulfilled. It returns an error wrapping ErrPledgeNotHeld if the pledge was
+	// already resolved.
+	ResolvePledge(ctx context.Context, id string, status PledgeStatus, at time.Time) (*Pledge, error)
+	// ExpireHolds resolves every held pledge whose hold ended at or before now as
+	// PledgeExpired and returns how many were expired
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+}
+
+// CatalogRepository persists the shared catalog of canonical items
+type CatalogRepository interface {
+	// Create stores a new catalog item. It returns an error wrapping
+	// ErrCatalogNameTaken if another item has the same name, ignoring case.
+	Create(ctx context.Context, item *CatalogItem) error
+	// GetByID returns a catalog item, or an error wrapping ErrCatalogItemNotFound
+	GetByID(ctx context.Context, id string) (*CatalogItem, error)
+	// Update saves the catalog item if the stored version still equals
+	// item.Version, then increments item.Version. A stale version returns an
+	// error wrapping ErrCatalogVersionConflict.
+	Update(ctx context.Context, item *CatalogItem) error
+	// List returns every catalog item ordered by name
+	List(ctx context.Context) ([]*CatalogItem, error)
+}
+
+// SchoolDirectory looks up the schools teachers work at. It is implemented by
+// schooldirectory.Service.
+type SchoolDirectory interface {
+	// GetSchool returns the school, or an error wrapping
+	// schooldirectory.ErrSchoolNotFound. The ID of a school merged into another
+	// returns the surviving school.
+	GetSchool(ctx context.Context, id string) (*schooldirectory.School, error)
+}
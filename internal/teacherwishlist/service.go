@@ -0,0 +1,1072 @@
+package teacherwishlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+// TransferWishlists says what happens to a teacher's wishlists when they move to
+// another school
+type TransferWishlists string
+
+const (
+	// TransferMoveWishlists keeps the wishlists with the teacher. Published lists
+	// are paused until the teacher is verified at the new school.
+	TransferMoveWishlists TransferWishlists = "move"
+	// TransferArchiveWishlists archives every wishlist, for lists that only made
+	// sense at the old school
+	TransferArchiveWishlists TransferWishlists = "archive"
+)
+
+// IsValid returns true if w is a known option
+func (w TransferWishlists) IsValid() bool {
+	return w == TransferMoveWishlists || w == TransferArchiveWishlists
+}
+
+// TransferTeacherInput describes a teacher moving to another school
+type TransferTeacherInput struct {
+	SchoolID string `json:"school_id"`
+	// Wishlists defaults to TransferMoveWishlists
+	Wishlists TransferWishlists `json:"wishlists,omitempty"`
+	// Actor is the ID of the admin or teacher requesting the transfer
+	Actor string `json:"actor"`
+}
+
+// CreateTeacherInput holds the registration details of a teacher
+type CreateTeacherInput struct {
+	SchoolID string `json:"school_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+}
+
+// WishlistItemInput describes an item when creating or updating a wishlist. The
+// fulfilled quantity is never taken from input; it only changes through
+// RecordFulfillment.
+type WishlistItemInput struct {
+	// ID identifies an existing item to update; leave empty to add a new item
+	ID             string       `json:"id,omitempty"`
+	Name           string       `json:"name"`
+	Quantity       int          `json:"quantity"`
+	UnitPriceCents int64        `json:"unit_price_cents"`
+	Priority       ItemPriority `json:"priority,omitempty"`
+	Category       ItemCategory `json:"category,omitempty"`
+	ProductURL     string       `json:"product_url,omitempty"`
+	// CatalogItemID picks an item from the catalog. Its name, category and
+	// typical price fill in the fields left empty. Without it the item is linked
+	// to the catalog item its name matches, if any.
+	CatalogItemID string `json:"catalog_item_id,omitempty"`
+}
+
+// CatalogItemInput holds the editable fields of a catalog item
+type CatalogItemInput struct {
+	Name              string       `json:"name"`
+	Category          ItemCategory `json:"category,omitempty"`
+	TypicalPriceCents int64        `json:"typical_price_cents"`
+	Aliases           []string     `json:"aliases,omitempty"`
+}
+
+// CreateWishlistInput holds the fields of a new wishlist
+type CreateWishlistInput struct {
+	TeacherID   string `json:"teacher_id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	WishlistScope
+	Items []WishlistItemInput `json:"items"`
+}
+
+// ImportWishlistInput creates a draft wishlist from a retailer list export
+type ImportWishlistInput struct {
+	TeacherID   string `json:"teacher_id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	WishlistScope
+	Format ImportFormat `json:"format"`
+	// File is the export file uploaded by the teacher
+	File io.Reader `json:"-"`
+}
+
+// ImportWishlistResult is the wishlist created by an import and the rows of the
+// file that were left out
+type ImportWishlistResult struct {
+	Wishlist *Wishlist        `json:"wishlist"`
+	Skipped  []ImportRowError `json:"skipped"`
+}
+
+// UpdateWishlistInput replaces the editable fields of a wishlist. Items is the
+// complete new item list: existing items are matched by ID, items without an ID
+// are added and items left out are removed.
+type UpdateWishlistInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	WishlistScope
+	Items []WishlistItemInput `json:"items"`
+}
+
+// PublishInput schedules a wishlist. A nil PublishAt publishes immediately and a
+// nil ExpireAt expires the list at the end of the school year.
+type PublishInput struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	ExpireAt  *time.Time `json:"expire_at,omitempty"`
+}
+
+// ClaimInput describes a donor's pledge of some units of a wishlist item
+type ClaimInput struct {
+	WishlistID string `json:"wishlist_id"`
+	ItemID     string `json:"item_id"`
+	DonorName  string `json:"donor_name"`
+	DonorEmail string `json:"donor_email"`
+	Quantity   int    `json:"quantity"`
+}
+
+// PledgeCanceller identifies who withdraws a pledge: the donor, by the email the
+// pledge was made with, or the wishlist's teacher
+type PledgeCanceller struct {
+	DonorEmail string `json:"donor_email,omitempty"`
+	TeacherID  string `json:"teacher_id,omitempty"`
+}
+
+// Service implements the teacher wishlist use cases
+type Service struct {
+	teachers     TeacherRepository
+	schools      SchoolDirectory
+	wishlists    WishlistRepository
+	pledges      PledgeRepository
+	catalog      CatalogRepository
+	holdDuration time.Duration
+	maxWishlists int
+	now          func() time.Time
+}
+
+// ServiceOption configures a Service
+type ServiceOption func(*Service)
+
+// WithPledgeHoldDuration sets how long a claim reserves units before it expires.
+// The default is shared.DefaultPledgeHoldDuration.
+func WithPledgeHoldDuration(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		if d > 0 {
+			s.holdDuration = d
+		}
+	}
+}
+
+// WithMaxWishlistsPerTeacher sets how many wishlists that are not archived a
+// teacher can have. The default is shared.DefaultMaxWishlistsPerTeacher.
+func WithMaxWishlistsPerTeacher(n int) ServiceOption {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxWishlists = n
+		}
+	}
+}
+
+// NewService creates a Service backed by the given repositories
+func NewService(teachers TeacherRepository, schools SchoolDirectory, wishlists WishlistRepository,
+	pledges PledgeRepository, catalog CatalogRepository, opts ...ServiceOption) *Service {
+	s := &Service{
+		teachers:     teachers,
+		schools:      schools,
+		wishlists:    wishlists,
+		pledges:      pledges,
+		catalog:      catalog,
+		holdDuration: shared.DefaultPledgeHoldDuration,
+		maxWishlists: shared.DefaultMaxWishlistsPerTeacher,
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// CreateTeacher registers a teacher at an existing school, or at the school it
+// was merged into. New teachers start unverified.
+func (s *Service) CreateTeacher(ctx context.Context, input CreateTeacherInput) (*Teacher, error) {
+	now := s.now().UTC()
+	teacher := &Teacher{
+		ID:              shared.NewID(),
+		SchoolID:        strings.TrimSpace(input.SchoolID),
+		Name:            strings.TrimSpace(input.Name),
+		Email:           strings.ToLower(strings.TrimSpace(input.Email)),
+		ValidationState: NewValidationState(),
+		Version:         1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := teacher.Validate(); err != nil {
+		return nil, err
+	}
+
+	school, err := s.resolveSchool(ctx, teacher.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	teacher.SchoolID = school.ID
+
+	if _, err := s.teachers.GetByEmail(ctx, teacher.Email); err == nil {
+		return nil, ErrTeacherEmailTaken
+	} else if !errors.Is(err, shared.ErrNotFound) {
+		return nil, fmt.Errorf("create teacher: %w", err)
+	}
+
+	if err := s.teachers.Create(ctx, teacher); err != nil {
+		return nil, fmt.Errorf("create teacher: %w", err)
+	}
+
+	return teacher, nil
+}
+
+// GetTeacher returns a teacher by ID
+func (s *Service) GetTeacher(ctx context.Context, id string) (*Teacher, error) {
+	return s.teachers.GetByID(ctx, id)
+}
+
+// SubmitForReview asks an admin to verify the teacher. Unverified, rejected and
+// expired teachers can submit; the teacher is recorded as the actor.
+func (s *Service) SubmitForReview(ctx context.Context, teacherID string) (*Teacher, error) {
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := teacher.Transition(ValidationPendingReview, teacher.ID, "", s.now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.teachers.Update(ctx, teacher); err != nil {
+		return nil, fmt.Errorf("submit for review: %w", err)
+	}
+
+	return teacher, nil
+}
+
+// TransferTeacher moves a teacher to another school and sends them back to
+// review. Their wishlists are paused or archived first, so a failed teacher
+// update can simply be retried: donors never see lists of a teacher whose
+// school changed before an admin verified them again.
+func (s *Service) TransferTeacher(ctx context.Context, teacherID string, input TransferTeacherInput) (*Teacher, error) {
+	if input.Wishlists == "" {
+		input.Wishlists = TransferMoveWishlists
+	}
+	if !input.Wishlists.IsValid() {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "wishlists",
+			Code:    shared.CodeTransferWishlistsInvalid,
+			Message: fmt.Sprintf("unknown wishlist transfer option %q", input.Wishlists),
+		})
+	}
+
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the transfer is allowed on a copy before touching any wishlist
+	now := s.now().UTC()
+	transferred := *teacher
+	if err := transferred.TransferTo(input.SchoolID, input.Actor, now); err != nil {
+		return nil, err
+	}
+
+	school, err := s.resolveSchool(ctx, transferred.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	if school.ID != transferred.SchoolID {
+		// The school was merged into another, so the teacher moves to the survivor
+		transferred = *teacher
+		if err := transferred.TransferTo(school.ID, input.Actor, now); err != nil {
+			return nil, err
+		}
+	}
+
+	wishlists, err := s.wishlists.ListByTeacher(ctx, teacher.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, wishlist := range wishlists {
+		var change func(*Wishlist, time.Time) error
+		switch {
+		case input.Wishlists == TransferArchiveWishlists && wishlist.Status != WishlistArchived:
+			change = (*Wishlist).Archive
+		case input.Wishlists == TransferMoveWishlists && wishlist.Status == WishlistPublished:
+			change = (*Wishlist).Pause
+		default:
+			continue
+		}
+
+		if err := change(wishlist, now); err != nil {
+			return nil, err
+		}
+		if err := s.wishlists.Update(ctx, wishlist, nil); err != nil {
+			return nil, fmt.Errorf("transfer teacher wishlists: %w", err)
+		}
+	}
+	if input.Wishlists == TransferArchiveWishlists {
+		transferred.PrimaryWishlistID = ""
+	}
+
+	if err := s.teachers.Update(ctx, &transferred); err != nil {
+		return nil, fmt.Errorf("transfer teacher: %w", err)
+	}
+
+	return &transferred, nil
+}
+
+// CreateWishlist validates and stores a new wishlist. Validation problems in the
+// wishlist or any item are all reported in one *shared.ValidationError.
+func (s *Service) CreateWishlist(ctx context.Context, input CreateWishlistInput) (*Wishlist, error) {
+	now := s.now().UTC()
+	wishlist := &Wishlist{
+		ID:            shared.NewID(),
+		TeacherID:     strings.TrimSpace(input.TeacherID),
+		Title:         strings.TrimSpace(input.Title),
+		Description:   strings.TrimSpace(input.Description),
+		WishlistScope: input.WishlistScope.normalize(),
+		Items:         make([]WishlistItem, 0, len(input.Items)),
+		Status:        WishlistDraft,
+		Revision:      1,
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	verr := &shared.ValidationError{}
+	inputs, err := s.linkCatalogItems(ctx, input.Items, verr)
+	if err != nil {
+		return nil, err
+	}
+	for idx, in := range inputs {
+		if in.ID != "" {
+			verr.Add(fmt.Sprintf("items[%d].id", idx), shared.CodeItemIDUnknown, "new wishlists cannot reference existing items")
+		}
+		wishlist.Items = append(wishlist.Items, newItemFromInput(in))
+	}
+
+	verr.Merge("", shared.CodeWishlistItemInvalid, wishlist.Validate())
+	if err := verr.ErrOrNil(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.teachers.GetByID(ctx, wishlist.TeacherID); err != nil {
+		return nil, err
+	}
+
+	revision := newWishlistRevision(wishlist, RevisionCreated, 0)
+	if err := s.wishlists.Create(ctx, wishlist, revision, s.maxWishlists); err != nil {
+		return nil, fmt.Errorf("create wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// ImportWishlist parses a retailer list export and creates a draft wishlist from
+// the rows that parsed. The file is only read, never followed to the retailer
+// site. If no row parsed, nothing is created and the row errors are returned as
+// a *shared.ValidationError under "rows[line]".
+func (s *Service) ImportWishlist(ctx context.Context, input ImportWishlistInput) (*ImportWishlistResult, error) {
+	list, err := ParseRetailerList(input.File, input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list.Items) == 0 {
+		verr := shared.NewValidationError(shared.FieldError{
+			Field:   "file",
+			Code:    shared.CodeImportNoItems,
+			Message: "no row of the file could be imported",
+		})
+		for _, row := range list.Skipped {
+			verr.Merge(fmt.Sprintf("rows[%d]", row.Line), "", shared.NewValidationError(row.Errors...))
+		}
+		return nil, verr
+	}
+
+	wishlist, err := s.CreateWishlist(ctx, CreateWishlistInput{
+		TeacherID:     input.TeacherID,
+		Title:         input.Title,
+		Description:   input.Description,
+		WishlistScope: input.WishlistScope,
+		Items:         list.Items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportWishlistResult{Wishlist: wishlist, Skipped: list.Skipped}, nil
+}
+
+// GetWishlist returns a wishlist by ID
+func (s *Service) GetWishlist(ctx context.Context, id string) (*Wishlist, error) {
+	return s.wishlists.GetByID(ctx, id)
+}
+
+// UpdateWishlist replaces the title, description and items of a wishlist while
+// keeping the quantity invariants: an item's requested quantity cannot drop below
+// what has already been fulfilled or pledged, and items with fulfilled or pledged
+// units cannot be removed. Every update is recorded as a new revision. Only the
+// wishlist's teacher can update it.
+func (s *Service) UpdateWishlist(ctx context.Context, teacherID, id string,
+	input UpdateWishlistInput) (*Wishlist, error) {
+	wishlist, err := s.ownedWishlist(ctx, teacherID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editWishlist(ctx, wishlist, input, RevisionUpdated, 0)
+}
+
+// ListRevisions returns every revision of a teacher's own wishlist, oldest
+// first. Admins read other teachers' history through admin.WishlistHistoryService.
+func (s *Service) ListRevisions(ctx context.Context, teacherID, wishlistID string) ([]*WishlistRevision, error) {
+	if _, err := s.ownedWishlist(ctx, teacherID, wishlistID); err != nil {
+		return nil, err
+	}
+	return s.wishlists.ListRevisions(ctx, wishlistID)
+}
+
+// GetRevision returns one revision of a teacher's own wishlist
+func (s *Service) GetRevision(ctx context.Context, teacherID, wishlistID string,
+	number int) (*WishlistRevision, error) {
+	if _, err := s.ownedWishlist(ctx, teacherID, wishlistID); err != nil {
+		return nil, err
+	}
+	return s.wishlists.GetRevision(ctx, wishlistID, number)
+}
+
+// DiffRevisions compares two revisions of a teacher's own wishlist
+func (s *Service) DiffRevisions(ctx context.Context, teacherID, wishlistID string,
+	from, to int) (WishlistDiff, error) {
+	if _, err := s.ownedWishlist(ctx, teacherID, wishlistID); err != nil {
+		return WishlistDiff{}, err
+	}
+	return DiffStoredRevisions(ctx, s.wishlists, wishlistID, from, to)
+}
+
+// DiffStoredRevisions loads two revisions of a wishlist and compares them
+func DiffStoredRevisions(ctx context.Context, revisions RevisionReader, wishlistID string,
+	from, to int) (WishlistDiff, error) {
+	fromRevision, err := revisions.GetRevision(ctx, wishlistID, from)
+	if err != nil {
+		return WishlistDiff{}, err
+	}
+
+	toRevision, err := revisions.GetRevision(ctx, wishlistID, to)
+	if err != nil {
+		return WishlistDiff{}, err
+	}
+
+	return DiffWishlistRevisions(*fromRevision, *toRevision), nil
+}
+
+// RestoreRevision makes an earlier revision's title, description and items the
+// current content, recorded as a new revision. Items that were removed since are
+// added back as new items. The quantity invariants still apply, so a restore
+// that would drop units already fulfilled or pledged is rejected. Only the
+// teacher who owns the wishlist can restore it.
+func (s *Service) RestoreRevision(ctx context.Context, teacherID, wishlistID string, number int) (*Wishlist, error) {
+	wishlist, err := s.ownedWishlist(ctx, teacherID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.wishlists.GetRevision(ctx, wishlistID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions do not record the scope, so the current one is kept
+	input := UpdateWishlistInput{
+		Title:         revision.Title,
+		Description:   revision.Description,
+		WishlistScope: wishlist.WishlistScope,
+		Items:         make([]WishlistItemInput, 0, len(revision.Items)),
+	}
+	for _, item := range revision.Items {
+		in := WishlistItemInput{
+			Name:           item.Name,
+			Quantity:       item.QuantityRequested,
+			UnitPriceCents: item.UnitPriceCents,
+			Priority:       item.Priority,
+			Category:       item.Category,
+			ProductURL:     item.ProductURL,
+			CatalogItemID:  item.CatalogItemID,
+		}
+		if wishlist.Item(item.ID) != nil {
+			in.ID = item.ID
+		}
+		input.Items = append(input.Items, in)
+	}
+
+	return s.editWishlist(ctx, wishlist, input, RevisionRestored, number)
+}
+
+// editWishlist applies an edit to a loaded wishlist, enforcing the quantity
+// invariants, and saves it with a new revision
+func (s *Service) editWishlist(ctx context.Context, wishlist *Wishlist, input UpdateWishlistInput,
+	source RevisionSource, restoredFrom int) (*Wishlist, error) {
+	if wishlist.Status == WishlistArchived {
+		return nil, ErrWishlistArchived
+	}
+
+	existing := make(map[string]WishlistItem, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		existing[item.ID] = item
+	}
+
+	verr := &shared.ValidationError{}
+	inputs, err := s.linkCatalogItems(ctx, input.Items, verr)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]WishlistItem, 0, len(inputs))
+	kept := make(map[string]bool, len(inputs))
+	for idx, in := range inputs {
+		if in.ID == "" {
+			items = append(items, newItemFromInput(in))
+			continue
+		}
+
+		updated := newItemFromInput(in)
+		if current, ok := existing[in.ID]; ok {
+			updated.ID = current.ID
+			updated.QuantityFulfilled = current.QuantityFulfilled
+			updated.QuantityPledged = current.QuantityPledged
+			kept[in.ID] = true
+		} else {
+			verr.Add(fmt.Sprintf("items[%d].id", idx), shared.CodeItemIDUnknown,
+				fmt.Sprintf("item %s is not on this wishlist", in.ID))
+		}
+		items = append(items, updated)
+	}
+
+	for _, item := range wishlist.Items {
+		if committed := item.QuantityFulfilled + item.QuantityPledged; !kept[item.ID] && committed > 0 {
+			verr.Add("items", shared.CodeItemFulfilledRemoval,
+				fmt.Sprintf("item %q cannot be removed because %d have already been fulfilled or pledged", item.Name, committed))
+		}
+	}
+
+	wishlist.Title = strings.TrimSpace(input.Title)
+	wishlist.Description = strings.TrimSpace(input.Description)
+	wishlist.WishlistScope = input.WishlistScope.normalize()
+	wishlist.Items = items
+
+	verr.Merge("", shared.CodeWishlistItemInvalid, wishlist.Validate())
+	if err := verr.ErrOrNil(); err != nil {
+		return nil, err
+	}
+
+	wishlist.UpdatedAt = s.now().UTC()
+	wishlist.Revision++
+	if err := s.wishlists.Update(ctx, wishlist, newWishlistRevision(wishlist, source, restoredFrom)); err != nil {
+		return nil, fmt.Errorf("update wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// RecordFulfillment marks quantity units of an item as received by the
+// classroom. Only the wishlist's teacher can record them.
+func (s *Service) RecordFulfillment(ctx context.Context, teacherID, wishlistID, itemID string,
+	quantity int) (*Wishlist, error) {
+	wishlist, err := s.ownedWishlist(ctx, teacherID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := wishlist.Item(itemID)
+	if item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWishlistItemNotFound, itemID)
+	}
+
+	if err := item.Fulfill(quantity); err != nil {
+		return nil, err
+	}
+
+	wishlist.UpdatedAt = s.now().UTC()
+	if err := s.wishlists.Update(ctx, wishlist, nil); err != nil {
+		return nil, fmt.Errorf("record fulfillment: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// ClaimItem places a time-limited hold on some units of an item for a donor.
+// If another donor claimed the units first, the error wraps
+// ErrInsufficientQuantity.
+func (s *Service) ClaimItem(ctx context.Context, input ClaimInput) (*Pledge, error) {
+	now := s.now().UTC()
+	pledge := &Pledge{
+		ID:         shared.NewID(),
+		WishlistID: strings.TrimSpace(input.WishlistID),
+		ItemID:     strings.TrimSpace(input.ItemID),
+		DonorName:  strings.TrimSpace(input.DonorName),
+		DonorEmail: strings.TrimSpace(input.DonorEmail),
+		Quantity:   input.Quantity,
+		Status:     PledgeHeld,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.holdDuration),
+	}
+
+	if err := pledge.Validate(); err != nil {
+		return nil, err
+	}
+
+	wishlist, err := s.wishlists.GetByID(ctx, pledge.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if !wishlist.IsVisibleAt(now) {
+		return nil, ErrWishlistNotVisible
+	}
+
+	if err := s.pledges.HoldPledge(ctx, pledge); err != nil {
+		return nil, fmt.Errorf("claim item: %w", err)
+	}
+
+	return pledge, nil
+}
+
+// ConfirmPledge records that the teacher received a pledged donation, turning
+// its held units into fulfilled units. Only the wishlist's teacher can confirm,
+// and a pledge whose hold has lapsed is expired instead.
+func (s *Service) ConfirmPledge(ctx context.Context, teacherID, pledgeID string) (*Pledge, error) {
+	pledge, err := s.pledges.GetPledge(ctx, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist, err := s.wishlists.GetByID(ctx, pledge.WishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.TeacherID != teacherID {
+		return nil, ErrNotWishlistOwner
+	}
+
+	now := s.now().UTC()
+	if pledge.HoldExpired(now) {
+		_, err := s.pledges.ResolvePledge(ctx, pledge.ID, PledgeExpired, now)
+		if err != nil && !errors.Is(err, ErrPledgeNotHeld) {
+			return nil, fmt.Errorf("expire pledge: %w", err)
+		}
+		return nil, ErrPledgeExpired
+	}
+
+	return s.pledges.ResolvePledge(ctx, pledge.ID, PledgeConfirmed, now)
+}
+
+// CancelPledge withdraws a held pledge and releases its units. Only the donor
+// who made the pledge or the wishlist's teacher can cancel it.
+func (s *Service) CancelPledge(ctx context.Context, by PledgeCanceller, pledgeID string) (*Pledge, error) {
+	pledge, err := s.pledges.GetPledge(ctx, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	donorEmail := strings.TrimSpace(by.DonorEmail)
+	if donorEmail == "" || !strings.EqualFold(donorEmail, pledge.DonorEmail) {
+		if by.TeacherID == "" {
+			return nil, ErrNotPledgeDonor
+		}
+		if _, err := s.ownedWishlist(ctx, by.TeacherID, pledge.WishlistID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.pledges.ResolvePledge(ctx, pledge.ID, PledgeCancelled, s.now().UTC())
+}
+
+// ListPledges returns every pledge on a wishlist, oldest first
+func (s *Service) ListPledges(ctx context.Context, wishlistID string) ([]*Pledge, error) {
+	return s.pledges.ListPledges(ctx, wishlistID)
+}
+
+// ExpirePledges releases the units of every held pledge whose hold has lapsed
+// and returns how many pledges expired
+func (s *Service) ExpirePledges(ctx context.Context) (int, error) {
+	return s.pledges.ExpireHolds(ctx, s.now().UTC())
+}
+
+// RunPledgeExpiry calls ExpirePledges every interval, or every
+// shared.PledgeExpiryInterval if interval is not positive, until ctx is
+// cancelled. Failed runs are logged and retried on the next tick.
+func (s *Service) RunPledgeExpiry(ctx context.Context, interval time.Duration) error {
+	return runEvery(ctx, interval, shared.PledgeExpiryInterval, "expire pledges", s.ExpirePledges)
+}
+
+// PublishWishlist makes a teacher's wishlist visible to donors, optionally on a
+// schedule. Only verified teachers can publish.
+func (s *Service) PublishWishlist(ctx context.Context, teacherID, wishlistID string,
+	input PublishInput) (*Wishlist, error) {
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if !teacher.ValidationState.IsVerified() {
+		return nil, ErrTeacherNotVerified
+	}
+
+	return s.changeStatus(ctx, teacherID, wishlistID, func(w *Wishlist, now time.Time) error {
+		return w.Publish(input.PublishAt, input.ExpireAt, now)
+	})
+}
+
+// PauseWishlist temporarily hides a published wishlist from donors
+func (s *Service) PauseWishlist(ctx context.Context, teacherID, wishlistID string) (*Wishlist, error) {
+	return s.changeStatus(ctx, teacherID, wishlistID, (*Wishlist).Pause)
+}
+
+// ArchiveWishlist closes a wishlist
+func (s *Service) ArchiveWishlist(ctx context.Context, teacherID, wishlistID string) (*Wishlist, error) {
+	return s.changeStatus(ctx, teacherID, wishlistID, (*Wishlist).Archive)
+}
+
+// ReopenWishlist turns an archived wishlist back into a draft, provided the
+// teacher has not reached the wishlist limit since it was archived
+func (s *Service) ReopenWishlist(ctx context.Context, teacherID, wishlistID string) (*Wishlist, error) {
+	wishlist, err := s.ownedWishlist(ctx, teacherID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wishlist.Reopen(s.now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlists.Reopen(ctx, wishlist, s.maxWishlists); err != nil {
+		return nil, fmt.Errorf("reopen wishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// ListWishlists returns every wishlist of a teacher, oldest first
+func (s *Service) ListWishlists(ctx context.Context, teacherID string) ([]*Wishlist, error) {
+	return s.wishlists.ListByTeacher(ctx, teacherID)
+}
+
+// SetPrimaryWishlist picks the wishlist featured on the teacher's public profile
+func (s *Service) SetPrimaryWishlist(ctx context.Context, teacherID, wishlistID string) (*Teacher, error) {
+	wishlist, err := s.wishlists.GetByID(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.TeacherID != teacherID {
+		return nil, ErrNotWishlistOwner
+	}
+	if wishlist.Status == WishlistArchived {
+		return nil, ErrWishlistArchived
+	}
+
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	teacher.PrimaryWishlistID = wishlist.ID
+	teacher.UpdatedAt = s.now().UTC()
+	if err := s.teachers.Update(ctx, teacher); err != nil {
+		return nil, fmt.Errorf("set primary wishlist: %w", err)
+	}
+
+	return teacher, nil
+}
+
+// PrimaryWishlist returns the wishlist to feature on a teacher's public profile:
+// the primary wishlist if donors can find it, otherwise the most recently
+// published listed one. It returns ErrWishlistNotFound if no list is listed.
+func (s *Service) PrimaryWishlist(ctx context.Context, teacherID string) (*Wishlist, error) {
+	teacher, err := s.teachers.GetByID(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlists, err := s.wishlists.ListByTeacher(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	var featured *Wishlist
+	for _, wishlist := range wishlists {
+		if !wishlist.IsListed(now) {
+			continue
+		}
+		if wishlist.ID == teacher.PrimaryWishlistID {
+			return wishlist, nil
+		}
+		if featured == nil || publishedAt(wishlist).After(publishedAt(featured)) {
+			featured = wishlist
+		}
+	}
+
+	if featured == nil {
+		return nil, fmt.Errorf("%w: no listed wishlist for teacher %s", ErrWishlistNotFound, teacherID)
+	}
+	return featured, nil
+}
+
+// publishedAt returns when a published wishlist became visible
+func publishedAt(w *Wishlist) time.Time {
+	if w.PublishAt != nil {
+		return *w.PublishAt
+	}
+	return w.UpdatedAt
+}
+
+// AddCatalogItem adds a canonical item to the shared catalog. The name and every
+// alias must differ from the names and aliases of all other catalog items once
+// normalized, so that free-text items match at most one of them exactly.
+func (s *Service) AddCatalogItem(ctx context.Context, input CatalogItemInput) (*CatalogItem, error) {
+	now := s.now().UTC()
+	item := &CatalogItem{ID: shared.NewID(), Version: 1, CreatedAt: now}
+	if err := s.saveCatalogItem(ctx, item, input, now, s.catalog.Create); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateCatalogItem replaces the editable fields of a catalog item. Wishlist
+// items already linked to it keep their own name and price.
+func (s *Service) UpdateCatalogItem(ctx context.Context, id string, input CatalogItemInput) (*CatalogItem, error) {
+	item, err := s.catalog.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveCatalogItem(ctx, item, input, s.now().UTC(), s.catalog.Update); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetCatalogItem returns a catalog item by ID
+func (s *Service) GetCatalogItem(ctx context.Context, id string) (*CatalogItem, error) {
+	return s.catalog.GetByID(ctx, id)
+}
+
+// SuggestCatalogItems returns up to limit catalog items resembling what a teacher
+// typed, best match first, for picking an item while building a wishlist
+func (s *Service) SuggestCatalogItems(ctx context.Context, text string, limit int) ([]CatalogMatch, error) {
+	catalog, err := s.catalog.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("suggest catalog items: %w", err)
+	}
+
+	if limit <= 0 || limit > shared.MaxSearchLimit {
+		limit = shared.DefaultSearchLimit
+	}
+	matches := MatchCatalog(text, catalog, shared.CatalogSuggestThreshold)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// saveCatalogItem applies input to item, checks it and stores it with save
+func (s *Service) saveCatalogItem(ctx context.Context, item *CatalogItem, input CatalogItemInput, now time.Time,
+	save func(context.Context, *CatalogItem) error) error {
+	item.Name = strings.TrimSpace(input.Name)
+	item.Category = ItemCategory(strings.ToLower(strings.TrimSpace(string(input.Category))))
+	if item.Category == "" {
+		item.Category = CategoryOther
+	}
+	item.TypicalPriceCents = input.TypicalPriceCents
+	item.Aliases = make([]string, 0, len(input.Aliases))
+	for _, alias := range input.Aliases {
+		item.Aliases = append(item.Aliases, strings.TrimSpace(alias))
+	}
+	item.UpdatedAt = now
+
+	if err := item.Validate(); err != nil {
+		return err
+	}
+
+	catalog, err := s.catalog.List(ctx)
+	if err != nil {
+		return fmt.Errorf("save catalog item: %w", err)
+	}
+	names := make(map[string]bool, len(item.Aliases)+1)
+	for _, name := range item.Names() {
+		names[NormalizeItemName(name)] = true
+	}
+	for _, other := range catalog {
+		if other.ID == item.ID {
+			continue
+		}
+		for _, name := range other.Names() {
+			if names[NormalizeItemName(name)] {
+				return fmt.Errorf("%w: %q is used by %s", ErrCatalogNameTaken, name, other.Name)
+			}
+		}
+	}
+
+	if err := save(ctx, item); err != nil {
+		return fmt.Errorf("save catalog item: %w", err)
+	}
+	return nil
+}
+
+// linkCatalogItems links each item input to a catalog item. Inputs that pick a
+// catalog item take its name, category and typical price where they leave them
+// empty; the others are linked to the catalog item their name matches best, if
+// it scores at least shared.CatalogMatchThreshold. Unknown catalog item IDs are
+// reported in verr. The catalog is small and curated, so it is matched in memory.
+func (s *Service) linkCatalogItems(ctx context.Context, inputs []WishlistItemInput,
+	verr *shared.ValidationError) ([]WishlistItemInput, error) {
+	if len(inputs) == 0 {
+		return inputs, nil
+	}
+
+	catalog, err := s.catalog.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load catalog: %w", err)
+	}
+	byID := make(map[string]*CatalogItem, len(catalog))
+	for _, item := range catalog {
+		byID[item.ID] = item
+	}
+
+	linked := make([]WishlistItemInput, 0, len(inputs))
+	for idx, in := range inputs {
+		in.CatalogItemID = strings.TrimSpace(in.CatalogItemID)
+
+		var item *CatalogItem
+		if in.CatalogItemID == "" {
+			if matches := MatchCatalog(in.Name, catalog, shared.CatalogMatchThreshold); len(matches) > 0 {
+				item = matches[0].Item
+				in.CatalogItemID = item.ID
+			}
+		} else if item = byID[in.CatalogItemID]; item == nil {
+			verr.Add(fmt.Sprintf("items[%d].catalog_item_id", idx), shared.CodeItemCatalogUnknown,
+				fmt.Sprintf("catalog item %s does not exist", in.CatalogItemID))
+		} else {
+			if strings.TrimSpace(in.Name) == "" {
+				in.Name = item.Name
+			}
+			if in.UnitPriceCents == 0 {
+				in.UnitPriceCents = item.TypicalPriceCents
+			}
+		}
+
+		if item != nil && strings.TrimSpace(string(in.Category)) == "" {
+			in.Category = item.Category
+		}
+		linked = append(linked, in)
+	}
+
+	return linked, nil
+}
+
+// ArchiveExpiredWishlists archives every open wishlist whose expiry has passed
+// and returns how many were archived
+func (s *Service) ArchiveExpiredWishlists(ctx context.Context) (int, error) {
+	return s.wishlists.ArchiveExpired(ctx, s.now().UTC())
+}
+
+// RunWishlistArchiver calls ArchiveExpiredWishlists every interval, or every
+// shared.WishlistArchiveInterval if interval is not positive, until ctx is
+// cancelled. Failed runs are logged and retried on the next tick.
+func (s *Service) RunWishlistArchiver(ctx context.Context, interval time.Duration) error {
+	return runEvery(ctx, interval, shared.WishlistArchiveInterval, "archive expired wishlists",
+		s.ArchiveExpiredWishlists)
+}
+
+// changeStatus applies a lifecycle change to a teacher's own wishlist. Status
+// changes do not create revisions.
+func (s *Service) changeStatus(ctx context.Context, teacherID, wishlistID string,
+	change func(w *Wishlist, now time.Time) error) (*Wishlist, error) {
+	wishlist, err := s.ownedWishlist(ctx, teacherID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(wishlist, s.now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlists.Update(ctx, wishlist, nil); err != nil {
+		return nil, fmt.Errorf("change wishlist status: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// resolveSchool looks up the school a teacher is joining, returning the
+// surviving school if it was merged into another. An unknown school is a
+// ValidationError on school_id.
+func (s *Service) resolveSchool(ctx context.Context, schoolID string) (*schooldirectory.School, error) {
+	school, err := s.schools.GetSchool(ctx, schoolID)
+	if errors.Is(err, schooldirectory.ErrSchoolNotFound) {
+		return nil, shared.NewValidationError(shared.FieldError{
+			Field:   "school_id",
+			Code:    shared.CodeTeacherSchoolUnknown,
+			Message: fmt.Sprintf("unknown school %q", schoolID),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("look up school: %w", err)
+	}
+	return school, nil
+}
+
+// ownedWishlist loads a wishlist, failing with ErrNotWishlistOwner unless it
+// belongs to the teacher
+func (s *Service) ownedWishlist(ctx context.Context, teacherID, wishlistID string) (*Wishlist, error) {
+	wishlist, err := s.wishlists.GetByID(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.TeacherID != teacherID {
+		return nil, ErrNotWishlistOwner
+	}
+	return wishlist, nil
+}
+
+// runEvery calls job every interval, or every fallback if interval is not
+// positive, until ctx is cancelled, logging failures and the number of records
+// each run changed
+func runEvery(ctx context.Context, interval, fallback time.Duration, name string,
+	job func(context.Context) (int, error)) error {
+	if interval <= 0 {
+		interval = fallback
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			changed, err := job(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, name, "error", err)
+				continue
+			}
+			if changed > 0 {
+				slog.InfoContext(ctx, name, "count", changed)
+			}
+		}
+	}
+}
+
+// newItemFromInput builds an unvalidated item from input
+func newItemFromInput(in WishlistItemInput) WishlistItem {
+	item := newWishlistItem(in.Name, in.Quantity, in.UnitPriceCents, in.Priority, in.Category, in.ProductURL)
+	item.CatalogItemID = strings.TrimSpace(in.CatalogItemID)
+	return item
+}
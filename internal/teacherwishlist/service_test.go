@@ -0,0 +1,1629 @@
+package teacherwishlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared"
+)
+
+// memoryRepository is an in-memory WishlistRepository and PledgeRepository for
+// service tests
+type memoryRepository struct {
+	mu        sync.Mutex
+	wishlists map[string]*Wishlist
+	revisions map[string][]*WishlistRevision
+	pledges   map[string]*Pledge
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		wishlists: make(map[string]*Wishlist),
+		revisions: make(map[string][]*WishlistRevision),
+		pledges:   make(map[string]*Pledge),
+	}
+}
+
+func (r *memoryRepository) Create(_ context.Context, wishlist *Wishlist, revision *WishlistRevision,
+	maxPerTeacher int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	open := 0
+	for _, stored := range r.wishlists {
+		if stored.TeacherID == wishlist.TeacherID && stored.Status != WishlistArchived {
+			open++
+		}
+	}
+	if maxPerTeacher > 0 && open >= maxPerTeacher {
+		return ErrWishlistLimitReached
+	}
+
+	r.wishlists[wishlist.ID] = cloneWishlist(wishlist)
+	r.appendRevisionLocked(revision)
+	return nil
+}
+
+func (r *memoryRepository) GetByID(_ context.Context, id string) (*Wishlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wishlist, ok := r.wishlists[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWishlistNotFound, id)
+	}
+	return cloneWishlist(wishlist), nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, wishlist *Wishlist, revision *WishlistRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.updateLocked(wishlist, revision)
+}
+
+func (r *memoryRepository) updateLocked(wishlist *Wishlist, revision *WishlistRevision) error {
+	stored, ok := r.wishlists[wishlist.ID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrWishlistNotFound, wishlist.ID)
+	}
+	if stored.Version != wishlist.Version {
+		return ErrWishlistVersionConflict
+	}
+
+	wishlist.Version++
+	r.wishlists[wishlist.ID] = cloneWishlist(wishlist)
+	r.appendRevisionLocked(revision)
+	return nil
+}
+
+func (r *memoryRepository) Reopen(_ context.Context, wishlist *Wishlist, maxPerTeacher int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	open := 0
+	for _, stored := range r.wishlists {
+		if stored.TeacherID == wishlist.TeacherID && stored.ID != wishlist.ID && stored.Status != WishlistArchived {
+			open++
+		}
+	}
+	if maxPerTeacher > 0 && open >= maxPerTeacher {
+		return ErrWishlistLimitReached
+	}
+
+	return r.updateLocked(wishlist, nil)
+}
+
+func (r *memoryRepository) appendRevisionLocked(revision *WishlistRevision) {
+	if revision != nil {
+		clone := *revision
+		r.revisions[revision.WishlistID] = append(r.revisions[revision.WishlistID], &clone)
+	}
+}
+
+func (r *memoryRepository) ListRevisions(_ context.Context, wishlistID string) ([]*WishlistRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*WishlistRevision{}, r.revisions[wishlistID]...), nil
+}
+
+func (r *memoryRepository) GetRevision(_ context.Context, wishlistID string, number int) (*WishlistRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, revision := range r.revisions[wishlistID] {
+		if revision.Number == number {
+			clone := *revision
+			return &clone, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s revision %d", ErrRevisionNotFound, wishlistID, number)
+}
+
+func (r *memoryRepository) ListByTeacher(_ context.Context, teacherID string) ([]*Wishlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wishlists := []*Wishlist{}
+	for _, wishlist := range r.wishlists {
+		if wishlist.TeacherID == teacherID {
+			wishlists = append(wishlists, cloneWishlist(wishlist))
+		}
+	}
+	sort.Slice(wishlists, func(i, j int) bool { return wishlists[i].CreatedAt.Before(wishlists[j].CreatedAt) })
+	return wishlists, nil
+}
+
+func (r *memoryRepository) ArchiveExpired(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	archived := 0
+	for _, wishlist := range r.wishlists {
+		if !wishlist.IsExpiredAt(now) {
+			continue
+		}
+		if err := wishlist.Archive(now); err != nil {
+			return archived, err
+		}
+		wishlist.Version++
+		archived++
+	}
+	return archived, nil
+}
+
+func (r *memoryRepository) HoldPledge(_ context.Context, pledge *Pledge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wishlist, ok := r.wishlists[pledge.WishlistID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrWishlistNotFound, pledge.WishlistID)
+	}
+	item := wishlist.Item(pledge.ItemID)
+	if item == nil {
+		return fmt.Errorf("%w: %s", ErrWishlistItemNotFound, pledge.ItemID)
+	}
+	if err := item.reserve(pledge.Quantity); err != nil {
+		return err
+	}
+
+	wishlist.Version++
+	clone := *pledge
+	r.pledges[pledge.ID] = &clone
+	return nil
+}
+
+func (r *memoryRepository) GetPledge(_ context.Context, id string) (*Pledge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pledge, ok := r.pledges[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPledgeNotFound, id)
+	}
+	clone := *pledge
+	return &clone, nil
+}
+
+func (r *memoryRepository) ListPledges(_ context.Context, wishlistID string) ([]*Pledge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pledges := []*Pledge{}
+	for _, pledge := range r.pledges {
+		if pledge.WishlistID == wishlistID {
+			clone := *pledge
+			pledges = append(pledges, &clone)
+		}
+	}
+	sort.Slice(pledges, func(i, j int) bool { return pledges[i].CreatedAt.Before(pledges[j].CreatedAt) })
+	return pledges, nil
+}
+
+func (r *memoryRepository) ResolvePledge(_ context.Context, id string, status PledgeStatus, at time.Time) (*Pledge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pledge, ok := r.pledges[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPledgeNotFound, id)
+	}
+	if err := r.resolveLocked(pledge, status, at); err != nil {
+		return nil, err
+	}
+	clone := *pledge
+	return &clone, nil
+}
+
+func (r *memoryRepository) ExpireHolds(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := 0
+	for _, pledge := range r.pledges {
+		if pledge.HoldExpired(now) {
+			if err := r.resolveLocked(pledge, PledgeExpired, now); err != nil {
+				return expired, err
+			}
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (r *memoryRepository) resolveLocked(pledge *Pledge, status PledgeStatus, at time.Time) error {
+	if err := pledge.resolve(status, at); err != nil {
+		return err
+	}
+
+	wishlist := r.wishlists[pledge.WishlistID]
+	wishlist.Item(pledge.ItemID).settle(pledge.Quantity, status)
+	wishlist.Version++
+	return nil
+}
+
+// memoryTeacherRepository is an in-memory TeacherRepository for service tests
+type memoryTeacherRepository struct {
+	mu       sync.Mutex
+	teachers map[string]*Teacher
+}
+
+func newMemoryTeacherRepository() *memoryTeacherRepository {
+	return &memoryTeacherRepository{teachers: make(map[string]*Teacher)}
+}
+
+func (r *memoryTeacherRepository) Create(_ context.Context, teacher *Teacher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.teachers {
+		if strings.EqualFold(existing.Email, teacher.Email) {
+			return ErrTeacherEmailTaken
+		}
+	}
+	r.teachers[teacher.ID] = cloneTeacher(teacher)
+	return nil
+}
+
+func (r *memoryTeacherRepository) GetByID(_ context.Context, id string) (*Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	teacher, ok := r.teachers[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTeacherNotFound, id)
+	}
+	return cloneTeacher(teacher), nil
+}
+
+func (r *memoryTeacherRepository) GetByEmail(_ context.Context, email string) (*Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, teacher := range r.teachers {
+		if strings.EqualFold(teacher.Email, email) {
+			return cloneTeacher(teacher), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTeacherNotFound, email)
+}
+
+func (r *memoryTeacherRepository) Update(_ context.Context, teacher *Teacher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.teachers[teacher.ID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTeacherNotFound, teacher.ID)
+	}
+	if stored.Version != teacher.Version {
+		return ErrTeacherVersionConflict
+	}
+
+	teacher.Version++
+	r.teachers[teacher.ID] = cloneTeacher(teacher)
+	return nil
+}
+
+func (r *memoryTeacherRepository) ListByValidationStatus(_ context.Context, status ValidationStatus) ([]*Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	teachers := []*Teacher{}
+	for _, teacher := range r.teachers {
+		if teacher.ValidationState.Status == status {
+			teachers = append(teachers, cloneTeacher(teacher))
+		}
+	}
+	sort.Slice(teachers, func(i, j int) bool { return teachers[i].UpdatedAt.Before(teachers[j].UpdatedAt) })
+	return teachers, nil
+}
+
+func (r *memoryTeacherRepository) ListBySchool(_ context.Context, schoolID string) ([]*Teacher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	teachers := []*Teacher{}
+	for _, teacher := range r.teachers {
+		if teacher.SchoolID == schoolID {
+			teachers = append(teachers, cloneTeacher(teacher))
+		}
+	}
+	sort.Slice(teachers, func(i, j int) bool { return teachers[i].CreatedAt.Before(teachers[j].CreatedAt) })
+	return teachers, nil
+}
+
+func cloneTeacher(teacher *Teacher) *Teacher {
+	clone := *teacher
+	clone.ValidationState.History = append([]ValidationTransition{}, teacher.ValidationState.History...)
+	clone.SchoolHistory = append([]SchoolTransfer(nil), teacher.SchoolHistory...)
+	return &clone
+}
+
+func cloneWishlist(wishlist *Wishlist) *Wishlist {
+	clone := *wishlist
+	clone.Items = append([]WishlistItem{}, wishlist.Items...)
+	return &clone
+}
+
+// memoryCatalogRepository is an in-memory CatalogRepository for service tests
+type memoryCatalogRepository struct {
+	mu    sync.Mutex
+	items map[string]*CatalogItem
+}
+
+func newMemoryCatalogRepository() *memoryCatalogRepository {
+	return &memoryCatalogRepository{items: make(map[string]*CatalogItem)}
+}
+
+func (r *memoryCatalogRepository) Create(_ context.Context, item *CatalogItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.items {
+		if strings.EqualFold(existing.Name, item.Name) {
+			return ErrCatalogNameTaken
+		}
+	}
+	r.items[item.ID] = cloneCatalogItem(item)
+	return nil
+}
+
+func (r *memoryCatalogRepository) GetByID(_ context.Context, id string) (*CatalogItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrCatalogItemNotFound
+	}
+	return cloneCatalogItem(item), nil
+}
+
+func (r *memoryCatalogRepository) Update(_ context.Context, item *CatalogItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.items[item.ID]
+	if !ok {
+		return ErrCatalogItemNotFound
+	}
+	if stored.Version != item.Version {
+		return ErrCatalogVersionConflict
+	}
+	item.Version++
+	r.items[item.ID] = cloneCatalogItem(item)
+	return nil
+}
+
+func (r *memoryCatalogRepository) List(_ context.Context) ([]*CatalogItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]*CatalogItem, 0, len(r.items))
+	for _, item := range r.items {
+		items = append(items, cloneCatalogItem(item))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+func cloneCatalogItem(item *CatalogItem) *CatalogItem {
+	clone := *item
+	clone.Aliases = append([]string{}, item.Aliases...)
+	return &clone
+}
+
+// stubSchoolDirectory maps the school IDs it knows to the surviving school: a
+// school merged into another maps to the other school
+type stubSchoolDirectory map[string]string
+
+func (d stubSchoolDirectory) GetSchool(_ context.Context, id string) (*schooldirectory.School, error) {
+	survivor, ok := d[id]
+	if !ok {
+		return nil, schooldirectory.ErrSchoolNotFound
+	}
+	return &schooldirectory.School{ID: survivor, Name: "School " + survivor}, nil
+}
+
+var testNow = time.Date(2025, time.September, 2, 8, 0, 0, 0, time.UTC)
+
+func newTestService() (*Service, *memoryRepository) {
+	service, repo, _ := newTestServiceWithTeachers()
+	return service, repo
+}
+
+func newTestServiceWithTeachers() (*Service, *memoryRepository, *memoryTeacherRepository) {
+	repo := newMemoryRepository()
+	teachers := newMemoryTeacherRepository()
+	teachers.teachers["teacher-1"] = &Teacher{
+		ID:              "teacher-1",
+		SchoolID:        "school-1",
+		Name:            "Alex Rivera",
+		Email:           "alex.rivera@example.edu",
+		ValidationState: NewValidationState(),
+		Version:         1,
+		CreatedAt:       testNow,
+		UpdatedAt:       testNow,
+	}
+
+	schools := stubSchoolDirectory{"school-1": "school-1", "school-2": "school-2", "school-3": "school-2"}
+	service := NewService(teachers, schools, repo, repo, newMemoryCatalogRepository(),
+		WithPledgeHoldDuration(48*time.Hour))
+	service.now = func() time.Time { return testNow }
+	return service, repo, teachers
+}
+
+func createTestWishlist(t *testing.T, service *Service) *Wishlist {
+	t.Helper()
+
+	wishlist, err := service.CreateWishlist(context.Background(), CreateWishlistInput{
+		TeacherID: "teacher-1",
+		Title:     " Room 12 supplies ",
+		Items: []WishlistItemInput{
+			{Name: "Glue sticks", Quantity: 30, UnitPriceCents: 59, Category: CategorySupplies},
+			{Name: "Headphones", Quantity: 5, UnitPriceCents: 1299, Priority: PriorityHigh, Category: CategoryTechnology},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWishlist() error = %v", err)
+	}
+	return wishlist
+}
+
+// publishTestWishlist publishes a stored wishlist directly through the
+// repository, bypassing the teacher verification check
+func publishTestWishlist(t *testing.T, repo *memoryRepository, wishlist *Wishlist) *Wishlist {
+	t.Helper()
+
+	stored, err := repo.GetByID(context.Background(), wishlist.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if err := stored.Publish(nil, nil, testNow); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := repo.Update(context.Background(), stored, nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	return stored
+}
+
+func TestService_CreateWishlist(t *testing.T) {
+	service, repo := newTestService()
+
+	wishlist := createTestWishlist(t, service)
+	if wishlist.Title != "Room 12 supplies" {
+		t.Errorf("Title = %q, want trimmed title", wishlist.Title)
+	}
+	if wishlist.Version != 1 || !wishlist.CreatedAt.Equal(testNow) {
+		t.Errorf("Version = %d, CreatedAt = %v", wishlist.Version, wishlist.CreatedAt)
+	}
+	if len(wishlist.Items) != 2 || wishlist.Items[0].Priority != PriorityMedium {
+		t.Errorf("Items = %+v", wishlist.Items)
+	}
+	if _, err := repo.GetByID(context.Background(), wishlist.ID); err != nil {
+		t.Errorf("wishlist was not stored: %v", err)
+	}
+}
+
+func TestService_CreateWishlist_ReportsAllErrors(t *testing.T) {
+	service, repo := newTestService()
+
+	_, err := service.CreateWishlist(context.Background(), CreateWishlistInput{
+		Items: []WishlistItemInput{
+			{Name: "Pencils", Quantity: 0},
+			{ID: "existing", Name: "Paper", Quantity: 1, ProductURL: "not a url"},
+		},
+	})
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("CreateWishlist() error = %v, want *shared.ValidationError", err)
+	}
+
+	want := map[string]string{
+		"items[1].id":                 shared.CodeItemIDUnknown,
+		"teacher_id":                  shared.CodeWishlistTeacherRequired,
+		"title":                       shared.CodeWishlistTitleRequired,
+		"items[0].quantity_requested": shared.CodeItemQuantityRange,
+		"items[1].product_url":        shared.CodeItemProductURLFormat,
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("got %+v, want %v", verr.Fields, want)
+	}
+	for _, field := range verr.Fields {
+		if want[field.Field] != field.Code {
+			t.Errorf("unexpected field error %+v", field)
+		}
+	}
+
+	if len(repo.wishlists) != 0 {
+		t.Error("invalid wishlist was stored")
+	}
+}
+
+func TestService_UpdateWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	wishlist := createTestWishlist(t, service)
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, glue.ID, 20); err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+
+	updated, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title: "Room 12",
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 25, UnitPriceCents: 59},
+			{Name: "Scissors", Quantity: 12, UnitPriceCents: 199},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+
+	if updated.Title != "Room 12" || updated.Version != 3 || updated.Revision != 2 {
+		t.Errorf("Title = %q, Version = %d", updated.Title, updated.Version)
+	}
+	if item := updated.Item(glue.ID); item == nil || item.QuantityFulfilled != 20 || item.QuantityRequested != 25 {
+		t.Errorf("glue sticks = %+v, want fulfillment preserved", item)
+	}
+	if updated.Item(headphones.ID) != nil {
+		t.Error("unfulfilled item left out of the update was not removed")
+	}
+	if len(updated.Items) != 2 {
+		t.Errorf("Items = %+v", updated.Items)
+	}
+}
+
+func TestService_UpdateWishlist_QuantityInvariants(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := createTestWishlist(t, service)
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, glue.ID, 20); err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, headphones.ID, 1); err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+
+	_, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title: "Room 12",
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 10},
+			{ID: "not-on-list", Name: "Rulers", Quantity: 5},
+		},
+	})
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("UpdateWishlist() error = %v, want *shared.ValidationError", err)
+	}
+	for _, code := range []string{shared.CodeItemQuantityBelowFilled, shared.CodeItemIDUnknown, shared.CodeItemFulfilledRemoval} {
+		if !verr.HasCode(code) {
+			t.Errorf("missing code %s in %+v", code, verr.Fields)
+		}
+	}
+
+	_, err = service.UpdateWishlist(ctx, "teacher-2", wishlist.ID, UpdateWishlistInput{Title: "Mine now"})
+	if !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("UpdateWishlist() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+
+	stored, _ := repo.GetByID(ctx, wishlist.ID)
+	if len(stored.Items) != 2 || stored.Item(glue.ID).QuantityRequested != 30 || stored.Title != wishlist.Title {
+		t.Errorf("rejected update changed the stored wishlist: %+v", stored)
+	}
+}
+
+func TestService_RecordFulfillment(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	wishlist := createTestWishlist(t, service)
+	headphones := wishlist.Items[1]
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, headphones.ID, 6); err == nil {
+		t.Error("RecordFulfillment() beyond quantity requested should fail")
+	}
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, "missing", 1); !errors.Is(err, ErrWishlistItemNotFound) {
+		t.Errorf("RecordFulfillment() unknown item error = %v", err)
+	}
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", "missing", headphones.ID, 1); !errors.Is(err, shared.ErrNotFound) {
+		t.Errorf("RecordFulfillment() unknown wishlist error = %v", err)
+	}
+
+	if _, err := service.RecordFulfillment(ctx, "teacher-2", wishlist.ID, headphones.ID, 1); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("RecordFulfillment() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+
+	updated, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, headphones.ID, 5)
+	if err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+	if !updated.Item(headphones.ID).IsFulfilled() {
+		t.Error("headphones should be fulfilled")
+	}
+}
+
+func claim(t *testing.T, service *Service, wishlist *Wishlist, itemID string, quantity int) *Pledge {
+	t.Helper()
+
+	pledge, err := service.ClaimItem(context.Background(), ClaimInput{
+		WishlistID: wishlist.ID,
+		ItemID:     itemID,
+		DonorName:  "Pat Donor",
+		DonorEmail: "pat@example.com",
+		Quantity:   quantity,
+	})
+	if err != nil {
+		t.Fatalf("ClaimItem(%d) error = %v", quantity, err)
+	}
+	return pledge
+}
+
+func TestService_ClaimItem(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	headphones := wishlist.Items[1]
+
+	pledge := claim(t, service, wishlist, headphones.ID, 3)
+	if pledge.Status != PledgeHeld || !pledge.ExpiresAt.Equal(testNow.Add(48*time.Hour)) {
+		t.Errorf("pledge = %+v, want held for 48h", pledge)
+	}
+
+	stored, _ := repo.GetByID(ctx, wishlist.ID)
+	item := stored.Item(headphones.ID)
+	if item.QuantityPledged != 3 || item.QuantityAvailable() != 2 || item.QuantityRemaining() != 5 {
+		t.Errorf("item quantities = %+v", item)
+	}
+
+	_, err := service.ClaimItem(ctx, ClaimInput{
+		WishlistID: wishlist.ID, ItemID: headphones.ID, DonorName: "Sam", DonorEmail: "sam@example.com", Quantity: 3,
+	})
+	if !errors.Is(err, ErrInsufficientQuantity) || !errors.Is(err, shared.ErrConflict) {
+		t.Errorf("over-claim error = %v, want ErrInsufficientQuantity", err)
+	}
+
+	_, err = service.ClaimItem(ctx, ClaimInput{WishlistID: wishlist.ID, ItemID: headphones.ID, DonorEmail: "nope"})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 3 {
+		t.Errorf("invalid claim error = %v, want 3 field errors", err)
+	}
+
+	_, err = service.ClaimItem(ctx, ClaimInput{
+		WishlistID: wishlist.ID, ItemID: "missing", DonorName: "Sam", DonorEmail: "sam@example.com", Quantity: 1,
+	})
+	if !errors.Is(err, ErrWishlistItemNotFound) {
+		t.Errorf("claim on unknown item error = %v", err)
+	}
+}
+
+func TestService_ClaimItem_Concurrent(t *testing.T) {
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	headphones := wishlist.Items[1]
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.ClaimItem(context.Background(), ClaimInput{
+				WishlistID: wishlist.ID, ItemID: headphones.ID, DonorName: "Donor", DonorEmail: "d@example.com", Quantity: 1,
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrInsufficientQuantity) {
+				t.Errorf("unexpected error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != headphones.QuantityRequested {
+		t.Errorf("%d claims succeeded, want %d", succeeded, headphones.QuantityRequested)
+	}
+	stored, _ := repo.GetByID(context.Background(), wishlist.ID)
+	if got := stored.Item(headphones.ID).QuantityPledged; got != headphones.QuantityRequested {
+		t.Errorf("QuantityPledged = %d, want %d", got, headphones.QuantityRequested)
+	}
+}
+
+func TestService_ConfirmPledge(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue := wishlist.Items[0]
+	pledge := claim(t, service, wishlist, glue.ID, 10)
+
+	if _, err := service.ConfirmPledge(ctx, "someone-else", pledge.ID); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("ConfirmPledge() by another teacher error = %v", err)
+	}
+
+	confirmed, err := service.ConfirmPledge(ctx, wishlist.TeacherID, pledge.ID)
+	if err != nil {
+		t.Fatalf("ConfirmPledge() error = %v", err)
+	}
+	if confirmed.Status != PledgeConfirmed || confirmed.ResolvedAt == nil {
+		t.Errorf("confirmed pledge = %+v", confirmed)
+	}
+
+	stored, _ := repo.GetByID(ctx, wishlist.ID)
+	if item := stored.Item(glue.ID); item.QuantityFulfilled != 10 || item.QuantityPledged != 0 {
+		t.Errorf("item after confirm = %+v", item)
+	}
+
+	if _, err := service.ConfirmPledge(ctx, wishlist.TeacherID, pledge.ID); !errors.Is(err, ErrPledgeNotHeld) {
+		t.Errorf("second ConfirmPledge() error = %v, want ErrPledgeNotHeld", err)
+	}
+	if _, err := service.CancelPledge(ctx, PledgeCanceller{TeacherID: wishlist.TeacherID}, pledge.ID); !errors.Is(err, ErrPledgeNotHeld) {
+		t.Errorf("CancelPledge() after confirm error = %v, want ErrPledgeNotHeld", err)
+	}
+}
+
+func TestService_ConfirmPledge_AfterHoldLapsed(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue := wishlist.Items[0]
+	pledge := claim(t, service, wishlist, glue.ID, 10)
+
+	service.now = func() time.Time { return testNow.Add(49 * time.Hour) }
+	if _, err := service.ConfirmPledge(ctx, wishlist.TeacherID, pledge.ID); !errors.Is(err, ErrPledgeExpired) {
+		t.Errorf("ConfirmPledge() error = %v, want ErrPledgeExpired", err)
+	}
+
+	stored, _ := repo.GetPledge(ctx, pledge.ID)
+	if stored.Status != PledgeExpired {
+		t.Errorf("pledge status = %s, want expired", stored.Status)
+	}
+}
+
+func TestService_CancelPledge(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue := wishlist.Items[0]
+	pledge := claim(t, service, wishlist, glue.ID, 10)
+
+	if _, err := service.CancelPledge(ctx, PledgeCanceller{DonorEmail: "someone@example.com"}, pledge.ID); !errors.Is(err, ErrNotPledgeDonor) {
+		t.Errorf("CancelPledge() by another donor error = %v, want ErrNotPledgeDonor", err)
+	}
+	if _, err := service.CancelPledge(ctx, PledgeCanceller{TeacherID: "teacher-2"}, pledge.ID); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("CancelPledge() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+
+	cancelled, err := service.CancelPledge(ctx, PledgeCanceller{DonorEmail: " PAT@example.com "}, pledge.ID)
+	if err != nil || cancelled.Status != PledgeCancelled {
+		t.Fatalf("CancelPledge() by the donor = %+v, %v", cancelled, err)
+	}
+
+	stored, _ := repo.GetByID(ctx, wishlist.ID)
+	if item := stored.Item(glue.ID); item.QuantityPledged != 0 || item.QuantityFulfilled != 0 {
+		t.Errorf("item after cancel = %+v", item)
+	}
+
+	second := claim(t, service, wishlist, glue.ID, 5)
+	if _, err := service.CancelPledge(ctx, PledgeCanceller{TeacherID: wishlist.TeacherID}, second.ID); err != nil {
+		t.Errorf("CancelPledge() by the teacher error = %v", err)
+	}
+
+	if _, err := service.CancelPledge(ctx, PledgeCanceller{TeacherID: wishlist.TeacherID}, "missing"); !errors.Is(err, ErrPledgeNotFound) {
+		t.Errorf("CancelPledge() unknown error = %v", err)
+	}
+}
+
+func TestService_ExpirePledges(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+
+	old := claim(t, service, wishlist, glue.ID, 10)
+	service.now = func() time.Time { return testNow.Add(24 * time.Hour) }
+	recent := claim(t, service, wishlist, headphones.ID, 2)
+
+	service.now = func() time.Time { return testNow.Add(48 * time.Hour) }
+	expired, err := service.ExpirePledges(ctx)
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpirePledges() = %d, %v, want 1", expired, err)
+	}
+
+	pledges, _ := service.ListPledges(ctx, wishlist.ID)
+	if len(pledges) != 2 || pledges[0].ID != old.ID || pledges[0].Status != PledgeExpired || pledges[1].ID != recent.ID || !pledges[1].IsHeld() {
+		t.Errorf("pledges after expiry = %+v", pledges)
+	}
+
+	stored, _ := repo.GetByID(ctx, wishlist.ID)
+	if stored.Item(glue.ID).QuantityPledged != 0 || stored.Item(headphones.ID).QuantityPledged != 2 {
+		t.Errorf("items after expiry = %+v", stored.Items)
+	}
+}
+
+func TestService_RunPledgeExpiry_DefaultInterval(t *testing.T) {
+	service, _ := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A non-positive interval falls back to the default instead of panicking
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		if err := service.RunPledgeExpiry(ctx, interval); !errors.Is(err, context.Canceled) {
+			t.Errorf("RunPledgeExpiry(%v) error = %v, want %v", interval, err, context.Canceled)
+		}
+		if err := service.RunWishlistArchiver(ctx, interval); !errors.Is(err, context.Canceled) {
+			t.Errorf("RunWishlistArchiver(%v) error = %v, want %v", interval, err, context.Canceled)
+		}
+	}
+}
+
+func TestService_UpdateWishlist_PledgedItems(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+	claim(t, service, wishlist, headphones.ID, 4)
+
+	fresh, _ := service.GetWishlist(ctx, wishlist.ID)
+	_, err := service.UpdateWishlist(ctx, "teacher-1", fresh.ID, UpdateWishlistInput{
+		Title: fresh.Title,
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 30},
+			{ID: headphones.ID, Name: "Headphones", Quantity: 3},
+		},
+	})
+
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeItemQuantityBelowFilled) {
+		t.Errorf("UpdateWishlist() error = %v, want %s", err, shared.CodeItemQuantityBelowFilled)
+	}
+
+	_, err = service.UpdateWishlist(ctx, "teacher-1", fresh.ID, UpdateWishlistInput{
+		Title: fresh.Title,
+		Items: []WishlistItemInput{{ID: glue.ID, Name: "Glue sticks", Quantity: 30}},
+	})
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeItemFulfilledRemoval) {
+		t.Errorf("UpdateWishlist() removing pledged item error = %v", err)
+	}
+
+	updated, err := service.UpdateWishlist(ctx, "teacher-1", fresh.ID, UpdateWishlistInput{
+		Title: fresh.Title,
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 30},
+			{ID: headphones.ID, Name: "Headphones", Quantity: 4},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+	if item := updated.Item(headphones.ID); item.QuantityPledged != 4 || item.QuantityAvailable() != 0 {
+		t.Errorf("headphones after update = %+v", item)
+	}
+}
+
+func TestService_CreateWishlist_UnknownTeacher(t *testing.T) {
+	service, _ := newTestService()
+
+	_, err := service.CreateWishlist(context.Background(), CreateWishlistInput{TeacherID: "nobody", Title: "Supplies"})
+	if !errors.Is(err, ErrTeacherNotFound) {
+		t.Errorf("CreateWishlist() error = %v, want ErrTeacherNotFound", err)
+	}
+}
+
+func TestService_CreateTeacher(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	teacher, err := service.CreateTeacher(ctx, CreateTeacherInput{
+		SchoolID: "school-1",
+		Name:     " Jordan Lee ",
+		Email:    "Jordan.Lee@Example.edu",
+	})
+	if err != nil {
+		t.Fatalf("CreateTeacher() error = %v", err)
+	}
+	if teacher.Name != "Jordan Lee" || teacher.Email != "jordan.lee@example.edu" {
+		t.Errorf("teacher = %+v, want trimmed name and lower-case email", teacher)
+	}
+	if teacher.ValidationState.Status != ValidationUnverified || len(teacher.ValidationState.History) != 0 {
+		t.Errorf("ValidationState = %+v, want unverified without history", teacher.ValidationState)
+	}
+
+	_, err = service.CreateTeacher(ctx, CreateTeacherInput{SchoolID: "school-2", Name: "Other", Email: "JORDAN.LEE@example.edu"})
+	if !errors.Is(err, ErrTeacherEmailTaken) {
+		t.Errorf("duplicate email error = %v, want ErrTeacherEmailTaken", err)
+	}
+
+	_, err = service.CreateTeacher(ctx, CreateTeacherInput{Email: "not-an-email"})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 3 {
+		t.Errorf("invalid teacher error = %v, want 3 field errors", err)
+	}
+
+	_, err = service.CreateTeacher(ctx, CreateTeacherInput{SchoolID: "school-9", Name: "Sam", Email: "sam@example.edu"})
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeTeacherSchoolUnknown) {
+		t.Errorf("unknown school error = %v, want %s", err, shared.CodeTeacherSchoolUnknown)
+	}
+
+	// A school merged into another is resolved to the surviving school
+	merged, err := service.CreateTeacher(ctx, CreateTeacherInput{SchoolID: "school-3", Name: "Sam", Email: "sam@example.edu"})
+	if err != nil || merged.SchoolID != "school-2" {
+		t.Errorf("CreateTeacher() at merged school = %+v, %v, want school-2", merged, err)
+	}
+}
+
+func TestService_SubmitForReview(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	teacher, err := service.SubmitForReview(ctx, "teacher-1")
+	if err != nil {
+		t.Fatalf("SubmitForReview() error = %v", err)
+	}
+
+	last, ok := teacher.ValidationState.LastTransition()
+	if teacher.ValidationState.Status != ValidationPendingReview || !ok ||
+		last.From != ValidationUnverified || last.Actor != "teacher-1" || !last.At.Equal(testNow) {
+		t.Errorf("ValidationState = %+v", teacher.ValidationState)
+	}
+
+	stored, _ := service.GetTeacher(ctx, "teacher-1")
+	if stored.ValidationState.Status != ValidationPendingReview || stored.Version != 2 {
+		t.Errorf("stored teacher = %+v", stored)
+	}
+
+	if _, err := service.SubmitForReview(ctx, "teacher-1"); !errors.Is(err, ErrInvalidValidationTransition) {
+		t.Errorf("second SubmitForReview() error = %v, want ErrInvalidValidationTransition", err)
+	}
+}
+
+func TestService_Revisions(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	wishlist := createTestWishlist(t, service)
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+
+	service.now = func() time.Time { return testNow.Add(time.Hour) }
+	_, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title:       "Room 12 supplies",
+		Description: "For the fall semester",
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 40, UnitPriceCents: 59, Category: CategorySupplies},
+			{Name: "Scissors", Quantity: 12, UnitPriceCents: 199},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+
+	// Recording a donation changes quantities but is not an edit
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, glue.ID, 5); err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+
+	revisions, err := service.ListRevisions(ctx, "teacher-1", wishlist.ID)
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("ListRevisions() = %d revisions, %v", len(revisions), err)
+	}
+	if revisions[0].Source != RevisionCreated || revisions[1].Source != RevisionUpdated ||
+		!revisions[1].CreatedAt.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("revisions = %+v, %+v", revisions[0], revisions[1])
+	}
+
+	diff, err := service.DiffRevisions(ctx, "teacher-1", wishlist.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions() error = %v", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0] != (FieldChange{Field: "description", From: "", To: "For the fall semester"}) {
+		t.Errorf("diff.Changes = %+v", diff.Changes)
+	}
+	if len(diff.AddedItems) != 1 || diff.AddedItems[0].Name != "Scissors" {
+		t.Errorf("diff.AddedItems = %+v", diff.AddedItems)
+	}
+	if len(diff.RemovedItems) != 1 || diff.RemovedItems[0].ID != headphones.ID {
+		t.Errorf("diff.RemovedItems = %+v", diff.RemovedItems)
+	}
+	wantChange := ItemChange{ItemID: glue.ID, Name: "Glue sticks", Changes: []FieldChange{{Field: "quantity_requested", From: "30", To: "40"}}}
+	if len(diff.ChangedItems) != 1 || diff.ChangedItems[0].ItemID != wantChange.ItemID ||
+		len(diff.ChangedItems[0].Changes) != 1 || diff.ChangedItems[0].Changes[0] != wantChange.Changes[0] {
+		t.Errorf("diff.ChangedItems = %+v", diff.ChangedItems)
+	}
+
+	if _, err := service.DiffRevisions(ctx, "teacher-1", wishlist.ID, 1, 9); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("DiffRevisions() with unknown revision error = %v", err)
+	}
+
+	// The history is the owning teacher's alone
+	if _, err := service.ListRevisions(ctx, "teacher-2", wishlist.ID); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("ListRevisions() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+	if _, err := service.GetRevision(ctx, "teacher-2", wishlist.ID, 1); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("GetRevision() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+	if _, err := service.DiffRevisions(ctx, "teacher-2", wishlist.ID, 1, 2); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("DiffRevisions() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+}
+
+func TestService_RestoreRevision(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	wishlist := createTestWishlist(t, service)
+	glue, headphones := wishlist.Items[0], wishlist.Items[1]
+
+	// Accidentally wipe everything but the glue sticks, which have a donation
+	if _, err := service.RecordFulfillment(ctx, "teacher-1", wishlist.ID, glue.ID, 10); err != nil {
+		t.Fatalf("RecordFulfillment() error = %v", err)
+	}
+	if _, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title: "x",
+		Items: []WishlistItemInput{{ID: glue.ID, Name: "Glue sticks", Quantity: 10}},
+	}); err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+
+	if _, err := service.RestoreRevision(ctx, "teacher-2", wishlist.ID, 1); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("RestoreRevision() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+
+	restored, err := service.RestoreRevision(ctx, "teacher-1", wishlist.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+
+	if restored.Title != "Room 12 supplies" || restored.Revision != 3 || len(restored.Items) != 2 {
+		t.Fatalf("restored wishlist = %+v", restored)
+	}
+	if item := restored.Item(glue.ID); item == nil || item.QuantityRequested != 30 || item.QuantityFulfilled != 10 {
+		t.Errorf("glue sticks = %+v, want original quantity with fulfillment kept", item)
+	}
+	if restored.Item(headphones.ID) != nil || restored.Items[1].Name != "Headphones" {
+		t.Errorf("removed item should come back as a new item, got %+v", restored.Items[1])
+	}
+
+	revision, _ := service.GetRevision(ctx, "teacher-1", wishlist.ID, 3)
+	if revision.Source != RevisionRestored || revision.RestoredFrom != 1 {
+		t.Errorf("restore revision = %+v", revision)
+	}
+	if diff, _ := service.DiffRevisions(ctx, "teacher-1", wishlist.ID, 1, 3); len(diff.Changes) != 0 || len(diff.ChangedItems) != 0 {
+		t.Errorf("restored content differs from revision 1: %+v", diff)
+	}
+}
+
+func TestService_RestoreRevision_KeepsQuantityInvariants(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	glue := wishlist.Items[0]
+
+	if _, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title: wishlist.Title,
+		Items: []WishlistItemInput{
+			{ID: glue.ID, Name: "Glue sticks", Quantity: 30},
+			{Name: "Paint", Quantity: 6},
+		},
+	}); err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+
+	current, _ := service.GetWishlist(ctx, wishlist.ID)
+	claim(t, service, current, current.Items[1].ID, 2)
+
+	// Revision 1 has no paint, but two jars are already pledged
+	_, err := service.RestoreRevision(ctx, "teacher-1", wishlist.ID, 1)
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeItemFulfilledRemoval) {
+		t.Errorf("RestoreRevision() error = %v, want %s", err, shared.CodeItemFulfilledRemoval)
+	}
+}
+
+func verifyTestTeacher(t *testing.T, teachers *memoryTeacherRepository) {
+	t.Helper()
+
+	teacher := teachers.teachers["teacher-1"]
+	state, err := teacher.ValidationState.TransitionTo(ValidationPendingReview, "teacher-1", "", testNow)
+	if err == nil {
+		state, err = state.TransitionTo(ValidationVerified, "admin-1", "", testNow)
+	}
+	if err != nil {
+		t.Fatalf("TransitionTo() error = %v", err)
+	}
+	teacher.ValidationState = state
+}
+
+func TestService_PublishWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, _, teachers := newTestServiceWithTeachers()
+	wishlist := createTestWishlist(t, service)
+	if wishlist.Status != WishlistDraft {
+		t.Fatalf("Status = %s, want draft", wishlist.Status)
+	}
+
+	if _, err := service.PublishWishlist(ctx, "teacher-1", wishlist.ID, PublishInput{}); !errors.Is(err, ErrTeacherNotVerified) {
+		t.Errorf("PublishWishlist() by unverified teacher error = %v, want ErrTeacherNotVerified", err)
+	}
+
+	verifyTestTeacher(t, teachers)
+	teachers.teachers["teacher-2"] = &Teacher{ID: "teacher-2", ValidationState: teachers.teachers["teacher-1"].ValidationState}
+	if _, err := service.PublishWishlist(ctx, "teacher-2", wishlist.ID, PublishInput{}); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("PublishWishlist() by another teacher error = %v, want ErrNotWishlistOwner", err)
+	}
+
+	publishAt := testNow.Add(24 * time.Hour)
+	published, err := service.PublishWishlist(ctx, "teacher-1", wishlist.ID, PublishInput{PublishAt: &publishAt})
+	if err != nil {
+		t.Fatalf("PublishWishlist() error = %v", err)
+	}
+	if published.Status != WishlistPublished || !published.ExpireAt.Equal(SchoolYearEnd(publishAt)) {
+		t.Errorf("published wishlist = %+v", published)
+	}
+	if published.Revision != wishlist.Revision {
+		t.Errorf("Revision = %d, publishing must not create a revision", published.Revision)
+	}
+
+	// Not visible to donors until the scheduled publish time
+	_, err = service.ClaimItem(ctx, ClaimInput{
+		WishlistID: wishlist.ID,
+		ItemID:     wishlist.Items[0].ID,
+		DonorName:  "Pat Donor",
+		DonorEmail: "pat@example.com",
+		Quantity:   1,
+	})
+	if !errors.Is(err, ErrWishlistNotVisible) {
+		t.Errorf("ClaimItem() before publish time error = %v, want ErrWishlistNotVisible", err)
+	}
+}
+
+func TestService_PauseArchiveReopen(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	wishlist := publishTestWishlist(t, repo, createTestWishlist(t, service))
+
+	paused, err := service.PauseWishlist(ctx, "teacher-1", wishlist.ID)
+	if err != nil || paused.Status != WishlistPaused {
+		t.Fatalf("PauseWishlist() = %+v, %v", paused, err)
+	}
+	if _, err := service.PauseWishlist(ctx, "teacher-1", wishlist.ID); !errors.Is(err, ErrInvalidWishlistTransition) {
+		t.Errorf("second PauseWishlist() error = %v, want ErrInvalidWishlistTransition", err)
+	}
+
+	archived, err := service.ArchiveWishlist(ctx, "teacher-1", wishlist.ID)
+	if err != nil || archived.ArchivedAt == nil {
+		t.Fatalf("ArchiveWishlist() = %+v, %v", archived, err)
+	}
+	if _, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{Title: "Renamed"}); !errors.Is(err, ErrWishlistArchived) {
+		t.Errorf("UpdateWishlist() on archived list error = %v, want ErrWishlistArchived", err)
+	}
+	if _, err := service.RestoreRevision(ctx, "teacher-1", wishlist.ID, 1); !errors.Is(err, ErrWishlistArchived) {
+		t.Errorf("RestoreRevision() on archived list error = %v, want ErrWishlistArchived", err)
+	}
+
+	reopened, err := service.ReopenWishlist(ctx, "teacher-1", wishlist.ID)
+	if err != nil || reopened.Status != WishlistDraft || reopened.ExpireAt != nil {
+		t.Fatalf("ReopenWishlist() = %+v, %v", reopened, err)
+	}
+	if _, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{Title: "Renamed"}); err != nil {
+		t.Errorf("UpdateWishlist() on reopened list error = %v", err)
+	}
+}
+
+func TestService_ArchiveExpiredWishlists(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	expiring := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	draft := createTestWishlist(t, service)
+
+	if archived, err := service.ArchiveExpiredWishlists(ctx); err != nil || archived != 0 {
+		t.Fatalf("ArchiveExpiredWishlists() = %d, %v, want nothing archived yet", archived, err)
+	}
+
+	service.now = func() time.Time { return *expiring.ExpireAt }
+	if archived, err := service.ArchiveExpiredWishlists(ctx); err != nil || archived != 1 {
+		t.Fatalf("ArchiveExpiredWishlists() = %d, %v, want 1", archived, err)
+	}
+
+	stored, _ := service.GetWishlist(ctx, expiring.ID)
+	if stored.Status != WishlistArchived || stored.Version != expiring.Version+1 {
+		t.Errorf("expired wishlist = %+v", stored)
+	}
+	if stored, _ := service.GetWishlist(ctx, draft.ID); stored.Status != WishlistDraft {
+		t.Errorf("draft Status = %s, drafts never expire", stored.Status)
+	}
+}
+
+func TestService_ImportWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+
+	result, err := service.ImportWishlist(ctx, ImportWishlistInput{
+		TeacherID: "teacher-1",
+		Title:     "Imported list",
+		Format:    ImportCSV,
+		File:      strings.NewReader("name,qty,price\nPencils,10,$0.25\nGlue,some,1\n"),
+	})
+	if err != nil {
+		t.Fatalf("ImportWishlist() error = %v", err)
+	}
+	if result.Wishlist.Status != WishlistDraft || len(result.Wishlist.Items) != 1 {
+		t.Errorf("Wishlist = %+v, want a draft with one item", result.Wishlist)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Line != 3 {
+		t.Errorf("Skipped = %+v, want line 3", result.Skipped)
+	}
+	if revisions, _ := repo.ListRevisions(ctx, result.Wishlist.ID); len(revisions) != 1 {
+		t.Errorf("revisions = %d, want the created revision", len(revisions))
+	}
+}
+
+func TestService_ImportWishlist_NoItems(t *testing.T) {
+	service, repo := newTestService()
+
+	_, err := service.ImportWishlist(context.Background(), ImportWishlistInput{
+		TeacherID: "teacher-1",
+		Title:     "Imported list",
+		Format:    ImportCSV,
+		File:      strings.NewReader("name,qty\nGlue,some\n"),
+	})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeImportNoItems) || !verr.HasCode(shared.CodeImportQuantityFormat) {
+		t.Fatalf("ImportWishlist() error = %v, want no items and the row error", err)
+	}
+	if verr.Fields[1].Field != "rows[2].quantity_requested" {
+		t.Errorf("row error field = %q", verr.Fields[1].Field)
+	}
+	if len(repo.wishlists) != 0 {
+		t.Error("no wishlist should be created")
+	}
+}
+
+func TestService_CreateWishlist_Scope(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	wishlist, err := service.CreateWishlist(ctx, CreateWishlistInput{
+		TeacherID:     "teacher-1",
+		Title:         "Field trip",
+		WishlistScope: WishlistScope{GradeLevel: " K ", Subject: " Science ", StudentCount: 22},
+	})
+	if err != nil {
+		t.Fatalf("CreateWishlist() error = %v", err)
+	}
+	want := WishlistScope{GradeLevel: GradeKindergarten, Subject: "Science", StudentCount: 22, Visibility: VisibilityPublic}
+	if wishlist.WishlistScope != want {
+		t.Errorf("WishlistScope = %+v, want %+v", wishlist.WishlistScope, want)
+	}
+
+	updated, err := service.UpdateWishlist(ctx, "teacher-1", wishlist.ID, UpdateWishlistInput{
+		Title:         "Field trip",
+		WishlistScope: WishlistScope{GradeLevel: GradeMixed, Visibility: VisibilityUnlisted},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWishlist() error = %v", err)
+	}
+
+	restored, err := service.RestoreRevision(ctx, "teacher-1", wishlist.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored.WishlistScope != updated.WishlistScope {
+		t.Errorf("restore changed the scope to %+v, want %+v", restored.WishlistScope, updated.WishlistScope)
+	}
+}
+
+func TestService_CreateWishlist_LimitPerTeacher(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	WithMaxWishlistsPerTeacher(2)(service)
+
+	first := createTestWishlist(t, service)
+	createTestWishlist(t, service)
+	if _, err := service.CreateWishlist(ctx, CreateWishlistInput{TeacherID: "teacher-1", Title: "Third"}); !errors.Is(err, ErrWishlistLimitReached) {
+		t.Fatalf("CreateWishlist() over the limit error = %v, want ErrWishlistLimitReached", err)
+	}
+
+	// Archived lists do not count, but reopening one does
+	if _, err := service.ArchiveWishlist(ctx, "teacher-1", first.ID); err != nil {
+		t.Fatalf("ArchiveWishlist() error = %v", err)
+	}
+	third, err := service.CreateWishlist(ctx, CreateWishlistInput{TeacherID: "teacher-1", Title: "Third"})
+	if err != nil {
+		t.Fatalf("CreateWishlist() after archiving error = %v", err)
+	}
+	if _, err := service.ReopenWishlist(ctx, "teacher-1", first.ID); !errors.Is(err, ErrWishlistLimitReached) {
+		t.Errorf("ReopenWishlist() over the limit error = %v, want ErrWishlistLimitReached", err)
+	}
+
+	if _, err := service.ArchiveWishlist(ctx, "teacher-1", third.ID); err != nil {
+		t.Fatalf("ArchiveWishlist() error = %v", err)
+	}
+	if _, err := service.ReopenWishlist(ctx, "teacher-1", first.ID); err != nil {
+		t.Errorf("ReopenWishlist() error = %v", err)
+	}
+
+	wishlists, _ := service.ListWishlists(ctx, "teacher-1")
+	if len(wishlists) != 3 {
+		t.Errorf("ListWishlists() = %d wishlists, want 3", len(wishlists))
+	}
+}
+
+func TestService_PrimaryWishlist(t *testing.T) {
+	ctx := context.Background()
+	service, repo, teachers := newTestServiceWithTeachers()
+
+	if _, err := service.PrimaryWishlist(ctx, "teacher-1"); !errors.Is(err, ErrWishlistNotFound) {
+		t.Errorf("PrimaryWishlist() without listed wishlists error = %v, want ErrWishlistNotFound", err)
+	}
+
+	older := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	newer := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	repo.wishlists[newer.ID].UpdatedAt = testNow.Add(time.Hour)
+
+	featured, err := service.PrimaryWishlist(ctx, "teacher-1")
+	if err != nil || featured.ID != newer.ID {
+		t.Fatalf("PrimaryWishlist() = %v, %v, want the most recently published list", featured, err)
+	}
+
+	teacher, err := service.SetPrimaryWishlist(ctx, "teacher-1", older.ID)
+	if err != nil || teacher.PrimaryWishlistID != older.ID {
+		t.Fatalf("SetPrimaryWishlist() = %+v, %v", teacher, err)
+	}
+	if stored := teachers.teachers["teacher-1"]; stored.PrimaryWishlistID != older.ID || stored.Version != 2 {
+		t.Errorf("stored teacher = %+v", stored)
+	}
+	if featured, _ := service.PrimaryWishlist(ctx, "teacher-1"); featured.ID != older.ID {
+		t.Errorf("PrimaryWishlist() = %s, want the primary list", featured.ID)
+	}
+
+	// A paused primary list is not shown; the profile falls back to a listed one
+	if _, err := service.PauseWishlist(ctx, "teacher-1", older.ID); err != nil {
+		t.Fatalf("PauseWishlist() error = %v", err)
+	}
+	if featured, _ := service.PrimaryWishlist(ctx, "teacher-1"); featured.ID != newer.ID {
+		t.Errorf("PrimaryWishlist() = %s, want the fallback list", featured.ID)
+	}
+
+	teachers.teachers["teacher-2"] = &Teacher{ID: "teacher-2"}
+	if _, err := service.SetPrimaryWishlist(ctx, "teacher-2", older.ID); !errors.Is(err, ErrNotWishlistOwner) {
+		t.Errorf("SetPrimaryWishlist() for another teacher's list error = %v, want ErrNotWishlistOwner", err)
+	}
+}
+
+func TestService_TransferTeacher_MoveWishlists(t *testing.T) {
+	ctx := context.Background()
+	service, repo, teachers := newTestServiceWithTeachers()
+	verifyTestTeacher(t, teachers)
+	published := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	draft := createTestWishlist(t, service)
+	if _, err := service.SetPrimaryWishlist(ctx, "teacher-1", published.ID); err != nil {
+		t.Fatalf("SetPrimaryWishlist() error = %v", err)
+	}
+
+	teacher, err := service.TransferTeacher(ctx, "teacher-1", TransferTeacherInput{SchoolID: " school-2 ", Actor: "admin-1"})
+	if err != nil {
+		t.Fatalf("TransferTeacher() error = %v", err)
+	}
+	if teacher.SchoolID != "school-2" || teacher.ValidationState.Status != ValidationPendingReview {
+		t.Errorf("teacher = %+v, want pending review at school-2", teacher)
+	}
+	want := []SchoolTransfer{{FromSchoolID: "school-1", ToSchoolID: "school-2", Actor: "admin-1", At: testNow}}
+	if !reflect.DeepEqual(teacher.SchoolHistory, want) {
+		t.Errorf("SchoolHistory = %+v, want %+v", teacher.SchoolHistory, want)
+	}
+	if stored := teachers.teachers["teacher-1"]; stored.SchoolID != "school-2" || stored.PrimaryWishlistID != published.ID {
+		t.Errorf("stored teacher = %+v, moved lists keep the primary list", stored)
+	}
+
+	if got := repo.wishlists[published.ID].Status; got != WishlistPaused {
+		t.Errorf("published wishlist Status = %s, want paused until the teacher is verified again", got)
+	}
+	if got := repo.wishlists[draft.ID].Status; got != WishlistDraft {
+		t.Errorf("draft wishlist Status = %s, want draft", got)
+	}
+}
+
+func TestService_TransferTeacher_ArchiveWishlists(t *testing.T) {
+	ctx := context.Background()
+	service, repo, teachers := newTestServiceWithTeachers()
+	verifyTestTeacher(t, teachers)
+	published := publishTestWishlist(t, repo, createTestWishlist(t, service))
+	draft := createTestWishlist(t, service)
+	if _, err := service.SetPrimaryWishlist(ctx, "teacher-1", published.ID); err != nil {
+		t.Fatalf("SetPrimaryWishlist() error = %v", err)
+	}
+
+	teacher, err := service.TransferTeacher(ctx, "teacher-1", TransferTeacherInput{
+		SchoolID:  "school-2",
+		Wishlists: TransferArchiveWishlists,
+		Actor:     "teacher-1",
+	})
+	if err != nil {
+		t.Fatalf("TransferTeacher() error = %v", err)
+	}
+	if teacher.PrimaryWishlistID != "" {
+		t.Errorf("PrimaryWishlistID = %q, want it cleared", teacher.PrimaryWishlistID)
+	}
+	for _, id := range []string{published.ID, draft.ID} {
+		if got := repo.wishlists[id]; got.Status != WishlistArchived || got.ArchivedAt == nil {
+			t.Errorf("wishlist %s = %+v, want archived", id, got)
+		}
+	}
+}
+
+func TestService_TransferTeacher_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  ValidationStatus
+		input   TransferTeacherInput
+		wantErr error
+		code    string
+	}{
+		{"same school", ValidationVerified, TransferTeacherInput{SchoolID: "school-1", Actor: "admin-1"},
+			shared.ErrValidation, shared.CodeTeacherSchoolUnchanged},
+		{"unknown option", ValidationVerified, TransferTeacherInput{SchoolID: "school-2", Wishlists: "copy", Actor: "admin-1"},
+			shared.ErrValidation, shared.CodeTransferWishlistsInvalid},
+		{"unknown school", ValidationVerified, TransferTeacherInput{SchoolID: "school-9", Actor: "admin-1"},
+			shared.ErrValidation, shared.CodeTeacherSchoolUnknown},
+		{"suspended", ValidationSuspended, TransferTeacherInput{SchoolID: "school-2", Actor: "admin-1"},
+			ErrInvalidValidationTransition, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, repo, teachers := newTestServiceWithTeachers()
+			verifyTestTeacher(t, teachers)
+			published := publishTestWishlist(t, repo, createTestWishlist(t, service))
+			teachers.teachers["teacher-1"].ValidationState.Status = tt.status
+
+			_, err := service.TransferTeacher(ctx, "teacher-1", tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransferTeacher() error = %v, want %v", err, tt.wantErr)
+			}
+			var verr *shared.ValidationError
+			if tt.code != "" && (!errors.As(err, &verr) || !verr.HasCode(tt.code)) {
+				t.Errorf("TransferTeacher() error = %v, want %s", err, tt.code)
+			}
+
+			if stored := teachers.teachers["teacher-1"]; stored.SchoolID != "school-1" || stored.Version != 1 {
+				t.Errorf("stored teacher = %+v, want it unchanged", stored)
+			}
+			if got := repo.wishlists[published.ID].Status; got != WishlistPublished {
+				t.Errorf("wishlist Status = %s, a failed transfer must not touch wishlists", got)
+			}
+		})
+	}
+}
+
+func addTestCatalogItem(t *testing.T, service *Service, input CatalogItemInput) *CatalogItem {
+	t.Helper()
+
+	item, err := service.AddCatalogItem(context.Background(), input)
+	if err != nil {
+		t.Fatalf("AddCatalogItem() error = %v", err)
+	}
+	return item
+}
+
+func TestService_AddCatalogItem(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+
+	item := addTestCatalogItem(t, service, CatalogItemInput{
+		Name:              " Glue Sticks ",
+		Category:          "Supplies",
+		TypicalPriceCents: 59,
+		Aliases:           []string{" glue stick 30 pack "},
+	})
+	if item.Name != "Glue Sticks" || item.Category != CategorySupplies || item.Aliases[0] != "glue stick 30 pack" {
+		t.Errorf("AddCatalogItem() = %+v", item)
+	}
+
+	// "Glue stick" normalizes to the same name as "Glue Sticks"
+	_, err := service.AddCatalogItem(ctx, CatalogItemInput{Name: "Washable glue", Aliases: []string{"Glue stick"}})
+	if !errors.Is(err, ErrCatalogNameTaken) {
+		t.Errorf("AddCatalogItem() with a taken alias error = %v, want ErrCatalogNameTaken", err)
+	}
+
+	_, err = service.AddCatalogItem(ctx, CatalogItemInput{Name: "", TypicalPriceCents: -1})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeCatalogNameRequired) || !verr.HasCode(shared.CodeCatalogPriceRange) {
+		t.Errorf("AddCatalogItem() error = %v, want name and price errors", err)
+	}
+
+	updated, err := service.UpdateCatalogItem(ctx, item.ID, CatalogItemInput{
+		Name:     "Glue Sticks",
+		Category: CategorySupplies,
+		Aliases:  []string{"glue stick 30 pack", "Elmer's glue sticks"},
+	})
+	if err != nil || len(updated.Aliases) != 2 || updated.Version != 2 {
+		t.Errorf("UpdateCatalogItem() = %+v, %v", updated, err)
+	}
+}
+
+func TestService_SuggestCatalogItems(t *testing.T) {
+	service, _ := newTestService()
+	crayons := addTestCatalogItem(t, service, CatalogItemInput{Name: "Crayola Crayons 24ct", Category: CategoryArt})
+	addTestCatalogItem(t, service, CatalogItemInput{Name: "Colored Pencils 12ct", Category: CategoryArt})
+	addTestCatalogItem(t, service, CatalogItemInput{Name: "Tissues", Category: CategoryHygiene})
+
+	matches, err := service.SuggestCatalogItems(context.Background(), "crayons", 5)
+	if err != nil {
+		t.Fatalf("SuggestCatalogItems() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Item.ID != crayons.ID {
+		t.Errorf("SuggestCatalogItems() = %+v, want only the crayons", matches)
+	}
+}
+
+func TestService_CreateWishlist_LinksCatalogItems(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestService()
+	crayons := addTestCatalogItem(t, service, CatalogItemInput{
+		Name:              "Crayola Crayons 24ct",
+		Category:          CategoryArt,
+		TypicalPriceCents: 129,
+	})
+	glue := addTestCatalogItem(t, service, CatalogItemInput{
+		Name:              "Glue Sticks",
+		Category:          CategorySupplies,
+		TypicalPriceCents: 59,
+		Aliases:           []string{"school glue stick"},
+	})
+
+	wishlist, err := service.CreateWishlist(ctx, CreateWishlistInput{
+		TeacherID: "teacher-1",
+		Title:     "Room 12",
+		Items: []WishlistItemInput{
+			{CatalogItemID: crayons.ID, Quantity: 10},
+			{Name: "Elmer's glue sticks, 30 pack", Quantity: 2, UnitPriceCents: 1499},
+			{Name: "Classroom rug", Quantity: 1, Category: CategoryFurniture},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWishlist() error = %v", err)
+	}
+
+	picked := wishlist.Items[0]
+	if picked.Name != crayons.Name || picked.UnitPriceCents != 129 || picked.Category != CategoryArt ||
+		picked.CatalogItemID != crayons.ID {
+		t.Errorf("picked item = %+v, want the catalog name, price and category", picked)
+	}
+	matched := wishlist.Items[1]
+	if matched.CatalogItemID != glue.ID || matched.Name != "Elmer's glue sticks, 30 pack" ||
+		matched.UnitPriceCents != 1499 || matched.Category != CategorySupplies {
+		t.Errorf("matched item = %+v, want it linked to glue sticks keeping its own name and price", matched)
+	}
+	if rug := wishlist.Items[2]; rug.CatalogItemID != "" {
+		t.Errorf("unmatched item CatalogItemID = %q, want none", rug.CatalogItemID)
+	}
+
+	_, err = service.CreateWishlist(ctx, CreateWishlistInput{
+		TeacherID: "teacher-1",
+		Title:     "Room 12",
+		Items:     []WishlistItemInput{{CatalogItemID: "missing", Quantity: 1}},
+	})
+	var verr *shared.ValidationError
+	if !errors.As(err, &verr) || !verr.HasCode(shared.CodeItemCatalogUnknown) {
+		t.Errorf("CreateWishlist() with an unknown catalog item error = %v, want %s", err, shared.CodeItemCatalogUnknown)
+	}
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"hrh-backend/internal/admin"
+	"hrh-backend/internal/schooldirectory"
+)
+
+// BulkImportJobRepository implements admin.BulkImportJobRepository. Skipped rows
+// are stored as a JSONB array.
+type BulkImportJobRepository struct {
+	db *sql.DB
+}
+
+var _ admin.BulkImportJobRepository = (*BulkImportJobRepository)(nil)
+
+// NewBulkImportJobRepository creates a BulkImportJobRepository
+func NewBulkImportJobRepository(db *sql.DB) *BulkImportJobRepository {
+	return &BulkImportJobRepository{db: db}
+}
+
+const bulkImportJobColumns = `id, kind, source_path, status, started_by, created, updated, unchanged, skipped,
+	error, started_at, finished_at`
+
+// Create stores a new job
+func (r *BulkImportJobRepository) Create(ctx context.Context, job *admin.BulkImportJob) error {
+	skipped, err := json.Marshal(job.Skipped)
+	if err != nil {
+		return fmt.Errorf("encode skipped rows: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO bulk_import_jobs (`+bulkImportJobColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		job.ID, job.Kind, job.SourcePath, job.Status, job.StartedBy, job.Created, job.Updated, job.Unchanged,
+		skipped, job.Error, job.StartedAt, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("insert bulk import job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a job
+func (r *BulkImportJobRepository) GetByID(ctx context.Context, id string) (*admin.BulkImportJob, error) {
+	var job admin.BulkImportJob
+	var skipped []byte
+	var finishedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT `+bulkImportJobColumns+` FROM bulk_import_jobs WHERE id = $1`, id).
+		Scan(&job.ID, &job.Kind, &job.SourcePath, &job.Status, &job.StartedBy, &job.Created, &job.Updated,
+			&job.Unchanged, &skipped, &job.Error, &job.StartedAt, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", admin.ErrBulkImportJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan bulk import job: %w", err)
+	}
+
+	job.FinishedAt = nullTimePtr(finishedAt)
+	job.Skipped = []schooldirectory.NCESRowError{}
+	if err := json.Unmarshal(skipped, &job.Skipped); err != nil {
+		return nil, fmt.Errorf("decode skipped rows: %w", err)
+	}
+	return &job, nil
+}
+
+// Update saves the job's status and results
+func (r *BulkImportJobRepository) Update(ctx context.Context, job *admin.BulkImportJob) error {
+	skipped, err := json.Marshal(job.Skipped)
+	if err != nil {
+		return fmt.Errorf("encode skipped rows: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE bulk_import_jobs
+		SET status = $2, created = $3, updated = $4, unchanged = $5, skipped = $6, error = $7, finished_at = $8
+		WHERE id = $1`,
+		job.ID, job.Status, job.Created, job.Updated, job.Unchanged, skipped, job.Error, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("update bulk import job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update bulk import job: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", admin.ErrBulkImportJobNotFound, job.ID)
+	}
+
+	return nil
+}
+
+// DuplicateScanRepository implements admin.DuplicateScanRepository. The pairs
+// found are stored as a JSONB array.
+type DuplicateScanRepository struct {
+	db *sql.DB
+}
+
+var _ admin.DuplicateScanRepository = (*DuplicateScanRepository)(nil)
+
+// NewDuplicateScanRepository creates a DuplicateScanRepository
+func NewDuplicateScanRepository(db *sql.DB) *DuplicateScanRepository {
+	return &DuplicateScanRepository{db: db}
+}
+
+const duplicateScanColumns = `id, status, started_by, duplicates, error, started_at, finished_at`
+
+// Create stores a new scan
+func (r *DuplicateScanRepository) Create(ctx context.Context, scan *admin.DuplicateScan) error {
+	duplicates, err := json.Marshal(scan.Duplicates)
+	if err != nil {
+		return fmt.Errorf("encode duplicate schools: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO school_duplicate_scans (`+duplicateScanColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		scan.ID, scan.Status, scan.StartedBy, duplicates, scan.Error, scan.StartedAt, scan.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("insert duplicate scan: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a scan
+func (r *DuplicateScanRepository) GetByID(ctx context.Context, id string) (*admin.DuplicateScan, error) {
+	var scan admin.DuplicateScan
+	var duplicates []byte
+	var finishedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT `+duplicateScanColumns+` FROM school_duplicate_scans WHERE id = $1`, id).
+		Scan(&scan.ID, &scan.Status, &scan.StartedBy, &duplicates, &scan.Error, &scan.StartedAt, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", admin.ErrDuplicateScanNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan duplicate scan: %w", err)
+	}
+
+	scan.FinishedAt = nullTimePtr(finishedAt)
+	scan.Duplicates = []schooldirectory.SchoolDuplicate{}
+	if err := json.Unmarshal(duplicates, &scan.Duplicates); err != nil {
+		return nil, fmt.Errorf("decode duplicate schools: %w", err)
+	}
+	return &scan, nil
+}
+
+// Update saves the scan's status and results
+func (r *DuplicateScanRepository) Update(ctx context.Context, scan *admin.DuplicateScan) error {
+	duplicates, err := json.Marshal(scan.Duplicates)
+	if err != nil {
+		return fmt.Errorf("encode duplicate schools: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE school_duplicate_scans
+		SET status = $2, duplicates = $3, error = $4, finished_at = $5
+		WHERE id = $1`,
+		scan.ID, scan.Status, duplicates, scan.Error, scan.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("update duplicate scan: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update duplicate scan: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", admin.ErrDuplicateScanNotFound, scan.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"hrh-backend/internal/publicsearch"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// CatalogRepository implements teacherwishlist.CatalogRepository and
+// publicsearch.DemandFinder. Aliases are stored as a JSONB array.
+type CatalogRepository struct {
+	db *sql.DB
+}
+
+var (
+	_ teacherwishlist.CatalogRepository = (*CatalogRepository)(nil)
+	_ publicsearch.DemandFinder         = (*CatalogRepository)(nil)
+)
+
+// NewCatalogRepository creates a CatalogRepository
+func NewCatalogRepository(db *sql.DB) *CatalogRepository {
+	return &CatalogRepository{db: db}
+}
+
+const catalogItemColumns = `id, name, category, typical_price_cents, aliases, version, created_at, updated_at`
+
+// Create stores a new catalog item
+func (r *CatalogRepository) Create(ctx context.Context, item *teacherwishlist.CatalogItem) error {
+	aliases, err := json.Marshal(item.Aliases)
+	if err != nil {
+		return fmt.Errorf("encode catalog aliases: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO catalog_items (`+catalogItemColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		item.ID, item.Name, item.Category, item.TypicalPriceCents, aliases, item.Version,
+		item.CreatedAt, item.UpdatedAt)
+	if isUniqueViolation(err) {
+		return teacherwishlist.ErrCatalogNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("insert catalog item: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a catalog item
+func (r *CatalogRepository) GetByID(ctx context.Context, id string) (*teacherwishlist.CatalogItem, error) {
+	item, err := scanCatalogItem(r.db.QueryRowContext(ctx,
+		`SELECT `+catalogItemColumns+` FROM catalog_items WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", teacherwishlist.ErrCatalogItemNotFound, id)
+	}
+	return item, err
+}
+
+// Update saves the catalog item using optimistic locking on version
+func (r *CatalogRepository) Update(ctx context.Context, item *teacherwishlist.CatalogItem) error {
+	aliases, err := json.Marshal(item.Aliases)
+	if err != nil {
+		return fmt.Errorf("encode catalog aliases: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE catalog_items
+		SET name = $3, category = $4, typical_price_cents = $5, aliases = $6, updated_at = $7,
+		    version = version + 1
+		WHERE id = $1 AND version = $2`,
+		item.ID, item.Version, item.Name, item.Category, item.TypicalPriceCents, aliases, item.UpdatedAt)
+	if isUniqueViolation(err) {
+		return teacherwishlist.ErrCatalogNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("update catalog item: %w", err)
+	}
+
+	err = checkVersionedUpdate(ctx, r.db, result, "catalog_items", item.ID,
+		teacherwishlist.ErrCatalogItemNotFound, teacherwishlist.ErrCatalogVersionConflict)
+	if err != nil {
+		return err
+	}
+
+	item.Version++
+	return nil
+}
+
+// List returns every catalog item ordered by name
+func (r *CatalogRepository) List(ctx context.Context) ([]*teacherwishlist.CatalogItem, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+catalogItemColumns+` FROM catalog_items ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list catalog items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*teacherwishlist.CatalogItem{}
+	for rows.Next() {
+		item, err := scanCatalogItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list catalog items: %w", err)
+	}
+
+	return items, nil
+}
+
+// FindItemDemand counts, per catalog item, the classrooms whose listed wishlists
+// still need it. The county is compared the way publicsearch normalizes it:
+// lower-cased and without a trailing "county".
+func (r *CatalogRepository) FindItemDemand(ctx context.Context,
+	query publicsearch.DemandQuery) ([]publicsearch.ItemDemand, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.name, c.category, COUNT(DISTINCT w.teacher_id) AS classrooms,
+		       SUM(i.quantity_requested - i.quantity_fulfilled - i.quantity_pledged) AS quantity_needed
+		FROM wishlist_items i
+		JOIN catalog_items c ON c.id = i.catalog_item_id
+		JOIN wishlists w ON w.id = i.wishlist_id
+		JOIN teachers t ON t.id = w.teacher_id
+		JOIN schools s ON s.id = t.school_id
+		WHERE i.quantity_requested > i.quantity_fulfilled + i.quantity_pledged
+		  AND w.status = $1 AND w.visibility = $2 AND t.validation_status = $3
+		  AND (w.publish_at IS NULL OR w.publish_at <= $4)
+		  AND (w.expire_at IS NULL OR w.expire_at > $4)
+		  AND ($5 = '' OR c.id::text = $5)
+		  AND ($6 = '' OR s.address->>'state' = $6)
+		  AND ($7 = '' OR btrim(regexp_replace(lower(s.address->'location'->>'county'), '\s*county\s*$', '')) = $7)
+		GROUP BY c.id, c.name, c.category
+		ORDER BY classrooms DESC, quantity_needed DESC, c.name
+		LIMIT $8`,
+		teacherwishlist.WishlistPublished, teacherwishlist.VisibilityPublic, teacherwishlist.ValidationVerified,
+		query.Now, query.CatalogItemID, query.State, query.County, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("find item demand: %w", err)
+	}
+	defer rows.Close()
+
+	demand := []publicsearch.ItemDemand{}
+	for rows.Next() {
+		var d publicsearch.ItemDemand
+		if err := rows.Scan(&d.CatalogItemID, &d.Name, &d.Category, &d.Classrooms, &d.QuantityNeeded); err != nil {
+			return nil, fmt.Errorf("scan item demand: %w", err)
+		}
+		demand = append(demand, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find item demand: %w", err)
+	}
+
+	return demand, nil
+}
+
+// scanCatalogItem reads the catalogItemColumns of one row
+func scanCatalogItem(row rowScanner) (*teacherwishlist.CatalogItem, error) {
+	var item teacherwishlist.CatalogItem
+	var aliases []byte
+	err := row.Scan(&item.ID, &item.Name, &item.Category, &item.TypicalPriceCents, &aliases, &item.Version,
+		&item.CreatedAt, &item.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan catalog item: %w", err)
+	}
+
+	item.Aliases = []string{}
+	if err := json.Unmarshal(aliases, &item.Aliases); err != nil {
+		return nil, fmt.Errorf("decode catalog aliases: %w", err)
+	}
+	return &item, nil
+}
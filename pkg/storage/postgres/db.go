@@ -0,0 +1,165 @@
+// Package postgres implements the repository interfaces of the internal packages
+// on PostgreSQL through database/sql. The driver is registered by the main
+// package; queries use $n placeholders.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/shared"
+)
+
+// Connection pool defaults
+const (
+	maxOpenConns    = 25
+	maxIdleConns    = 5
+	connMaxLifetime = 30 * time.Minute
+)
+
+// Open connects to the database using a registered driver (e.g. "pgx"),
+// configures the connection pool and verifies the connection
+func Open(ctx context.Context, driverName, dsn string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// withTx runs fn in a transaction, committing if fn succeeds and rolling back
+// otherwise. A transaction aborted by a deadlock or serialization failure
+// returns shared.ErrConflict, so callers can retry it.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		if isConcurrencyFailure(err) {
+			return fmt.Errorf("%w: %w", shared.ErrConflict, err)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isConcurrencyFailure(err) {
+			return fmt.Errorf("%w: %w", shared.ErrConflict, err)
+		}
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// prefixColumns qualifies a comma-separated column list with a table alias, e.g.
+// "id, name" with alias "i" becomes "i.id, i.name"
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, column := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nullIfEmpty stores an empty optional reference as NULL
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// syncWishlistLocations copies the location of each teacher's school onto the
+// teacher's wishlists. filter is a condition on the wishlist w, the teacher t or
+// the school s, with arg as $1.
+func syncWishlistLocations(ctx context.Context, q querier, filter string, arg any) error {
+	_, err := q.ExecContext(ctx, `
+		UPDATE wishlists w
+		SET location = s.location
+		FROM teachers t
+		JOIN schools s ON s.id = t.school_id
+		WHERE t.id = w.teacher_id AND `+filter+`
+		  AND w.location IS DISTINCT FROM s.location`, arg)
+	if err != nil {
+		return fmt.Errorf("sync wishlist locations: %w", err)
+	}
+	return nil
+}
+
+// checkVersionedUpdate turns an optimistic-lock UPDATE on table that touched no
+// rows into notFound if the row is gone, or conflict if its version moved on
+func checkVersionedUpdate(ctx context.Context, q querier, result sql.Result, table, id string,
+	notFound, conflict error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update %s: %w", table, err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var exists bool
+	// table is always a constant from this package
+	err = q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", table, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", notFound, id)
+	}
+
+	return conflict
+}
+
+// PostgreSQL SQLSTATEs the repositories react to
+const (
+	uniqueViolation      = "23505"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// sqlState returns the SQLSTATE of err, or "" if it carries none. Drivers
+// expose it through a SQLState method (e.g. pgconn.PgError).
+func sqlState(err error) string {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	return pgErr.SQLState()
+}
+
+// isUniqueViolation returns true if err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	return sqlState(err) == uniqueViolation
+}
+
+// isConcurrencyFailure returns true if err aborted a transaction because of a
+// deadlock or serialization failure with a concurrent one
+func isConcurrencyFailure(err error) bool {
+	state := sqlState(err)
+	return state == serializationFailure || state == deadlockDetected
+}
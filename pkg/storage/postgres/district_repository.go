@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/shared/domain"
+)
+
+// DistrictRepository implements schooldirectory.DistrictRepository. The address
+// and boundary are stored as JSONB and the admins in the district_admins table.
+type DistrictRepository struct {
+	db *sql.DB
+}
+
+var _ schooldirectory.DistrictRepository = (*DistrictRepository)(nil)
+
+// NewDistrictRepository creates a DistrictRepository
+func NewDistrictRepository(db *sql.DB) *DistrictRepository {
+	return &DistrictRepository{db: db}
+}
+
+const districtColumns = `id, state_agency_id, nces_id, name, address, boundary, version, created_at, updated_at`
+
+// Create stores a new district and its admins
+func (r *DistrictRepository) Create(ctx context.Context, district *schooldirectory.District) error {
+	boundary, err := encodeBoundary(district.Boundary)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO districts (`+districtColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			district.ID, district.StateAgencyID, nullIfEmpty(district.NCESID), district.Name, district.Address,
+			boundary, district.Version, district.CreatedAt, district.UpdatedAt)
+		if isUniqueViolation(err) {
+			return schooldirectory.ErrDistrictNCESIDTaken
+		}
+		if err != nil {
+			return fmt.Errorf("insert district: %w", err)
+		}
+
+		return insertDistrictAdmins(ctx, tx, district)
+	})
+}
+
+// GetByID returns a district with its admins
+func (r *DistrictRepository) GetByID(ctx context.Context, id string) (*schooldirectory.District, error) {
+	district, err := r.getDistrict(ctx, `id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", schooldirectory.ErrDistrictNotFound, id)
+	}
+	return district, err
+}
+
+// GetByNCESID returns the district with an NCES LEAID
+func (r *DistrictRepository) GetByNCESID(ctx context.Context, ncesID string) (*schooldirectory.District, error) {
+	district, err := r.getDistrict(ctx, `nces_id = $1`, ncesID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: NCES ID %s", schooldirectory.ErrDistrictNotFound, ncesID)
+	}
+	return district, err
+}
+
+// getDistrict loads the district matching a condition on one parameter
+func (r *DistrictRepository) getDistrict(ctx context.Context, where string,
+	arg any) (*schooldirectory.District, error) {
+	// where is always a constant from this file
+	district, err := scanDistrict(r.db.QueryRowContext(ctx,
+		`SELECT `+districtColumns+` FROM districts WHERE `+where, arg))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadDistrictAdmins(ctx, r.db, []*schooldirectory.District{district}); err != nil {
+		return nil, err
+	}
+	return district, nil
+}
+
+// Update saves the district using optimistic locking on version and replaces its
+// admins
+func (r *DistrictRepository) Update(ctx context.Context, district *schooldirectory.District) error {
+	boundary, err := encodeBoundary(district.Boundary)
+	if err != nil {
+		return err
+	}
+
+	err = withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE districts
+			SET state_agency_id = $3, nces_id = $4, name = $5, address = $6, boundary = $7, updated_at = $8,
+			    version = version + 1
+			WHERE id = $1 AND version = $2`,
+			district.ID, district.Version, district.StateAgencyID, nullIfEmpty(district.NCESID), district.Name,
+			district.Address, boundary, district.UpdatedAt)
+		if isUniqueViolation(err) {
+			return schooldirectory.ErrDistrictNCESIDTaken
+		}
+		if err != nil {
+			return fmt.Errorf("update district: %w", err)
+		}
+
+		err = checkVersionedUpdate(ctx, tx, result, "districts", district.ID,
+			schooldirectory.ErrDistrictNotFound, schooldirectory.ErrDistrictVersionConflict)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM district_admins WHERE district_id = $1`, district.ID); err != nil {
+			return fmt.Errorf("delete district admins: %w", err)
+		}
+		return insertDistrictAdmins(ctx, tx, district)
+	})
+	if err != nil {
+		return err
+	}
+
+	district.Version++
+	return nil
+}
+
+// ListByStateAgency returns the districts of a state agency ordered by name
+func (r *DistrictRepository) ListByStateAgency(ctx context.Context,
+	stateAgencyID string) ([]*schooldirectory.District, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+districtColumns+`
+		FROM districts
+		WHERE state_agency_id = $1
+		ORDER BY name, id`, stateAgencyID)
+	if err != nil {
+		return nil, fmt.Errorf("list districts: %w", err)
+	}
+	defer rows.Close()
+
+	districts := []*schooldirectory.District{}
+	for rows.Next() {
+		district, err := scanDistrict(rows)
+		if err != nil {
+			return nil, err
+		}
+		districts = append(districts, district)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list districts: %w", err)
+	}
+
+	if err := loadDistrictAdmins(ctx, r.db, districts); err != nil {
+		return nil, err
+	}
+	return districts, nil
+}
+
+// insertDistrictAdmins stores the admins of a district
+func insertDistrictAdmins(ctx context.Context, tx *sql.Tx, district *schooldirectory.District) error {
+	for _, adminID := range district.AdminIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO district_admins (district_id, admin_id) VALUES ($1, $2)`,
+			district.ID, adminID)
+		if err != nil {
+			return fmt.Errorf("insert district admin: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadDistrictAdmins sets the admins of each district
+func loadDistrictAdmins(ctx context.Context, q querier, districts []*schooldirectory.District) error {
+	if len(districts) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*schooldirectory.District, len(districts))
+	placeholders := make([]string, 0, len(districts))
+	args := make([]any, 0, len(districts))
+	for _, district := range districts {
+		district.AdminIDs = []string{}
+		byID[district.ID] = district
+		args = append(args, district.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT district_id, admin_id
+		FROM district_admins
+		WHERE district_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY district_id, admin_id`, args...)
+	if err != nil {
+		return fmt.Errorf("load district admins: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var districtID, adminID string
+		if err := rows.Scan(&districtID, &adminID); err != nil {
+			return fmt.Errorf("scan district admin: %w", err)
+		}
+		if district, ok := byID[districtID]; ok {
+			district.AdminIDs = append(district.AdminIDs, adminID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("load district admins: %w", err)
+	}
+
+	return nil
+}
+
+// scanDistrict reads the districtColumns of one row
+func scanDistrict(row rowScanner) (*schooldirectory.District, error) {
+	var district schooldirectory.District
+	var ncesID sql.NullString
+	var boundary []byte
+	err := row.Scan(&district.ID, &district.StateAgencyID, &ncesID, &district.Name, &district.Address, &boundary,
+		&district.Version, &district.CreatedAt, &district.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan district: %w", err)
+	}
+
+	district.NCESID = ncesID.String
+	if district.Boundary, err = decodeBoundary(boundary); err != nil {
+		return nil, err
+	}
+	return &district, nil
+}
+
+// StateAgencyRepository implements schooldirectory.StateAgencyRepository. The
+// address and boundary are stored as JSONB.
+type StateAgencyRepository struct {
+	db *sql.DB
+}
+
+var _ schooldirectory.StateAgencyRepository = (*StateAgencyRepository)(nil)
+
+// NewStateAgencyRepository creates a StateAgencyRepository
+func NewStateAgencyRepository(db *sql.DB) *StateAgencyRepository {
+	return &StateAgencyRepository{db: db}
+}
+
+const stateAgencyColumns = `id, state, name, address, boundary, version, created_at, updated_at`
+
+// Create stores a new state agency
+func (r *StateAgencyRepository) Create(ctx context.Context, agency *schooldirectory.StateAgency) error {
+	boundary, err := encodeBoundary(agency.Boundary)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO state_agencies (`+stateAgencyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		agency.ID, agency.State, agency.Name, agency.Address, boundary, agency.Version, agency.CreatedAt,
+		agency.UpdatedAt)
+	if isUniqueViolation(err) {
+		return schooldirectory.ErrStateAgencyTaken
+	}
+	if err != nil {
+		return fmt.Errorf("insert state agency: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a state agency
+func (r *StateAgencyRepository) GetByID(ctx context.Context, id string) (*schooldirectory.StateAgency, error) {
+	agency, err := scanStateAgency(r.db.QueryRowContext(ctx,
+		`SELECT `+stateAgencyColumns+` FROM state_agencies WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", schooldirectory.ErrStateAgencyNotFound, id)
+	}
+	return agency, err
+}
+
+// GetByState returns the agency of a state
+func (r *StateAgencyRepository) GetByState(ctx context.Context, state string) (*schooldirectory.StateAgency, error) {
+	agency, err := scanStateAgency(r.db.QueryRowContext(ctx,
+		`SELECT `+stateAgencyColumns+` FROM state_agencies WHERE state = $1`, state))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: state %s", schooldirectory.ErrStateAgencyNotFound, state)
+	}
+	return agency, err
+}
+
+// scanStateAgency reads the stateAgencyColumns of one row
+func scanStateAgency(row rowScanner) (*schooldirectory.StateAgency, error) {
+	var agency schooldirectory.StateAgency
+	var boundary []byte
+	err := row.Scan(&agency.ID, &agency.State, &agency.Name, &agency.Address, &boundary, &agency.Version,
+		&agency.CreatedAt, &agency.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan state agency: %w", err)
+	}
+
+	if agency.Boundary, err = decodeBoundary(boundary); err != nil {
+		return nil, err
+	}
+	return &agency, nil
+}
+
+// encodeBoundary stores a missing boundary as NULL and any other as JSON
+func encodeBoundary(boundary *domain.Polygon) (any, error) {
+	if boundary == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(boundary)
+	if err != nil {
+		return nil, fmt.Errorf("encode boundary: %w", err)
+	}
+	return data, nil
+}
+
+// decodeBoundary reads a boundary stored by encodeBoundary
+func decodeBoundary(data []byte) (*domain.Polygon, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var boundary domain.Polygon
+	if err := json.Unmarshal(data, &boundary); err != nil {
+		return nil, fmt.Errorf("decode boundary: %w", err)
+	}
+	return &boundary, nil
+}
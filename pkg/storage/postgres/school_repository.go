@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hrh-backend/internal/schooldirectory"
+)
+
+// SchoolRepository implements schooldirectory.SchoolRepository. The address is
+// stored as JSONB and its location as a PostGIS geography point, through their
+// Valuer and Scanner. The point column, not the JSON, holds the coordinates
+// that are read back.
+type SchoolRepository struct {
+	db *sql.DB
+}
+
+var _ schooldirectory.SchoolRepository = (*SchoolRepository)(nil)
+
+// NewSchoolRepository creates a SchoolRepository
+func NewSchoolRepository(db *sql.DB) *SchoolRepository {
+	return &SchoolRepository{db: db}
+}
+
+const schoolColumns = `id, name, address, location, nces_id, sector, lowest_grade, highest_grade, district_id,
+	district_nces_id, district_name, locale_code, locale_type, school_type, enrollment, title_i_status, frl_percent,
+	merged_into_id, merged_at, version, created_at, updated_at`
+
+// Create stores a new school
+func (r *SchoolRepository) Create(ctx context.Context, school *schooldirectory.School) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO schools (`+schoolColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+		        $22)`,
+		school.ID, school.Name, school.Address, school.Address.Location, nullIfEmpty(school.NCESID),
+		school.Sector, school.LowestGrade, school.HighestGrade, nullIfEmpty(school.DistrictID),
+		school.DistrictNCESID, school.DistrictName, school.LocaleCode, school.LocaleType, school.SchoolType,
+		school.Enrollment, school.TitleIStatus, school.FRLPercent, nullIfEmpty(school.MergedIntoID),
+		school.MergedAt, school.Version, school.CreatedAt, school.UpdatedAt)
+	if isUniqueViolation(err) {
+		return schooldirectory.ErrSchoolNCESIDTaken
+	}
+	if err != nil {
+		return fmt.Errorf("insert school: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a school
+func (r *SchoolRepository) GetByID(ctx context.Context, id string) (*schooldirectory.School, error) {
+	school, err := scanSchool(r.db.QueryRowContext(ctx, `SELECT `+schoolColumns+` FROM schools WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", schooldirectory.ErrSchoolNotFound, id)
+	}
+	return school, err
+}
+
+// GetByNCESID returns the school with an NCES ID
+func (r *SchoolRepository) GetByNCESID(ctx context.Context, ncesID string) (*schooldirectory.School, error) {
+	school, err := scanSchool(r.db.QueryRowContext(ctx,
+		`SELECT `+schoolColumns+` FROM schools WHERE nces_id = $1`, ncesID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: NCES ID %s", schooldirectory.ErrSchoolNotFound, ncesID)
+	}
+	return school, err
+}
+
+// Update saves the school using optimistic locking on version
+func (r *SchoolRepository) Update(ctx context.Context, school *schooldirectory.School) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateSchool(ctx, tx, school)
+	})
+	if err != nil {
+		return err
+	}
+
+	school.Version++
+	return nil
+}
+
+// Merge saves the merged school and the survivor in one transaction and points
+// the merged school's own aliases at the survivor
+func (r *SchoolRepository) Merge(ctx context.Context, survivor, merged *schooldirectory.School) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// The merged school gives up its NCES ID before the survivor takes it
+		if err := updateSchool(ctx, tx, merged); err != nil {
+			return err
+		}
+		if err := updateSchool(ctx, tx, survivor); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE schools SET merged_into_id = $2, version = version + 1
+			WHERE merged_into_id = $1`, merged.ID, survivor.ID)
+		if err != nil {
+			return fmt.Errorf("re-point school aliases: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	merged.Version++
+	survivor.Version++
+	return nil
+}
+
+// List returns every school that was not merged into another, ordered by ID
+func (r *SchoolRepository) List(ctx context.Context) ([]*schooldirectory.School, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+schoolColumns+`
+		FROM schools
+		WHERE merged_into_id IS NULL
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list schools: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSchools(rows, "list schools")
+}
+
+// ListByDistrict returns the schools of a district ordered by name
+func (r *SchoolRepository) ListByDistrict(ctx context.Context, districtID string) ([]*schooldirectory.School, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+schoolColumns+`
+		FROM schools
+		WHERE district_id = $1 AND merged_into_id IS NULL
+		ORDER BY name, id`, districtID)
+	if err != nil {
+		return nil, fmt.Errorf("list district schools: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSchools(rows, "list district schools")
+}
+
+// schoolSearchText is the text the name search matches, served by the
+// schools_search_trgm_idx trigram index
+const schoolSearchText = `lower(name || ' ' || COALESCE(address->>'city', ''))`
+
+// FindSearchCandidates preselects schools with the pg_trgm word similarity
+// operator, which uses pg_trgm.word_similarity_threshold, on the query as typed
+// and with abbreviations expanded. The last word may also be a prefix of any word.
+func (r *SchoolRepository) FindSearchCandidates(ctx context.Context,
+	query schooldirectory.SchoolCandidateQuery) ([]*schooldirectory.School, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+schoolColumns+`
+		FROM schools
+		WHERE merged_into_id IS NULL
+		  AND ($1 <% `+schoolSearchText+` OR $2 <% `+schoolSearchText+`
+		       OR ($3 <> '' AND `+schoolSearchText+` LIKE $3))
+		ORDER BY GREATEST(word_similarity($1, `+schoolSearchText+`), word_similarity($2, `+schoolSearchText+`)) DESC,
+		         name, id
+		LIMIT $4`,
+		query.Text, strings.Join(query.Words, " "), containsPattern(query.Prefix), query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("find school search candidates: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSchools(rows, "find school search candidates")
+}
+
+// updateSchool saves every column of the school if its stored version still
+// equals school.Version, leaving school.Version to the caller. The wishlists of
+// the school's teachers follow a change of location.
+func updateSchool(ctx context.Context, q querier, school *schooldirectory.School) error {
+	result, err := q.ExecContext(ctx, `
+		UPDATE schools
+		SET name = $3, address = $4, location = $5, nces_id = $6, sector = $7, lowest_grade = $8,
+		    highest_grade = $9, district_id = $10, district_nces_id = $11, district_name = $12, locale_code = $13,
+		    locale_type = $14, school_type = $15, enrollment = $16, title_i_status = $17, frl_percent = $18,
+		    merged_into_id = $19, merged_at = $20, updated_at = $21, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		school.ID, school.Version, school.Name, school.Address, school.Address.Location,
+		nullIfEmpty(school.NCESID), school.Sector, school.LowestGrade, school.HighestGrade,
+		nullIfEmpty(school.DistrictID), school.DistrictNCESID, school.DistrictName, school.LocaleCode,
+		school.LocaleType, school.SchoolType, school.Enrollment, school.TitleIStatus, school.FRLPercent,
+		nullIfEmpty(school.MergedIntoID), school.MergedAt, school.UpdatedAt)
+	if isUniqueViolation(err) {
+		return schooldirectory.ErrSchoolNCESIDTaken
+	}
+	if err != nil {
+		return fmt.Errorf("update school: %w", err)
+	}
+
+	err = checkVersionedUpdate(ctx, q, result, "schools", school.ID,
+		schooldirectory.ErrSchoolNotFound, schooldirectory.ErrSchoolVersionConflict)
+	if err != nil {
+		return err
+	}
+
+	return syncWishlistLocations(ctx, q, `s.id = $1`, school.ID)
+}
+
+// scanSchools reads every row of a school query; action names the query in
+// errors
+func scanSchools(rows *sql.Rows, action string) ([]*schooldirectory.School, error) {
+	schools := []*schooldirectory.School{}
+	for rows.Next() {
+		school, err := scanSchool(rows)
+		if err != nil {
+			return nil, err
+		}
+		schools = append(schools, school)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", action, err)
+	}
+
+	return schools, nil
+}
+
+// scanSchool reads the schoolColumns of one row
+func scanSchool(row rowScanner) (*schooldirectory.School, error) {
+	var school schooldirectory.School
+	var ncesID, districtID, mergedIntoID sql.NullString
+	var frlPercent sql.NullFloat64
+	var mergedAt sql.NullTime
+	// The location is scanned after the address, whose JSON keeps the county
+	// and region, so the coordinates come from the geography column
+	err := row.Scan(&school.ID, &school.Name, &school.Address, &school.Address.Location, &ncesID, &school.Sector, &school.LowestGrade,
+		&school.HighestGrade, &districtID, &school.DistrictNCESID, &school.DistrictName, &school.LocaleCode,
+		&school.LocaleType, &school.SchoolType, &school.Enrollment, &school.TitleIStatus, &frlPercent,
+		&mergedIntoID, &mergedAt, &school.Version, &school.CreatedAt, &school.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan school: %w", err)
+	}
+
+	school.NCESID = ncesID.String
+	school.DistrictID = districtID.String
+	school.MergedIntoID = mergedIntoID.String
+	school.MergedAt = nullTimePtr(mergedAt)
+	if frlPercent.Valid {
+		school.FRLPercent = &frlPercent.Float64
+	}
+	return &school, nil
+}
@@ -0,0 +1,311 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// TeacherRepository implements teacherwishlist.TeacherRepository. The current
+// validation status and school are stored on the teacher row; every transition
+// and every school transfer is kept in the append-only
+// teacher_validation_history and teacher_school_history tables.
+type TeacherRepository struct {
+	db *sql.DB
+}
+
+var _ teacherwishlist.TeacherRepository = (*TeacherRepository)(nil)
+
+// NewTeacherRepository creates a TeacherRepository
+func NewTeacherRepository(db *sql.DB) *TeacherRepository {
+	return &TeacherRepository{db: db}
+}
+
+const teacherColumns = `id, school_id, name, email, validation_status, primary_wishlist_id, version,
+	created_at, updated_at`
+
+const validationHistoryColumns = `teacher_id, seq, from_status, to_status, actor, reason, at`
+
+const schoolHistoryColumns = `teacher_id, seq, from_school_id, to_school_id, actor, at`
+
+// Create stores a new teacher and its history
+func (r *TeacherRepository) Create(ctx context.Context, teacher *teacherwishlist.Teacher) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO teachers (`+teacherColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			teacher.ID, teacher.SchoolID, teacher.Name, teacher.Email, teacher.ValidationState.Status,
+			nullIfEmpty(teacher.PrimaryWishlistID), teacher.Version, teacher.CreatedAt, teacher.UpdatedAt)
+		if isUniqueViolation(err) {
+			return teacherwishlist.ErrTeacherEmailTaken
+		}
+		if err != nil {
+			return fmt.Errorf("insert teacher: %w", err)
+		}
+
+		return insertHistory(ctx, tx, teacher)
+	})
+}
+
+// GetByID returns a teacher with its validation history
+func (r *TeacherRepository) GetByID(ctx context.Context, id string) (*teacherwishlist.Teacher, error) {
+	return r.getTeacher(ctx, `id = $1`, id)
+}
+
+// GetByEmail returns the teacher registered with the email, ignoring case
+func (r *TeacherRepository) GetByEmail(ctx context.Context, email string) (*teacherwishlist.Teacher, error) {
+	return r.getTeacher(ctx, `lower(email) = lower($1)`, email)
+}
+
+// Update saves the teacher using optimistic locking on version and appends new
+// validation transitions and school transfers. The teacher's wishlists take the
+// location of the teacher's school.
+func (r *TeacherRepository) Update(ctx context.Context, teacher *teacherwishlist.Teacher) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE teachers
+			SET school_id = $3, name = $4, email = $5, validation_status = $6, primary_wishlist_id = $7,
+			    updated_at = $8, version = version + 1
+			WHERE id = $1 AND version = $2`,
+			teacher.ID, teacher.Version, teacher.SchoolID, teacher.Name, teacher.Email,
+			teacher.ValidationState.Status, nullIfEmpty(teacher.PrimaryWishlistID), teacher.UpdatedAt)
+		if isUniqueViolation(err) {
+			return teacherwishlist.ErrTeacherEmailTaken
+		}
+		if err != nil {
+			return fmt.Errorf("update teacher: %w", err)
+		}
+
+		err = checkVersionedUpdate(ctx, tx, result, "teachers", teacher.ID,
+			teacherwishlist.ErrTeacherNotFound, teacherwishlist.ErrTeacherVersionConflict)
+		if err != nil {
+			return err
+		}
+
+		// A teacher's wishlists move with the teacher to another school
+		if err := syncWishlistLocations(ctx, tx, `t.id = $1`, teacher.ID); err != nil {
+			return err
+		}
+
+		return insertHistory(ctx, tx, teacher)
+	})
+	if err != nil {
+		return err
+	}
+
+	teacher.Version++
+	return nil
+}
+
+// ListByValidationStatus returns the teachers with a status, longest waiting first
+func (r *TeacherRepository) ListByValidationStatus(ctx context.Context,
+	status teacherwishlist.ValidationStatus) ([]*teacherwishlist.Teacher, error) {
+	return r.listTeachers(ctx, `validation_status`, status, `updated_at`)
+}
+
+// ListBySchool returns the teachers at a school, oldest first
+func (r *TeacherRepository) ListBySchool(ctx context.Context, schoolID string) ([]*teacherwishlist.Teacher, error) {
+	return r.listTeachers(ctx, `school_id`, schoolID, `created_at`)
+}
+
+// listTeachers loads the teachers whose column equals arg with their validation
+// and school history, ordered by orderBy. column and orderBy are always
+// constants from this package.
+func (r *TeacherRepository) listTeachers(ctx context.Context, column string, arg any,
+	orderBy string) ([]*teacherwishlist.Teacher, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+teacherColumns+`
+		FROM teachers
+		WHERE `+column+` = $1
+		ORDER BY `+orderBy+`, id`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list teachers: %w", err)
+	}
+	defer rows.Close()
+
+	teachers := []*teacherwishlist.Teacher{}
+	byID := make(map[string]*teacherwishlist.Teacher)
+	for rows.Next() {
+		teacher, err := scanTeacher(rows)
+		if err != nil {
+			return nil, err
+		}
+		teachers = append(teachers, teacher)
+		byID[teacher.ID] = teacher
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list teachers: %w", err)
+	}
+
+	historyRows, err := r.db.QueryContext(ctx, `
+		SELECT `+prefixColumns("h", validationHistoryColumns)+`
+		FROM teacher_validation_history h
+		JOIN teachers t ON t.id = h.teacher_id
+		WHERE t.`+column+` = $1
+		ORDER BY h.teacher_id, h.seq`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list validation history: %w", err)
+	}
+	defer historyRows.Close()
+
+	if err := scanValidationHistory(historyRows, byID); err != nil {
+		return nil, err
+	}
+
+	schoolRows, err := r.db.QueryContext(ctx, `
+		SELECT `+prefixColumns("h", schoolHistoryColumns)+`
+		FROM teacher_school_history h
+		JOIN teachers t ON t.id = h.teacher_id
+		WHERE t.`+column+` = $1
+		ORDER BY h.teacher_id, h.seq`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list school history: %w", err)
+	}
+	defer schoolRows.Close()
+
+	if err := scanSchoolHistory(schoolRows, byID); err != nil {
+		return nil, err
+	}
+
+	return teachers, nil
+}
+
+// getTeacher loads the teacher matching a single-argument condition with its
+// validation and school history
+func (r *TeacherRepository) getTeacher(ctx context.Context, condition, arg string) (*teacherwishlist.Teacher, error) {
+	teacher, err := scanTeacher(r.db.QueryRowContext(ctx, `SELECT `+teacherColumns+` FROM teachers WHERE `+condition, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", teacherwishlist.ErrTeacherNotFound, arg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+validationHistoryColumns+`
+		FROM teacher_validation_history
+		WHERE teacher_id = $1
+		ORDER BY seq`, teacher.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get validation history: %w", err)
+	}
+	defer rows.Close()
+
+	byID := map[string]*teacherwishlist.Teacher{teacher.ID: teacher}
+	if err := scanValidationHistory(rows, byID); err != nil {
+		return nil, err
+	}
+
+	schoolRows, err := r.db.QueryContext(ctx, `
+		SELECT `+schoolHistoryColumns+`
+		FROM teacher_school_history
+		WHERE teacher_id = $1
+		ORDER BY seq`, teacher.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get school history: %w", err)
+	}
+	defer schoolRows.Close()
+
+	if err := scanSchoolHistory(schoolRows, byID); err != nil {
+		return nil, err
+	}
+
+	return teacher, nil
+}
+
+// insertHistory stores the teacher's transitions and school transfers that are
+// not stored yet. Entries are keyed by their position in the history, so
+// existing rows are left untouched.
+func insertHistory(ctx context.Context, tx *sql.Tx, teacher *teacherwishlist.Teacher) error {
+	for seq, transition := range teacher.ValidationState.History {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO teacher_validation_history (`+validationHistoryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (teacher_id, seq) DO NOTHING`,
+			teacher.ID, seq, transition.From, transition.To, transition.Actor, transition.Reason, transition.At)
+		if err != nil {
+			return fmt.Errorf("insert validation history: %w", err)
+		}
+	}
+
+	for seq, transfer := range teacher.SchoolHistory {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO teacher_school_history (`+schoolHistoryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (teacher_id, seq) DO NOTHING`,
+			teacher.ID, seq, transfer.FromSchoolID, transfer.ToSchoolID, transfer.Actor, transfer.At)
+		if err != nil {
+			return fmt.Errorf("insert school history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanTeacher reads the teacherColumns of one row
+func scanTeacher(row rowScanner) (*teacherwishlist.Teacher, error) {
+	var teacher teacherwishlist.Teacher
+	var primaryWishlistID sql.NullString
+	err := row.Scan(&teacher.ID, &teacher.SchoolID, &teacher.Name, &teacher.Email,
+		&teacher.ValidationState.Status, &primaryWishlistID, &teacher.Version, &teacher.CreatedAt, &teacher.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan teacher: %w", err)
+	}
+
+	teacher.PrimaryWishlistID = primaryWishlistID.String
+	teacher.ValidationState.History = []teacherwishlist.ValidationTransition{}
+	return &teacher, nil
+}
+
+// scanValidationHistory appends each validationHistoryColumns row to the
+// history of the matching teacher
+func scanValidationHistory(rows *sql.Rows, teachers map[string]*teacherwishlist.Teacher) error {
+	for rows.Next() {
+		var teacherID string
+		var seq int
+		var transition teacherwishlist.ValidationTransition
+		err := rows.Scan(&teacherID, &seq, &transition.From, &transition.To, &transition.Actor,
+			&transition.Reason, &transition.At)
+		if err != nil {
+			return fmt.Errorf("scan validation history: %w", err)
+		}
+
+		if teacher, ok := teachers[teacherID]; ok {
+			teacher.ValidationState.History = append(teacher.ValidationState.History, transition)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("scan validation history: %w", err)
+	}
+	return nil
+}
+
+// scanSchoolHistory appends each schoolHistoryColumns row to the school history
+// of the matching teacher
+func scanSchoolHistory(rows *sql.Rows, teachers map[string]*teacherwishlist.Teacher) error {
+	for rows.Next() {
+		var teacherID string
+		var seq int
+		var transfer teacherwishlist.SchoolTransfer
+		err := rows.Scan(&teacherID, &seq, &transfer.FromSchoolID, &transfer.ToSchoolID, &transfer.Actor, &transfer.At)
+		if err != nil {
+			return fmt.Errorf("scan school history: %w", err)
+		}
+
+		if teacher, ok := teachers[teacherID]; ok {
+			teacher.SchoolHistory = append(teacher.SchoolHistory, transfer)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("scan school history: %w", err)
+	}
+	return nil
+}
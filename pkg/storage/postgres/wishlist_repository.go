@@ -0,0 +1,874 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hrh-backend/internal/publicsearch"
+	"hrh-backend/internal/schooldirectory"
+	"hrh-backend/internal/teacherwishlist"
+)
+
+// WishlistRepository implements teacherwishlist.WishlistRepository
+type WishlistRepository struct {
+	db *sql.DB
+}
+
+var (
+	_ teacherwishlist.WishlistRepository = (*WishlistRepository)(nil)
+	_ publicsearch.WishlistFinder        = (*WishlistRepository)(nil)
+)
+
+// NewWishlistRepository creates a WishlistRepository
+func NewWishlistRepository(db *sql.DB) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+const wishlistColumns = `id, teacher_id, title, description, grade_level, subject, student_count, visibility,
+	status, publish_at, expire_at, archived_at, revision, version, created_at, updated_at`
+
+const wishlistItemColumns = `id, wishlist_id, name, quantity_requested, quantity_fulfilled,
+	quantity_pledged, unit_price_cents, priority, category, product_url, catalog_item_id`
+
+// Create stores a new wishlist, its items and its first revision. The teacher row
+// is locked while the teacher's open wishlists are counted, so concurrent
+// creates cannot exceed maxPerTeacher. The wishlist takes the location of the
+// teacher's school.
+func (r *WishlistRepository) Create(ctx context.Context, wishlist *teacherwishlist.Wishlist,
+	revision *teacherwishlist.WishlistRevision, maxPerTeacher int) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkWishlistLimit(ctx, tx, wishlist, maxPerTeacher); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO wishlists (`+wishlistColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			wishlist.ID, wishlist.TeacherID, wishlist.Title, wishlist.Description, wishlist.GradeLevel,
+			wishlist.Subject, wishlist.StudentCount, wishlist.Visibility, wishlist.Status,
+			wishlist.PublishAt, wishlist.ExpireAt, wishlist.ArchivedAt,
+			wishlist.Revision, wishlist.Version, wishlist.CreatedAt, wishlist.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("insert wishlist: %w", err)
+		}
+
+		if err := syncWishlistLocations(ctx, tx, `w.id = $1`, wishlist.ID); err != nil {
+			return err
+		}
+
+		if err := insertWishlistItems(ctx, tx, wishlist); err != nil {
+			return err
+		}
+
+		return insertWishlistRevision(ctx, tx, revision)
+	})
+}
+
+// GetByID returns the wishlist with its items
+func (r *WishlistRepository) GetByID(ctx context.Context, id string) (*teacherwishlist.Wishlist, error) {
+	return getWishlist(ctx, r.db, id)
+}
+
+// Update replaces the wishlist and its items using optimistic locking on version
+// and appends the revision, if any
+func (r *WishlistRepository) Update(ctx context.Context, wishlist *teacherwishlist.Wishlist,
+	revision *teacherwishlist.WishlistRevision) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return updateWishlist(ctx, tx, wishlist, revision)
+	})
+	if err != nil {
+		return err
+	}
+
+	wishlist.Version++
+	return nil
+}
+
+// Reopen stores a wishlist moved back out of the archive. The teacher row is
+// locked while the teacher's other open wishlists are counted, as in Create.
+func (r *WishlistRepository) Reopen(ctx context.Context, wishlist *teacherwishlist.Wishlist,
+	maxPerTeacher int) error {
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkWishlistLimit(ctx, tx, wishlist, maxPerTeacher); err != nil {
+			return err
+		}
+		return updateWishlist(ctx, tx, wishlist, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	wishlist.Version++
+	return nil
+}
+
+// checkWishlistLimit locks the wishlist's teacher and fails with
+// ErrWishlistLimitReached if the teacher already has maxPerTeacher open
+// wishlists besides this one. A non-positive maxPerTeacher disables the check.
+func checkWishlistLimit(ctx context.Context, tx *sql.Tx, wishlist *teacherwishlist.Wishlist,
+	maxPerTeacher int) error {
+	if maxPerTeacher <= 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM teachers WHERE id = $1 FOR UPDATE`,
+		wishlist.TeacherID); err != nil {
+		return fmt.Errorf("lock teacher: %w", err)
+	}
+
+	var open int
+	err := tx.QueryRowContext(ctx, `
+		SELECT count(*) FROM wishlists WHERE teacher_id = $1 AND status <> $2 AND id <> $3`,
+		wishlist.TeacherID, teacherwishlist.WishlistArchived, wishlist.ID).Scan(&open)
+	if err != nil {
+		return fmt.Errorf("count wishlists: %w", err)
+	}
+	if open >= maxPerTeacher {
+		return teacherwishlist.ErrWishlistLimitReached
+	}
+	return nil
+}
+
+// updateWishlist replaces a wishlist and its items if its version is current
+// and appends the revision, if any. It leaves wishlist.Version to the caller,
+// to increment once the transaction commits.
+func updateWishlist(ctx context.Context, tx *sql.Tx, wishlist *teacherwishlist.Wishlist,
+	revision *teacherwishlist.WishlistRevision) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE wishlists
+		SET title = $3, description = $4, grade_level = $5, subject = $6, student_count = $7,
+		    visibility = $8, status = $9, publish_at = $10, expire_at = $11, archived_at = $12,
+		    revision = $13, updated_at = $14, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		wishlist.ID, wishlist.Version, wishlist.Title, wishlist.Description, wishlist.GradeLevel,
+		wishlist.Subject, wishlist.StudentCount, wishlist.Visibility, wishlist.Status,
+		wishlist.PublishAt, wishlist.ExpireAt, wishlist.ArchivedAt, wishlist.Revision, wishlist.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update wishlist: %w", err)
+	}
+
+	err = checkVersionedUpdate(ctx, tx, result, "wishlists", wishlist.ID,
+		teacherwishlist.ErrWishlistNotFound, teacherwishlist.ErrWishlistVersionConflict)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM wishlist_items WHERE wishlist_id = $1`, wishlist.ID); err != nil {
+		return fmt.Errorf("delete wishlist items: %w", err)
+	}
+
+	if err := insertWishlistItems(ctx, tx, wishlist); err != nil {
+		return err
+	}
+
+	return insertWishlistRevision(ctx, tx, revision)
+}
+
+// ListByTeacher returns every wishlist of a teacher, oldest first
+func (r *WishlistRepository) ListByTeacher(ctx context.Context, teacherID string) ([]*teacherwishlist.Wishlist, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+wishlistColumns+`
+		FROM wishlists
+		WHERE teacher_id = $1
+		ORDER BY created_at, id`, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("list wishlists: %w", err)
+	}
+	defer rows.Close()
+
+	wishlists := []*teacherwishlist.Wishlist{}
+	byID := make(map[string]*teacherwishlist.Wishlist)
+	for rows.Next() {
+		wishlist, err := scanWishlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		wishlists = append(wishlists, wishlist)
+		byID[wishlist.ID] = wishlist
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list wishlists: %w", err)
+	}
+
+	itemRows, err := r.db.QueryContext(ctx, `
+		SELECT `+prefixColumns("i", wishlistItemColumns)+`
+		FROM wishlist_items i
+		JOIN wishlists w ON w.id = i.wishlist_id
+		WHERE w.teacher_id = $1
+		ORDER BY i.wishlist_id, i.position`, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("list wishlist items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		item, wishlistID, err := scanWishlistItem(itemRows)
+		if err != nil {
+			return nil, err
+		}
+		if wishlist, ok := byID[wishlistID]; ok {
+			wishlist.Items = append(wishlist.Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, fmt.Errorf("list wishlist items: %w", err)
+	}
+
+	return wishlists, nil
+}
+
+// ArchiveExpired archives every published or paused wishlist whose expiry is at
+// or before now
+func (r *WishlistRepository) ArchiveExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE wishlists
+		SET status = $2, archived_at = $1, updated_at = $1, version = version + 1
+		WHERE status IN ($3, $4) AND expire_at <= $1`,
+		now, teacherwishlist.WishlistArchived, teacherwishlist.WishlistPublished, teacherwishlist.WishlistPaused)
+	if err != nil {
+		return 0, fmt.Errorf("archive expired wishlists: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("archive expired wishlists: %w", err)
+	}
+	return int(archived), nil
+}
+
+// wishlistSortOrders maps each search sort to its ORDER BY clause. The funding
+// columns come from the lateral join in FindPublishedWishlists.
+var wishlistSortOrders = map[publicsearch.WishlistSort]string{
+	publicsearch.SortNewest: `COALESCE(w.publish_at, w.updated_at) DESC, w.id`,
+	publicsearch.SortClosestToFunded: `f.requested_cents > 0 AND f.funded_cents >= f.requested_cents,
+		f.funded_cents::numeric / NULLIF(f.requested_cents, 0) DESC NULLS LAST,
+		f.requested_cents - f.funded_cents, w.id`,
+	publicsearch.SortHighestNeed: `s.frl_percent DESC NULLS LAST, s.title_i_status = 'schoolwide' DESC,
+		COALESCE(w.publish_at, w.updated_at) DESC, w.id`,
+	publicsearch.SortLargestEnrollment: `s.enrollment DESC, COALESCE(w.publish_at, w.updated_at) DESC, w.id`,
+	publicsearch.SortNearest: `ST_Distance(w.location, $17::geography),
+		COALESCE(w.publish_at, w.updated_at) DESC, w.id`,
+}
+
+// schoolGradeOrder lists the ranked grades so a query can compare a grade
+// against a school's span with array_position
+var schoolGradeOrder = joinStrings(schooldirectory.RankedGrades())
+
+// joinStrings comma-joins a filter list for string_to_array; an empty list
+// becomes "", which the queries treat as no filter
+func joinStrings[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = string(value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// FindPublishedWishlists returns a page of public wishlists visible at query.Now
+// whose teacher is verified, in query.Sort order. query.Text is matched against the
+// title, description and item names; query.DistrictID and the school attribute
+// filters against the teacher's school. query.Near is matched with ST_DWithin
+// against the wishlist's copy of the school location.
+func (r *WishlistRepository) FindPublishedWishlists(ctx context.Context,
+	query publicsearch.WishlistQuery) ([]*teacherwishlist.Wishlist, error) {
+	order, ok := wishlistSortOrders[query.Sort]
+	if !ok {
+		order = wishlistSortOrders[publicsearch.SortNewest]
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+prefixColumns("w", wishlistColumns)+`
+		FROM wishlists w
+		JOIN teachers t ON t.id = w.teacher_id
+		JOIN schools s ON s.id = t.school_id
+		CROSS JOIN LATERAL (
+		    SELECT COALESCE(SUM(i.quantity_requested::bigint * i.unit_price_cents), 0) AS requested_cents,
+		           COALESCE(SUM((i.quantity_fulfilled + i.quantity_pledged)::bigint * i.unit_price_cents), 0)
+		               AS funded_cents
+		    FROM wishlist_items i
+		    WHERE i.wishlist_id = w.id) f
+		WHERE w.status = $1 AND w.visibility = $7 AND t.validation_status = $2
+		  AND (w.publish_at IS NULL OR w.publish_at <= $3)
+		  AND (w.expire_at IS NULL OR w.expire_at > $3)
+		  AND ($4 = '' OR w.title ILIKE $4 OR w.description ILIKE $4 OR EXISTS (
+		      SELECT 1 FROM wishlist_items i WHERE i.wishlist_id = w.id AND i.name ILIKE $4))
+		  AND ($8 = '' OR s.district_id::text = $8)
+		  AND ($9 = '' OR s.school_type = ANY (string_to_array($9, ',')))
+		  AND ($10 = '' OR s.locale_type = ANY (string_to_array($10, ',')))
+		  AND ($11 = '' OR (array_position(string_to_array($16, ','), s.lowest_grade)
+		          <= array_position(string_to_array($16, ','), $11)
+		      AND array_position(string_to_array($16, ','), $11)
+		          <= array_position(string_to_array($16, ','), s.highest_grade)))
+		  AND (NOT $12 OR s.title_i_status IN ('targeted', 'schoolwide'))
+		  AND ($13::numeric IS NULL OR s.frl_percent >= $13)
+		  AND ($14 = 0 OR s.enrollment >= $14)
+		  AND ($15 = 0 OR s.enrollment <= $15)
+		  AND ($17::geography IS NULL OR ST_DWithin(w.location, $17::geography, $18))
+		ORDER BY `+order+`
+		LIMIT $5 OFFSET $6`,
+		teacherwishlist.WishlistPublished, teacherwishlist.ValidationVerified, query.Now,
+		containsPattern(query.Text), query.Limit, query.Offset, teacherwishlist.VisibilityPublic, query.DistrictID,
+		joinStrings(query.SchoolTypes), joinStrings(query.LocaleTypes), query.Grade, query.TitleIOnly,
+		query.MinFRLPercent, query.MinEnrollment, query.MaxEnrollment, schoolGradeOrder, query.Near,
+		query.Unit.ToKilometers(query.Radius)*1000)
+	if err != nil {
+		return nil, fmt.Errorf("find published wishlists: %w", err)
+	}
+	defer rows.Close()
+
+	wishlists := []*teacherwishlist.Wishlist{}
+	for rows.Next() {
+		wishlist, err := scanWishlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		wishlists = append(wishlists, wishlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find published wishlists: %w", err)
+	}
+
+	if err := loadWishlistItems(ctx, r.db, wishlists); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
+
+const wishlistRevisionColumns = `wishlist_id, number, source, restored_from, title, description, items, created_at`
+
+// ListRevisions returns every revision of a wishlist, oldest first
+func (r *WishlistRepository) ListRevisions(ctx context.Context,
+	wishlistID string) ([]*teacherwishlist.WishlistRevision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+wishlistRevisionColumns+`
+		FROM wishlist_revisions
+		WHERE wishlist_id = $1
+		ORDER BY number`, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("list wishlist revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*teacherwishlist.WishlistRevision{}
+	for rows.Next() {
+		revision, err := scanWishlistRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list wishlist revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns one revision of a wishlist
+func (r *WishlistRepository) GetRevision(ctx context.Context, wishlistID string,
+	number int) (*teacherwishlist.WishlistRevision, error) {
+	revision, err := scanWishlistRevision(r.db.QueryRowContext(ctx, `
+		SELECT `+wishlistRevisionColumns+`
+		FROM wishlist_revisions
+		WHERE wishlist_id = $1 AND number = $2`, wishlistID, number))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s revision %d", teacherwishlist.ErrRevisionNotFound, wishlistID, number)
+	}
+	return revision, err
+}
+
+// getWishlist loads a wishlist and its items
+func getWishlist(ctx context.Context, q querier, id string) (*teacherwishlist.Wishlist, error) {
+	wishlist, err := scanWishlist(q.QueryRowContext(ctx, `SELECT `+wishlistColumns+` FROM wishlists WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", teacherwishlist.ErrWishlistNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+wishlistItemColumns+`
+		FROM wishlist_items
+		WHERE wishlist_id = $1
+		ORDER BY position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get wishlist items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, _, err := scanWishlistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		wishlist.Items = append(wishlist.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get wishlist items: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// loadWishlistItems fills in the items of the given wishlists with one query
+func loadWishlistItems(ctx context.Context, q querier, wishlists []*teacherwishlist.Wishlist) error {
+	if len(wishlists) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*teacherwishlist.Wishlist, len(wishlists))
+	placeholders := make([]string, 0, len(wishlists))
+	args := make([]any, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		byID[wishlist.ID] = wishlist
+		args = append(args, wishlist.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+wishlistItemColumns+`
+		FROM wishlist_items
+		WHERE wishlist_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY wishlist_id, position`, args...)
+	if err != nil {
+		return fmt.Errorf("load wishlist items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, wishlistID, err := scanWishlistItem(rows)
+		if err != nil {
+			return err
+		}
+		if wishlist, ok := byID[wishlistID]; ok {
+			wishlist.Items = append(wishlist.Items, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("load wishlist items: %w", err)
+	}
+
+	return nil
+}
+
+// containsPattern turns search text into an ILIKE pattern matching it anywhere,
+// escaping LIKE wildcards. Empty text gives an empty pattern.
+func containsPattern(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// insertWishlistItems stores the items of a wishlist in list order
+func insertWishlistItems(ctx context.Context, tx *sql.Tx, wishlist *teacherwishlist.Wishlist) error {
+	for position, item := range wishlist.Items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO wishlist_items (`+wishlistItemColumns+`, position)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			item.ID, wishlist.ID, item.Name, item.QuantityRequested, item.QuantityFulfilled,
+			item.QuantityPledged, item.UnitPriceCents, item.Priority, item.Category, item.ProductURL,
+			nullIfEmpty(item.CatalogItemID), position)
+		if err != nil {
+			return fmt.Errorf("insert wishlist item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertWishlistRevision appends a revision with its items stored as JSON. A nil
+// revision is ignored.
+func insertWishlistRevision(ctx context.Context, tx *sql.Tx, revision *teacherwishlist.WishlistRevision) error {
+	if revision == nil {
+		return nil
+	}
+
+	items, err := json.Marshal(revision.Items)
+	if err != nil {
+		return fmt.Errorf("encode revision items: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO wishlist_revisions (`+wishlistRevisionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		revision.WishlistID, revision.Number, revision.Source, revision.RestoredFrom,
+		revision.Title, revision.Description, items, revision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert wishlist revision: %w", err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanWishlist reads the wishlistColumns of one row
+func scanWishlist(row rowScanner) (*teacherwishlist.Wishlist, error) {
+	var wishlist teacherwishlist.Wishlist
+	var publishAt, expireAt, archivedAt sql.NullTime
+	err := row.Scan(&wishlist.ID, &wishlist.TeacherID, &wishlist.Title, &wishlist.Description,
+		&wishlist.GradeLevel, &wishlist.Subject, &wishlist.StudentCount, &wishlist.Visibility, &wishlist.Status,
+		&publishAt, &expireAt, &archivedAt, &wishlist.Revision, &wishlist.Version,
+		&wishlist.CreatedAt, &wishlist.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan wishlist: %w", err)
+	}
+
+	wishlist.PublishAt = nullTimePtr(publishAt)
+	wishlist.ExpireAt = nullTimePtr(expireAt)
+	wishlist.ArchivedAt = nullTimePtr(archivedAt)
+	wishlist.Items = []teacherwishlist.WishlistItem{}
+	return &wishlist, nil
+}
+
+// nullTimePtr returns nil for a NULL time
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// scanWishlistItem reads the wishlistItemColumns of one row and returns the item
+// with its wishlist ID
+func scanWishlistItem(row rowScanner) (teacherwishlist.WishlistItem, string, error) {
+	var item teacherwishlist.WishlistItem
+	var wishlistID string
+	var catalogItemID sql.NullString
+	err := row.Scan(&item.ID, &wishlistID, &item.Name, &item.QuantityRequested, &item.QuantityFulfilled,
+		&item.QuantityPledged, &item.UnitPriceCents, &item.Priority, &item.Category, &item.ProductURL,
+		&catalogItemID)
+	if err != nil {
+		return teacherwishlist.WishlistItem{}, "", fmt.Errorf("scan wishlist item: %w", err)
+	}
+
+	item.CatalogItemID = catalogItemID.String
+
+	return item, wishlistID, nil
+}
+
+// PledgeRepository implements teacherwishlist.PledgeRepository. Holds are
+// reserved with a conditional UPDATE on the item row, so concurrent claims on the
+// same item are serialized by the row lock and can never exceed the quantity
+// requested.
+type PledgeRepository struct {
+	db *sql.DB
+}
+
+var _ teacherwishlist.PledgeRepository = (*PledgeRepository)(nil)
+
+// NewPledgeRepository creates a PledgeRepository
+func NewPledgeRepository(db *sql.DB) *PledgeRepository {
+	return &PledgeRepository{db: db}
+}
+
+const pledgeColumns = `id, wishlist_id, item_id, donor_name, donor_email, quantity, status,
+	created_at, expires_at, resolved_at`
+
+// HoldPledge reserves the pledge quantity on its item and stores the pledge
+func (r *PledgeRepository) HoldPledge(ctx context.Context, pledge *teacherwishlist.Pledge) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := lockWishlists(ctx, tx, pledge.WishlistID); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `
+			UPDATE wishlist_items
+			SET quantity_pledged = quantity_pledged + $3
+			WHERE id = $1 AND wishlist_id = $2
+			  AND quantity_requested - quantity_fulfilled - quantity_pledged >= $3`,
+			pledge.ItemID, pledge.WishlistID, pledge.Quantity)
+		if err != nil {
+			return fmt.Errorf("reserve pledge quantity: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("reserve pledge quantity: %w", err)
+		}
+		if affected == 0 {
+			var exists bool
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS (SELECT 1 FROM wishlist_items WHERE id = $1 AND wishlist_id = $2)`,
+				pledge.ItemID, pledge.WishlistID).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("reserve pledge quantity: %w", err)
+			}
+			if !exists {
+				return fmt.Errorf("%w: %s", teacherwishlist.ErrWishlistItemNotFound, pledge.ItemID)
+			}
+			return teacherwishlist.ErrInsufficientQuantity
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pledges (`+pledgeColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			pledge.ID, pledge.WishlistID, pledge.ItemID, pledge.DonorName, pledge.DonorEmail,
+			pledge.Quantity, pledge.Status, pledge.CreatedAt, pledge.ExpiresAt, pledge.ResolvedAt)
+		if err != nil {
+			return fmt.Errorf("insert pledge: %w", err)
+		}
+
+		return bumpWishlistVersion(ctx, tx, pledge.WishlistID)
+	})
+}
+
+// GetPledge returns a pledge by ID
+func (r *PledgeRepository) GetPledge(ctx context.Context, id string) (*teacherwishlist.Pledge, error) {
+	pledge, err := scanPledge(r.db.QueryRowContext(ctx, `SELECT `+pledgeColumns+` FROM pledges WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", teacherwishlist.ErrPledgeNotFound, id)
+	}
+	return pledge, err
+}
+
+// ListPledges returns every pledge on a wishlist, oldest first
+func (r *PledgeRepository) ListPledges(ctx context.Context, wishlistID string) ([]*teacherwishlist.Pledge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+pledgeColumns+`
+		FROM pledges
+		WHERE wishlist_id = $1
+		ORDER BY created_at, id`, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("list pledges: %w", err)
+	}
+	defer rows.Close()
+
+	pledges := []*teacherwishlist.Pledge{}
+	for rows.Next() {
+		pledge, err := scanPledge(rows)
+		if err != nil {
+			return nil, err
+		}
+		pledges = append(pledges, pledge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list pledges: %w", err)
+	}
+
+	return pledges, nil
+}
+
+// ResolvePledge moves a held pledge to a final status and settles its quantity
+func (r *PledgeRepository) ResolvePledge(ctx context.Context, id string, status teacherwishlist.PledgeStatus,
+	at time.Time) (*teacherwishlist.Pledge, error) {
+	var resolved *teacherwishlist.Pledge
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var wishlistID string
+		err := tx.QueryRowContext(ctx, `SELECT wishlist_id FROM pledges WHERE id = $1`, id).Scan(&wishlistID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", teacherwishlist.ErrPledgeNotFound, id)
+		}
+		if err != nil {
+			return fmt.Errorf("resolve pledge: %w", err)
+		}
+		if err := lockWishlists(ctx, tx, wishlistID); err != nil {
+			return err
+		}
+
+		pledge, err := scanPledge(tx.QueryRowContext(ctx, `
+			UPDATE pledges
+			SET status = $2, resolved_at = $3
+			WHERE id = $1 AND status = $4
+			RETURNING `+pledgeColumns,
+			id, status, at, teacherwishlist.PledgeHeld))
+		if errors.Is(err, sql.ErrNoRows) {
+			return teacherwishlist.ErrPledgeNotHeld
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := settlePledge(ctx, tx, pledge); err != nil {
+			return err
+		}
+
+		resolved = pledge
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// ExpireHolds expires every held pledge whose hold ended at or before now
+func (r *PledgeRepository) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var wishlistIDs []string
+		rows, err := tx.QueryContext(ctx, `
+			SELECT DISTINCT wishlist_id FROM pledges WHERE status = $2 AND expires_at <= $1`,
+			now, teacherwishlist.PledgeHeld)
+		if err != nil {
+			return fmt.Errorf("expire pledges: %w", err)
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("expire pledges: %w", err)
+			}
+			wishlistIDs = append(wishlistIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("expire pledges: %w", err)
+		}
+		if len(wishlistIDs) == 0 {
+			return nil
+		}
+		if err := lockWishlists(ctx, tx, wishlistIDs...); err != nil {
+			return err
+		}
+
+		// Only expire pledges on the wishlists locked above
+		rows, err = tx.QueryContext(ctx, `
+			UPDATE pledges
+			SET status = $2, resolved_at = $1
+			WHERE status = $3 AND expires_at <= $1
+			  AND wishlist_id::text = ANY (string_to_array($4, ','))
+			RETURNING `+pledgeColumns,
+			now, teacherwishlist.PledgeExpired, teacherwishlist.PledgeHeld, joinStrings(wishlistIDs))
+		if err != nil {
+			return fmt.Errorf("expire pledges: %w", err)
+		}
+
+		pledges := []*teacherwishlist.Pledge{}
+		for rows.Next() {
+			pledge, err := scanPledge(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			pledges = append(pledges, pledge)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("expire pledges: %w", err)
+		}
+
+		for _, pledge := range pledges {
+			if err := settlePledge(ctx, tx, pledge); err != nil {
+				return err
+			}
+		}
+
+		expired = len(pledges)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// settlePledge releases a resolved pledge's hold on its item, counting the units
+// as fulfilled if it was confirmed. The caller must hold the wishlist's lock.
+func settlePledge(ctx context.Context, tx *sql.Tx, pledge *teacherwishlist.Pledge) error {
+	fulfilled := 0
+	if pledge.Status == teacherwishlist.PledgeConfirmed {
+		fulfilled = pledge.Quantity
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE wishlist_items
+		SET quantity_pledged = quantity_pledged - $2, quantity_fulfilled = quantity_fulfilled + $3
+		WHERE id = $1`,
+		pledge.ItemID, pledge.Quantity, fulfilled)
+	if err != nil {
+		return fmt.Errorf("settle pledge: %w", err)
+	}
+
+	return bumpWishlistVersion(ctx, tx, pledge.WishlistID)
+}
+
+// bumpWishlistVersion increments the wishlist version after a change to its item
+// quantities, so edits based on an older copy fail with a version conflict. The
+// caller must hold the wishlist's lock.
+func bumpWishlistVersion(ctx context.Context, tx *sql.Tx, wishlistID string) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE wishlists SET version = version + 1 WHERE id = $1`, wishlistID); err != nil {
+		return fmt.Errorf("bump wishlist version: %w", err)
+	}
+	return nil
+}
+
+// lockWishlists locks the wishlist rows FOR UPDATE, in ID order. Every
+// transaction that changes item quantities locks the wishlist before its items,
+// as Update does, so pledges and wishlist edits cannot deadlock.
+func lockWishlists(ctx context.Context, tx *sql.Tx, ids ...string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM wishlists
+		WHERE id::text = ANY (string_to_array($1, ','))
+		ORDER BY id
+		FOR UPDATE`, joinStrings(ids))
+	if err != nil {
+		return fmt.Errorf("lock wishlists: %w", err)
+	}
+	defer rows.Close()
+
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("lock wishlists: %w", err)
+	}
+	if locked == 0 {
+		return fmt.Errorf("%w: %s", teacherwishlist.ErrWishlistNotFound, ids[0])
+	}
+	return nil
+}
+
+// scanPledge reads the pledgeColumns of one row
+func scanPledge(row rowScanner) (*teacherwishlist.Pledge, error) {
+	var pledge teacherwishlist.Pledge
+	var resolvedAt sql.NullTime
+	err := row.Scan(&pledge.ID, &pledge.WishlistID, &pledge.ItemID, &pledge.DonorName, &pledge.DonorEmail,
+		&pledge.Quantity, &pledge.Status, &pledge.CreatedAt, &pledge.ExpiresAt, &resolvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan pledge: %w", err)
+	}
+
+	pledge.ResolvedAt = nullTimePtr(resolvedAt)
+	return &pledge, nil
+}
+
+// scanWishlistRevision reads the wishlistRevisionColumns of one row
+func scanWishlistRevision(row rowScanner) (*teacherwishlist.WishlistRevision, error) {
+	var revision teacherwishlist.WishlistRevision
+	var items []byte
+	err := row.Scan(&revision.WishlistID, &revision.Number, &revision.Source, &revision.RestoredFrom,
+		&revision.Title, &revision.Description, &items, &revision.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan wishlist revision: %w", err)
+	}
+
+	if err := json.Unmarshal(items, &revision.Items); err != nil {
+		return nil, fmt.Errorf("decode revision items: %w", err)
+	}
+
+	return &revision, nil
+}
@@ -0,0 +1,289 @@
+-- Initial schema for all tables. Statements are idempotent so the script can be
+-- re-run against an existing database.
+
+-- Schools --------------------------------------------------------------------
+
+-- State education agencies, one per state. Boundaries are stored as the JSON
+-- encoding of domain.Polygon.
+CREATE TABLE IF NOT EXISTS state_agencies (
+    id         UUID PRIMARY KEY,
+    state      TEXT NOT NULL UNIQUE,
+    name       TEXT NOT NULL,
+    address    JSONB,
+    boundary   JSONB,
+    version    INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Districts (local education agencies)
+CREATE TABLE IF NOT EXISTS districts (
+    id              UUID PRIMARY KEY,
+    state_agency_id UUID NOT NULL REFERENCES state_agencies (id),
+    nces_id         TEXT UNIQUE,
+    name            TEXT NOT NULL,
+    address         JSONB,
+    boundary        JSONB,
+    version         INTEGER NOT NULL DEFAULT 1,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS districts_state_agency_id_idx ON districts (state_agency_id, name);
+
+-- Users who administer a district and can see all of its schools
+CREATE TABLE IF NOT EXISTS district_admins (
+    district_id UUID NOT NULL REFERENCES districts (id) ON DELETE CASCADE,
+    admin_id    TEXT NOT NULL,
+    PRIMARY KEY (district_id, admin_id)
+);
+
+CREATE TABLE IF NOT EXISTS schools (
+    id         UUID PRIMARY KEY,
+    name       TEXT NOT NULL,
+    address    JSONB,
+    version    INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Fields published by the NCES Common Core of Data and Private School Survey.
+-- Schools entered by hand have no NCES ID.
+ALTER TABLE schools
+    ADD COLUMN IF NOT EXISTS nces_id          TEXT,
+    ADD COLUMN IF NOT EXISTS sector           TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS lowest_grade     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS highest_grade    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS district_id      UUID REFERENCES districts (id),
+    ADD COLUMN IF NOT EXISTS district_nces_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS district_name    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS locale_code      TEXT NOT NULL DEFAULT '';
+
+-- Attributes donors filter on to find high-need schools. frl_percent is NULL
+-- when the share of students eligible for free or reduced-price lunch is unknown.
+ALTER TABLE schools
+    ADD COLUMN IF NOT EXISTS locale_type    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS school_type    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS enrollment     INTEGER NOT NULL DEFAULT 0 CHECK (enrollment >= 0),
+    ADD COLUMN IF NOT EXISTS title_i_status TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS frl_percent    NUMERIC(5, 2) CHECK (frl_percent BETWEEN 0 AND 100);
+
+-- A school merged into another as a duplicate is kept as an alias of the
+-- surviving school
+ALTER TABLE schools
+    ADD COLUMN IF NOT EXISTS merged_into_id UUID REFERENCES schools (id),
+    ADD COLUMN IF NOT EXISTS merged_at      TIMESTAMPTZ;
+
+-- The point of address->'location', written through domain.Location's Valuer
+-- and read through its Scanner, so radius searches can use ST_DWithin. The
+-- geohash at the highest precision serves geohash prefix searches. Both are
+-- NULL for a school without a location.
+CREATE EXTENSION IF NOT EXISTS postgis;
+ALTER TABLE schools
+    ADD COLUMN IF NOT EXISTS location geography(Point, 4326),
+    ADD COLUMN IF NOT EXISTS geohash  TEXT GENERATED ALWAYS AS (ST_GeoHash(location::geometry, 12)) STORED;
+
+UPDATE schools
+SET location = ST_SetSRID(ST_MakePoint((address->'location'->>'longitude')::double precision,
+                                       (address->'location'->>'latitude')::double precision), 4326)::geography
+WHERE location IS NULL AND address->'location' IS NOT NULL
+  AND (COALESCE((address->'location'->>'latitude')::double precision, 0) <> 0
+       OR COALESCE((address->'location'->>'longitude')::double precision, 0) <> 0
+       OR COALESCE(address->'location'->>'county', '') <> ''
+       OR COALESCE(address->'location'->>'region', '') <> '');
+
+CREATE UNIQUE INDEX IF NOT EXISTS schools_nces_id_key ON schools (nces_id) WHERE nces_id IS NOT NULL;
+-- Serves the typo-tolerant school name search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS schools_search_trgm_idx ON schools
+    USING gin ((lower(name || ' ' || COALESCE(address->>'city', ''))) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS schools_district_id_idx ON schools (district_id) WHERE district_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS schools_merged_into_id_idx ON schools (merged_into_id) WHERE merged_into_id IS NOT NULL;
+-- Serves geohash prefix searches with LIKE 'prefix%'
+CREATE INDEX IF NOT EXISTS schools_geohash_idx ON schools (geohash text_pattern_ops) WHERE merged_into_id IS NULL;
+CREATE INDEX IF NOT EXISTS schools_location_idx ON schools USING gist (location) WHERE merged_into_id IS NULL;
+
+-- Teachers -------------------------------------------------------------------
+
+CREATE TABLE IF NOT EXISTS teachers (
+    id                UUID PRIMARY KEY,
+    school_id         UUID NOT NULL REFERENCES schools (id),
+    name              TEXT NOT NULL,
+    email             TEXT NOT NULL,
+    validation_status TEXT NOT NULL DEFAULT 'unverified' CHECK (validation_status IN
+        ('unverified', 'pending_review', 'verified', 'rejected', 'suspended', 'expired')),
+    version           INTEGER NOT NULL DEFAULT 1,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS teachers_email_key ON teachers (lower(email));
+CREATE INDEX IF NOT EXISTS teachers_school_id_idx ON teachers (school_id);
+CREATE INDEX IF NOT EXISTS teachers_validation_status_idx ON teachers (validation_status, updated_at);
+
+-- Append-only log of every validation status change
+CREATE TABLE IF NOT EXISTS teacher_validation_history (
+    teacher_id  UUID NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+    seq         INTEGER NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status   TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    at          TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (teacher_id, seq)
+);
+
+-- Append-only log of every move to another school
+CREATE TABLE IF NOT EXISTS teacher_school_history (
+    teacher_id     UUID NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+    seq            INTEGER NOT NULL,
+    from_school_id UUID NOT NULL REFERENCES schools (id),
+    to_school_id   UUID NOT NULL REFERENCES schools (id),
+    actor          TEXT NOT NULL,
+    at             TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (teacher_id, seq)
+);
+
+-- Wishlists ------------------------------------------------------------------
+
+CREATE TABLE IF NOT EXISTS wishlists (
+    id            UUID PRIMARY KEY,
+    teacher_id    UUID NOT NULL REFERENCES teachers (id),
+    title         TEXT NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    grade_level   TEXT NOT NULL DEFAULT '',
+    subject       TEXT NOT NULL DEFAULT '',
+    student_count INTEGER NOT NULL DEFAULT 0 CHECK (student_count >= 0),
+    visibility    TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'unlisted')),
+    status        TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'paused', 'archived')),
+    publish_at    TIMESTAMPTZ,
+    expire_at     TIMESTAMPTZ,
+    archived_at   TIMESTAMPTZ,
+    revision      INTEGER NOT NULL DEFAULT 1,
+    version       INTEGER NOT NULL DEFAULT 1,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS wishlists_teacher_id_idx ON wishlists (teacher_id, status);
+-- Serves both the public search and the expiry job, which only look at open lists
+CREATE INDEX IF NOT EXISTS wishlists_open_expire_at_idx ON wishlists (status, expire_at)
+    WHERE status IN ('published', 'paused');
+
+-- Copy of the location of the teacher's school, kept in step by the
+-- repositories whenever a wishlist is created, a teacher changes school or a
+-- school moves, so wishlists can be searched by distance with ST_DWithin
+-- without joining teachers and schools
+ALTER TABLE wishlists
+    ADD COLUMN IF NOT EXISTS location geography(Point, 4326),
+    ADD COLUMN IF NOT EXISTS geohash  TEXT GENERATED ALWAYS AS (ST_GeoHash(location::geometry, 12)) STORED;
+
+UPDATE wishlists w
+SET location = s.location
+FROM teachers t
+JOIN schools s ON s.id = t.school_id
+WHERE t.id = w.teacher_id AND w.location IS NULL AND s.location IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS wishlists_geohash_idx ON wishlists (geohash text_pattern_ops)
+    WHERE status IN ('published', 'paused');
+CREATE INDEX IF NOT EXISTS wishlists_location_idx ON wishlists USING gist (location)
+    WHERE status IN ('published', 'paused');
+
+-- The wishlist featured on a teacher's public profile. Added here because
+-- wishlists reference teachers.
+ALTER TABLE teachers ADD COLUMN IF NOT EXISTS primary_wishlist_id UUID
+    REFERENCES wishlists (id) ON DELETE SET NULL;
+
+-- Shared catalog of canonical supply items that wishlist items link to
+CREATE TABLE IF NOT EXISTS catalog_items (
+    id                  UUID PRIMARY KEY,
+    name                TEXT NOT NULL,
+    category            TEXT NOT NULL DEFAULT 'other',
+    typical_price_cents BIGINT NOT NULL DEFAULT 0 CHECK (typical_price_cents >= 0),
+    aliases             JSONB NOT NULL DEFAULT '[]',
+    version             INTEGER NOT NULL DEFAULT 1,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS catalog_items_name_key ON catalog_items (lower(name));
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id                 UUID PRIMARY KEY,
+    wishlist_id        UUID NOT NULL REFERENCES wishlists (id) ON DELETE CASCADE,
+    position           INTEGER NOT NULL,
+    name               TEXT NOT NULL,
+    quantity_requested INTEGER NOT NULL CHECK (quantity_requested > 0),
+    quantity_fulfilled INTEGER NOT NULL DEFAULT 0 CHECK (quantity_fulfilled >= 0),
+    quantity_pledged   INTEGER NOT NULL DEFAULT 0 CHECK (quantity_pledged >= 0),
+    unit_price_cents   BIGINT NOT NULL DEFAULT 0 CHECK (unit_price_cents >= 0),
+    priority           TEXT NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high')),
+    category           TEXT NOT NULL DEFAULT 'other',
+    product_url        TEXT NOT NULL DEFAULT '',
+    catalog_item_id    UUID REFERENCES catalog_items (id) ON DELETE SET NULL,
+    CHECK (quantity_fulfilled + quantity_pledged <= quantity_requested)
+);
+
+CREATE INDEX IF NOT EXISTS wishlist_items_wishlist_id_idx ON wishlist_items (wishlist_id, position);
+CREATE INDEX IF NOT EXISTS wishlist_items_catalog_item_id_idx ON wishlist_items (catalog_item_id)
+    WHERE catalog_item_id IS NOT NULL;
+
+-- Immutable snapshots of each edit to a wishlist's title, description and items
+CREATE TABLE IF NOT EXISTS wishlist_revisions (
+    wishlist_id   UUID NOT NULL REFERENCES wishlists (id) ON DELETE CASCADE,
+    number        INTEGER NOT NULL,
+    source        TEXT NOT NULL CHECK (source IN ('created', 'updated', 'restored')),
+    restored_from INTEGER NOT NULL DEFAULT 0,
+    title         TEXT NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    items         JSONB NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (wishlist_id, number)
+);
+
+-- Donor pledges hold units of an item until the teacher confirms delivery or the
+-- hold expires
+CREATE TABLE IF NOT EXISTS pledges (
+    id          UUID PRIMARY KEY,
+    wishlist_id UUID NOT NULL REFERENCES wishlists (id) ON DELETE CASCADE,
+    item_id     UUID NOT NULL,
+    donor_name  TEXT NOT NULL,
+    donor_email TEXT NOT NULL,
+    quantity    INTEGER NOT NULL CHECK (quantity > 0),
+    status      TEXT NOT NULL CHECK (status IN ('held', 'confirmed', 'cancelled', 'expired')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS pledges_wishlist_id_idx ON pledges (wishlist_id, created_at);
+CREATE INDEX IF NOT EXISTS pledges_held_expires_at_idx ON pledges (expires_at) WHERE status = 'held';
+
+-- Admin ----------------------------------------------------------------------
+
+-- One row per run of a bulk import from a file on the server
+CREATE TABLE IF NOT EXISTS bulk_import_jobs (
+    id          UUID PRIMARY KEY,
+    kind        TEXT NOT NULL CHECK (kind IN ('nces_schools')),
+    source_path TEXT NOT NULL,
+    status      TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    started_by  TEXT NOT NULL,
+    created     INTEGER NOT NULL DEFAULT 0,
+    updated     INTEGER NOT NULL DEFAULT 0,
+    unchanged   INTEGER NOT NULL DEFAULT 0,
+    skipped     JSONB NOT NULL DEFAULT '[]',
+    error       TEXT NOT NULL DEFAULT '',
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ
+);
+
+-- One row per run of the job that looks for duplicate schools
+CREATE TABLE IF NOT EXISTS school_duplicate_scans (
+    id          UUID PRIMARY KEY,
+    status      TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    started_by  TEXT NOT NULL,
+    duplicates  JSONB NOT NULL DEFAULT '[]',
+    error       TEXT NOT NULL DEFAULT '',
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ
+);